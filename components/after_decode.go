@@ -0,0 +1,36 @@
+package components
+
+import (
+	"context"
+	"fmt"
+)
+
+// AfterDecoder is an optional interface that components can implement to
+// normalize or sanitize their decoded form values, e.g. trimming whitespace or
+// lower-casing an email address. AfterDecode is called immediately after form
+// decoding (and after `default` tags and the registry's SetPostDecode hook have
+// been applied), before request headers are applied and before Authorize, Init,
+// Validate, event handlers and Process, so all of them see the normalized values.
+// Keep defaults and data loading in Init.
+//
+// If AfterDecode returns an error, the request fails with a 400 Decode Error.
+//
+// Example:
+//
+//	func (c *SignupForm) AfterDecode(ctx context.Context) error {
+//	    c.Email = strings.ToLower(strings.TrimSpace(c.Email))
+//	    return nil
+//	}
+type AfterDecoder interface {
+	AfterDecode(ctx context.Context) error
+}
+
+// afterDecode calls AfterDecode if the component implements AfterDecoder.
+func afterDecode(ctx context.Context, instance any) error {
+	if v, ok := instance.(AfterDecoder); ok {
+		if err := v.AfterDecode(ctx); err != nil {
+			return fmt.Errorf("AfterDecode failed: %w", err)
+		}
+	}
+	return nil
+}
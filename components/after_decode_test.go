@@ -0,0 +1,76 @@
+package components_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TestSignupComponent struct {
+	Email  string   `form:"email"`
+	Lookup string   `json:"-"`
+	Calls  []string `json:"-"`
+}
+
+func (c *TestSignupComponent) AfterDecode(ctx context.Context) error {
+	c.Calls = append(c.Calls, "AfterDecode")
+	if c.Email == "" {
+		return errors.New("email is required")
+	}
+	c.Email = strings.ToLower(strings.TrimSpace(c.Email))
+	return nil
+}
+
+func (c *TestSignupComponent) Init(ctx context.Context) error {
+	c.Calls = append(c.Calls, "Init")
+	c.Lookup = "user:" + c.Email
+	return nil
+}
+
+func (c *TestSignupComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "%s %v", c.Lookup, c.Calls)
+	return nil
+}
+
+func TestAfterDecode(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSignupComponent](registry, "signup")
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/signup", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("signup")(w, req)
+		return w
+	}
+
+	t.Run("runs before Init, which reads the normalized value", func(t *testing.T) {
+		w := post("email=+Alice@Example.COM+")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "user:alice@example.com [AfterDecode Init]", w.Body.String())
+	})
+
+	t.Run("an error fails the request with a 400", func(t *testing.T) {
+		w := post("email=")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "email is required")
+	})
+
+	t.Run("SimulateRequest calls AfterDecode", func(t *testing.T) {
+		signup := &TestSignupComponent{}
+		err := components.SimulateRequest(context.Background(), signup, http.MethodPost, url.Values{"email": {" Bob@Example.com"}}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "user:bob@example.com", signup.Lookup)
+		assert.Equal(t, []string{"AfterDecode", "Init"}, signup.Calls)
+	})
+}
@@ -0,0 +1,21 @@
+package components
+
+import "context"
+
+// AfterRenderHandler is an optional interface that components can implement to run
+// logic after the component has been rendered successfully. This is useful for
+// metrics, cache warming, or cleanup.
+//
+// AfterRender is called once Render succeeds and the response has been written.
+// Since the response has already been sent, an error returned from AfterRender is
+// logged but does not change the response. It is not called when a cached or
+// replayed response is served, or when Render fails.
+//
+// Example:
+//
+//	func (c *ReportComponent) AfterRender(ctx context.Context) error {
+//	    return c.metrics.RecordView(ctx, c.ReportID)
+//	}
+type AfterRenderHandler interface {
+	AfterRender(ctx context.Context) error
+}
@@ -0,0 +1,27 @@
+package components
+
+import "context"
+
+// Authorizer is an optional interface that components can implement to declare
+// authorization logic separately from Process.
+//
+// Authorize is called after form data is decoded and request headers are applied,
+// but before Init, event handlers, and Process. If it returns an error, the request
+// is rejected with a 403 Forbidden error. Return an *ErrUnauthorized to reject with
+// a 401 Unauthorized instead (e.g. when no user is signed in).
+//
+// Example:
+//
+//	func (c *AdminPanel) Authorize(ctx context.Context) error {
+//	    user, ok := auth.UserFromContext(ctx)
+//	    if !ok {
+//	        return &components.ErrUnauthorized{Reason: "sign in required"}
+//	    }
+//	    if !user.IsAdmin {
+//	        return fmt.Errorf("user %s is not an administrator", user.Name)
+//	    }
+//	    return nil
+//	}
+type Authorizer interface {
+	Authorize(ctx context.Context) error
+}
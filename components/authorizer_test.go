@@ -0,0 +1,79 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+type testUserKey struct{}
+
+// TestAdminComponent only allows requests from the "admin" user
+type TestAdminComponent struct {
+	Count   int  `form:"count"`
+	Changed bool `json:"-"`
+}
+
+func (c *TestAdminComponent) Authorize(ctx context.Context) error {
+	user, ok := ctx.Value(testUserKey{}).(string)
+	if !ok {
+		return &components.ErrUnauthorized{Reason: "sign in required"}
+	}
+	if user != "admin" {
+		return fmt.Errorf("user %s is not an administrator", user)
+	}
+	return nil
+}
+
+func (c *TestAdminComponent) OnIncrement(ctx context.Context) error {
+	c.Count++
+	c.Changed = true
+	return nil
+}
+
+func (c *TestAdminComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>Admin count: %d</div>", c.Count)
+	return nil
+}
+
+func TestAuthorizer(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestAdminComponent](registry, "admin")
+
+	request := func(user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/admin", strings.NewReader("count=1&hxc-event=increment"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if user != "" {
+			req = req.WithContext(context.WithValue(req.Context(), testUserKey{}, user))
+		}
+		w := httptest.NewRecorder()
+		registry.HandlerFor("admin")(w, req)
+		return w
+	}
+
+	t.Run("allows authorized user", func(t *testing.T) {
+		w := request("admin")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Admin count: 2")
+	})
+
+	t.Run("denies other users with 403 before the event runs", func(t *testing.T) {
+		w := request("guest")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "guest is not an administrator")
+		assert.NotContains(t, w.Body.String(), "Admin count")
+	})
+
+	t.Run("ErrUnauthorized yields 401", func(t *testing.T) {
+		w := request("")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "sign in required")
+	})
+}
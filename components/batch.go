@@ -0,0 +1,308 @@
+package components
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strconv"
+	"time"
+)
+
+// DefaultMaxBatchSize is the default limit on the number of components in one
+// BatchHandler request.
+const DefaultMaxBatchSize = 20
+
+// BatchItem is one component requested from BatchHandler.
+type BatchItem struct {
+	// Name is the registered component name.
+	Name string `json:"name"`
+	// Values are decoded into the component as if they were form data.
+	Values url.Values `json:"values"`
+	// Target is the CSS selector of the element whose content the fragment
+	// replaces, e.g. "#cart-summary".
+	Target string `json:"target"`
+}
+
+// SetMaxBatchSize limits how many components a single BatchHandler request may
+// render (default 20). Larger batches are rejected with 400 Bad Request. Pass
+// n <= 0 to remove the limit.
+func (r *Registry) SetMaxBatchSize(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxBatchSize = n
+}
+
+// BatchHandler renders several components in one round trip, e.g. to refresh the
+// widgets of a dashboard together. It accepts a POST whose JSON body is a list of
+// BatchItem:
+//
+//	[{"name": "cart-summary", "values": {"currency": ["EUR"]}, "target": "#cart-summary"},
+//	 {"name": "notifications", "target": "#notifications"}]
+//
+// Each item is served as a request for its component would be: it passes through
+// the middleware of the component's group and is subject to the component's rate
+// limit, WithStrictFields, timeout and panic recovery. The component is then built
+// as RenderComponent does (decode, Init, Validate, Process, no events) after its
+// Authorize check, with its locale, RequestInfo and StatefulComponent or
+// SignedStateComponent state handled as in HandlerFor, and the response holds one
+// out-of-band fragment per item, in order:
+//
+//	<div hx-swap-oob="innerHTML:#cart-summary">...</div>
+//
+// so the triggering element should use hx-swap="none". If any component fails or
+// panics, the whole batch fails with that component's error; if group middleware
+// answers an item itself (e.g. with a 403), its response is returned for the whole
+// batch. The request body is subject to SetMaxBodySize and the item count to
+// SetMaxBatchSize. If CSRF protection is enabled, the token must be sent in the
+// header.
+//
+//	router.Post("/component-batch", registry.BatchHandler)
+func (r *Registry) BatchHandler(w http.ResponseWriter, req *http.Request) {
+	logger := r.logger()
+
+	if req.Method != http.MethodPost {
+		r.renderError(w, req, "Method Not Allowed", fmt.Sprintf("Method %s is not allowed", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.checkCSRF(req, false); err != nil {
+		r.renderError(w, req, "Forbidden", err.Error(), http.StatusForbidden)
+		return
+	}
+
+	r.mu.RLock()
+	maxBodySize := r.maxBodySize
+	maxBatchSize := r.maxBatchSize
+	prefix := r.prefix
+	r.mu.RUnlock()
+
+	if maxBodySize > 0 && req.Body != nil {
+		req.Body = http.MaxBytesReader(w, req.Body, maxBodySize)
+	}
+	var items []BatchItem
+	if err := json.NewDecoder(req.Body).Decode(&items); err != nil {
+		r.renderError(w, req, "Bad Request", fmt.Sprintf("Failed to parse batch: %v", err), http.StatusBadRequest)
+		return
+	}
+	if maxBatchSize > 0 && len(items) > maxBatchSize {
+		r.renderError(w, req, "Bad Request", fmt.Sprintf("Batch of %d components exceeds the limit of %d", len(items), maxBatchSize), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	for i, item := range items {
+		if !isValidComponentName(item.Name) {
+			r.renderError(w, req, "Bad Request", fmt.Sprintf("Batch item %d has an invalid component name %q", i, item.Name), http.StatusBadRequest)
+			return
+		}
+		if item.Target == "" {
+			r.renderComponentError(w, req, item.Name, nil, "Bad Request", fmt.Sprintf("Batch item %d (%s) has no target", i, item.Name), http.StatusBadRequest)
+			return
+		}
+
+		// Serve the item through its group's middleware, which may answer it
+		// itself instead of passing it on
+		var err error
+		served := false
+		itemResp := &batchItemResponse{header: make(http.Header)}
+		r.groupMiddleware(item.Name, func(_ http.ResponseWriter, itemReq *http.Request) {
+			served = true
+			err = r.renderBatchItem(itemReq, item, &buf)
+		})(itemResp, batchItemRequest(req, prefix, item))
+		if !served {
+			logger.Warn("batch item answered by group middleware",
+				"component", item.Name,
+				"status", itemResp.status())
+			itemResp.writeTo(w)
+			return
+		}
+		var panicked *renderPanic
+		if errors.As(err, &panicked) {
+			r.handlePanic(w, req, item.Name, panicked, nil, nil)
+			return
+		}
+		if err != nil {
+			title, operation, code := batchErrorStatus(err)
+			logger.Error("batch component error",
+				"component", item.Name,
+				"operation", operation,
+				"error", err)
+			var limited *batchRateLimitError
+			if errors.As(err, &limited) {
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(limited.wait)))
+			} else {
+				r.reportError(req.Context(), item.Name, operation, err, code)
+			}
+			r.renderComponentError(w, req, item.Name, err, title, capitalize(err.Error()), code)
+			return
+		}
+		for name, values := range itemResp.header {
+			w.Header()[name] = values
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(w); err != nil {
+		logger.Error("failed to write batch response",
+			"error", err)
+	}
+}
+
+// renderBatchItem builds a batch item's component and renders it into buf as an
+// out-of-band fragment, enforcing the component's rate limit and timeout and
+// exposing the request details and locale as HandlerFor does. Unless the component
+// was registered WithRecover(false), a panic is returned as a *renderPanic.
+func (r *Registry) renderBatchItem(req *http.Request, item BatchItem, buf *bytes.Buffer) (err error) {
+	r.mu.RLock()
+	entry, exists := r.components[item.Name]
+	timeout := r.timeoutFor(entry)
+	localeConfig := r.locale
+	r.mu.RUnlock()
+
+	if !entry.noRecover {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = &renderPanic{value: recovered, stack: debug.Stack()}
+			}
+		}()
+	}
+
+	if exists {
+		if allowed, wait := r.checkRateLimit(entry, item.Name, req); !allowed {
+			return &batchRateLimitError{wait: wait}
+		}
+	}
+
+	info := RequestInfo{ComponentName: item.Name, Method: req.Method}
+	ctx := withLocale(withRequestInfo(req.Context(), info), resolveLocale(req, localeConfig))
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	component, err := r.buildComponent(ctx, item.Name, item.Values, req)
+	if err != nil {
+		return err
+	}
+	// Don't render results computed after the timeout expired
+	if deadlineExceeded(ctx) {
+		return &ComponentError{ComponentName: item.Name, Operation: "process", Err: ctx.Err()}
+	}
+	fmt.Fprintf(buf, `<div hx-swap-oob="innerHTML:%s">`, html.EscapeString(item.Target))
+	err = renderWithOutOfBand(ctx, buf, component, component)
+	buf.WriteString("</div>")
+	return err
+}
+
+// batchItemRequest returns the request a batch item is served with: the batch
+// request addressed to the component's URL, with the item's values as its form.
+func batchItemRequest(req *http.Request, prefix string, item BatchItem) *http.Request {
+	itemReq := req.Clone(req.Context())
+	itemReq.URL.Path = prefix + item.Name
+	itemReq.URL.RawPath = ""
+	itemReq.Body = http.NoBody
+	itemReq.ContentLength = 0
+	itemReq.Form = item.Values
+	itemReq.PostForm = item.Values
+	return itemReq
+}
+
+// batchItemResponse records the response of group middleware serving a batch
+// item: the headers it sets, and the response it writes if it answers the item
+// itself.
+type batchItemResponse struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (b *batchItemResponse) Header() http.Header { return b.header }
+
+func (b *batchItemResponse) Write(p []byte) (int, error) {
+	if b.code == 0 {
+		b.code = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+func (b *batchItemResponse) WriteHeader(code int) {
+	if b.code == 0 {
+		b.code = code
+	}
+}
+
+// status returns the recorded status code, 200 if none was written.
+func (b *batchItemResponse) status() int {
+	if b.code == 0 {
+		return http.StatusOK
+	}
+	return b.code
+}
+
+// writeTo copies the recorded response to w.
+func (b *batchItemResponse) writeTo(w http.ResponseWriter) {
+	for name, values := range b.header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(b.status())
+	_, _ = b.body.WriteTo(w)
+}
+
+// batchRateLimitError is returned for a batch item over its component's rate limit.
+type batchRateLimitError struct {
+	wait time.Duration
+}
+
+func (e *batchRateLimitError) Error() string {
+	return "rate limit exceeded, please try again later"
+}
+
+// batchErrorStatus maps an error from building or rendering a batch item to a
+// response title, the failed lifecycle operation and a status code.
+func batchErrorStatus(err error) (title, operation string, code int) {
+	var limited *batchRateLimitError
+	if errors.As(err, &limited) {
+		return "Too Many Requests", "ratelimit", http.StatusTooManyRequests
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		operation = "process"
+		var componentErr *ComponentError
+		if errors.As(err, &componentErr) {
+			operation = componentErr.Operation
+		}
+		return "Gateway Timeout", operation, http.StatusGatewayTimeout
+	}
+	var notFound *ErrComponentNotFound
+	if errors.As(err, &notFound) {
+		return "Component Not Found", "lookup", http.StatusNotFound
+	}
+	var disabled *ErrComponentDisabled
+	if errors.As(err, &disabled) {
+		return "Service Unavailable", "lookup", http.StatusServiceUnavailable
+	}
+	var componentErr *ComponentError
+	if !errors.As(err, &componentErr) {
+		return "Render Error", "render", http.StatusInternalServerError
+	}
+	switch componentErr.Operation {
+	case "decode":
+		return "Decode Error", "decode", http.StatusBadRequest
+	case "authorize":
+		var unauthorized *ErrUnauthorized
+		if errors.As(err, &unauthorized) {
+			return "Unauthorized", "authorize", http.StatusUnauthorized
+		}
+		return "Forbidden", "authorize", http.StatusForbidden
+	case "state":
+		title, _, code := stateFailure(componentErr.Err)
+		return title, "state", code
+	}
+	return "Batch Error", componentErr.Operation, http.StatusInternalServerError
+}
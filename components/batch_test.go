@@ -0,0 +1,184 @@
+package components_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchHandler(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSimpleCounter](registry, "counter")
+	components.Register[*TestDefaultsSearchComponent](registry, "search")
+	components.Register[*TestAdminComponent](registry, "admin")
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component-batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		registry.BatchHandler(w, req)
+		return w
+	}
+
+	t.Run("renders each component as an out-of-band fragment", func(t *testing.T) {
+		w := post(`[
+			{"name": "counter", "values": {"count": ["3"]}, "target": "#counter"},
+			{"name": "search", "values": {"q": ["go"]}, "target": "#search-results"}
+		]`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t,
+			`<div hx-swap-oob="innerHTML:#counter"><div>3</div></div>`+
+				`<div hx-swap-oob="innerHTML:#search-results">go|5|relevance|true|docs+blog|0.5</div>`,
+			w.Body.String())
+	})
+
+	t.Run("rejects batches over the limit", func(t *testing.T) {
+		registry.SetMaxBatchSize(1)
+		defer registry.SetMaxBatchSize(components.DefaultMaxBatchSize)
+
+		w := post(`[{"name": "counter", "target": "#a"}, {"name": "counter", "target": "#b"}]`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "exceeds the limit of 1")
+	})
+
+	t.Run("fails the batch for an unknown component", func(t *testing.T) {
+		w := post(`[{"name": "counter", "target": "#a"}, {"name": "missing", "target": "#b"}]`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.NotContains(t, w.Body.String(), "hx-swap-oob")
+	})
+
+	t.Run("runs Authorize", func(t *testing.T) {
+		w := post(`[{"name": "admin", "target": "#admin"}]`)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("requires a target and POST", func(t *testing.T) {
+		w := post(`[{"name": "counter"}]`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		registry.BatchHandler(w, httptest.NewRequest(http.MethodGet, "/component-batch", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestBatchHandlerAppliesComponentPolicies(t *testing.T) {
+	registry := components.NewRegistry()
+	admin := registry.Group("admin")
+	admin.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("X-Role") != "admin" {
+				http.Error(w, "admins only", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), testUserKey{}, "admin")))
+		})
+	})
+	components.RegisterIn[*TestSimpleCounter](admin, "users")
+	components.RegisterIn[*TestAdminComponent](admin, "settings")
+	components.Register[*TestSimpleCounter](registry, "limited", components.WithRateLimit(0.001, 1))
+	components.Register[*TestSimpleCounter](registry, "strict", components.WithStrictFields())
+	components.Register[*TestSlowComponent](registry, "slow", components.WithTimeout(20*time.Millisecond))
+
+	post := func(body string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component-batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		w := httptest.NewRecorder()
+		registry.BatchHandler(w, req)
+		return w
+	}
+
+	t.Run("group middleware can block an item", func(t *testing.T) {
+		w := post(`[{"name": "admin/users", "values": {"count": ["7"]}, "target": "#users"}]`, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "admins only\n", w.Body.String())
+	})
+
+	t.Run("group middleware context reaches the component", func(t *testing.T) {
+		w := post(`[{"name": "admin/users", "values": {"count": ["7"]}, "target": "#users"}, {"name": "admin/settings", "target": "#settings"}]`,
+			http.Header{"X-Role": {"admin"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `<div hx-swap-oob="innerHTML:#users"><div>7</div></div>`)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		w := post(`[{"name": "limited", "target": "#a"}, {"name": "limited", "target": "#b"}]`, nil)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	})
+
+	t.Run("strict fields", func(t *testing.T) {
+		w := post(`[{"name": "strict", "values": {"count": ["1"], "bogus": ["1"]}, "target": "#a"}]`, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "bogus")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		w := post(`[{"name": "slow", "values": {"delay": ["1000"], "honor": ["true"]}, "target": "#a"}]`, nil)
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	})
+}
+
+func TestBatchHandlerRunsHandlerPhases(t *testing.T) {
+	registry := components.NewRegistry()
+	registry.SetStateKey([]byte("0123456789abcdef0123456789abcdef"))
+	components.Register[*TestPlainPanickingComponent](registry, "plain")
+	components.Register[*TestPlainPanickingComponent](registry, "unrecovered", components.WithRecover(false))
+	components.Register[*TestSignedCartComponent](registry, "cart")
+	components.Register[*TestGreetingLocaleComponent](registry, "greeting")
+	components.Register[*TestInfoComponent](registry, "info")
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component-batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", "de")
+		w := httptest.NewRecorder()
+		registry.BatchHandler(w, req)
+		return w
+	}
+
+	t.Run("recovers a panicking item", func(t *testing.T) {
+		w := post(`[{"name": "greeting", "target": "#a"}, {"name": "plain", "target": "#b"}]`)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotContains(t, w.Body.String(), "hx-swap-oob")
+	})
+
+	t.Run("WithRecover(false) re-panics", func(t *testing.T) {
+		assert.PanicsWithValue(t, "boom", func() {
+			post(`[{"name": "unrecovered", "target": "#a"}]`)
+		})
+	})
+
+	t.Run("verifies and signs state", func(t *testing.T) {
+		token, err := registry.SignState("cart", map[string]any{"items": []string{"apple"}, "total": 1})
+		require.NoError(t, err)
+
+		w := post(`[{"name": "cart", "values": {"state": ["` + token + `"]}, "target": "#cart"}]`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `<div hx-swap-oob="innerHTML:#cart">1 apple|`)
+		assert.NotContains(t, w.Body.String(), "1 apple|</div>")
+
+		w = post(`[{"name": "cart", "values": {"state": ["` + token + `x"]}, "target": "#cart"}]`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("applies the locale and request info", func(t *testing.T) {
+		w := post(`[{"name": "greeting", "target": "#a"}, {"name": "info", "target": "#b"}]`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t,
+			`<div hx-swap-oob="innerHTML:#a">de|de</div>`+
+				`<div hx-swap-oob="innerHTML:#b">true info POST </div>`,
+			w.Body.String())
+	})
+}
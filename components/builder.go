@@ -0,0 +1,160 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/a-h/templ"
+)
+
+// ComponentBuilder registers a component from a factory function instead of a type
+// parameter. It is returned by Registry.New.
+type ComponentBuilder struct {
+	registry *Registry
+	name     string
+	group    *Group
+}
+
+// New starts registering a component under name. Complete the registration with
+// From, which takes a factory returning a new instance for each request:
+//
+//	registry.New("search").From(func() templ.Component { return &search.SearchComponent{} })
+//
+// Unlike Register, the component type is not a type parameter, so registrations can
+// be driven by a slice, map or config (see Registry.RegisterAll and the
+// package-level RegisterAll).
+func (r *Registry) New(name string) *ComponentBuilder {
+	return &ComponentBuilder{registry: r, name: name}
+}
+
+// From registers the component built by factory, which must return a new pointer to
+// a struct implementing templ.Component on every call, since the instance is mutated
+// during the request. Each request is decoded into a fresh instance from factory, so
+// fields it sets act as defaults (like WithInitialState).
+//
+// From panics if the name is empty or already registered, or if factory returns nil
+// or a value that is not a pointer to a struct.
+func (b *ComponentBuilder) From(factory func() templ.Component, opts ...RegisterOption) {
+	c, err := factoryComponent(b.name, factory, b.group, opts)
+	if err != nil {
+		panic(err.Error())
+	}
+	b.registry.register(c.name, c.entry, c.opts)
+}
+
+// factoryComponent checks a component name and factory and returns the component
+// to add for them.
+func factoryComponent(name string, factory func() templ.Component, group *Group, opts []RegisterOption) (pendingComponent, error) {
+	ptrType, err := factoryType(name, factory)
+	if err != nil {
+		return pendingComponent{}, err
+	}
+	return pendingComponent{
+		name: name,
+		entry: componentEntry{
+			structType:   ptrType.Elem(),
+			initialState: func() any { return factory() },
+			renderable:   true,
+			group:        group,
+		},
+		opts: opts,
+	}, nil
+}
+
+// factoryType checks a component name and factory for From and returns the
+// pointer-to-struct type the factory builds.
+func factoryType(name string, factory func() templ.Component) (reflect.Type, error) {
+	if name == "" {
+		return nil, errors.New("component name cannot be empty")
+	}
+	if factory == nil {
+		return nil, fmt.Errorf("component factory cannot be nil (component name: %s)", name)
+	}
+
+	sample := factory()
+	if sample == nil {
+		return nil, fmt.Errorf("component factory returned nil (component name: %s)", name)
+	}
+	ptrType := reflect.TypeOf(sample)
+	if ptrType.Kind() != reflect.Ptr || ptrType.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf(
+			"component factory must return a pointer to a struct, got %T (component name: %s)",
+			sample, name)
+	}
+	return ptrType, nil
+}
+
+// RegisterAll registers every component in factories, keyed by name, applying opts
+// to each. Components are registered in name order; see ComponentBuilder.From for
+// the requirements on each factory. Like Register it panics on an invalid
+// component, but every component is checked first, so on panic none are
+// registered. Use the package-level RegisterAll to get an error instead.
+//
+//	registry.RegisterAll(map[string]func() templ.Component{
+//	    "search":  func() templ.Component { return &search.SearchComponent{} },
+//	    "counter": func() templ.Component { return &counter.CounterComponent{} },
+//	})
+func (r *Registry) RegisterAll(factories map[string]func() templ.Component, opts ...RegisterOption) {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pending := make([]pendingComponent, len(names))
+	for i, name := range names {
+		c, err := factoryComponent(name, factories[name], nil, opts)
+		if err != nil {
+			panic(err.Error())
+		}
+		pending[i] = c
+	}
+	if _, err := r.addComponents(pending); err != nil {
+		panic(err.Error())
+	}
+}
+
+// Registration describes a component to register with RegisterAll, so a plugin
+// package can export its components as a list:
+//
+//	var Components = []components.Registration{
+//	    {Name: "search", New: func() templ.Component { return &SearchComponent{} }},
+//	    {Name: "counter", New: func() templ.Component { return &CounterComponent{} }},
+//	}
+type Registration struct {
+	Name string
+	// New returns a new pointer to a struct implementing templ.Component on every
+	// call, as for ComponentBuilder.From.
+	New func() templ.Component
+}
+
+// RegisterAll registers each Registration in order:
+//
+//	for _, plugin := range plugins {
+//	    if err := components.RegisterAll(registry, plugin.Components...); err != nil {
+//	        log.Fatalf("loading plugin %s: %v", plugin.Name, err)
+//	    }
+//	}
+//
+// Unlike Register and Registry.RegisterAll it returns an error instead of
+// panicking when a registration is invalid: an empty name, a name used twice,
+// already registered or differing only by case (with SetCaseInsensitiveNames), a
+// factory that is nil or does not return a pointer to a struct, or a malformed
+// `default` tag. Every registration is checked and added under the registry's
+// lock, so on error none are registered.
+func RegisterAll(r *Registry, regs ...Registration) error {
+	pending := make([]pendingComponent, len(regs))
+	for i, reg := range regs {
+		c, err := factoryComponent(reg.Name, reg.New, nil, nil)
+		if err != nil {
+			return fmt.Errorf("registration %d: %w", i, err)
+		}
+		pending[i] = c
+	}
+	if i, err := r.addComponents(pending); err != nil {
+		return fmt.Errorf("registration %d: %w", i, err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package components_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterAll(t *testing.T) {
+	registry := components.NewRegistry()
+	registry.RegisterAll(map[string]func() templ.Component{
+		"counter": func() templ.Component { return &TestSimpleCounter{} },
+		"paged":   func() templ.Component { return &TestPagedComponent{Limit: 10} },
+		"after":   func() templ.Component { return &TestAfterRenderComponent{} },
+	})
+
+	assert.Equal(t, []string{"after", "counter", "paged"}, registry.ListComponents())
+
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+	}{
+		{"counter", "/component/counter?count=4", "<div>4</div>"},
+		{"paged", "/component/paged?q=go", "<div>go limit=10</div>"},
+		{"after", "/component/after", "<div>rendered</div>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			w := httptest.NewRecorder()
+			registry.Handler(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expected, w.Body.String())
+		})
+	}
+
+	t.Run("each request gets a fresh instance", func(t *testing.T) {
+		registry.Handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/component/paged?limit=50", nil))
+
+		w := httptest.NewRecorder()
+		registry.Handler(w, httptest.NewRequest(http.MethodGet, "/component/paged", nil))
+		assert.Equal(t, "<div> limit=10</div>", w.Body.String())
+	})
+}
+
+func TestComponentBuilderValidation(t *testing.T) {
+	registry := components.NewRegistry()
+	registry.New("counter").From(func() templ.Component { return &TestSimpleCounter{} })
+
+	assert.PanicsWithValue(t, "component 'counter' already registered", func() {
+		registry.New("counter").From(func() templ.Component { return &TestSimpleCounter{} })
+	})
+	assert.PanicsWithValue(t, "component factory returned nil (component name: nil)", func() {
+		registry.New("nil").From(func() templ.Component { return nil })
+	})
+	assert.Panics(t, func() {
+		registry.New("func").From(func() templ.Component { return templ.Raw("<div></div>") })
+	})
+	assert.PanicsWithValue(t, "component name cannot be empty", func() {
+		registry.New("").From(func() templ.Component { return &TestSimpleCounter{} })
+	})
+}
+
+// TestValueComponent implements templ.Component on a value receiver, so a factory
+// can return it without a pointer
+type TestValueComponent struct{}
+
+func (c TestValueComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, "<div>value</div>")
+	return err
+}
+
+func TestRegisterAllRegistrations(t *testing.T) {
+	t.Run("registers and serves each component", func(t *testing.T) {
+		registry := components.NewRegistry()
+		err := components.RegisterAll(registry,
+			components.Registration{Name: "counter", New: func() templ.Component { return &TestSimpleCounter{} }},
+			components.Registration{Name: "paged", New: func() templ.Component { return &TestPagedComponent{Limit: 10} }},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"counter", "paged"}, registry.ListComponents())
+
+		for target, expected := range map[string]string{
+			"/component/counter?count=4": "<div>4</div>",
+			"/component/paged?q=go":      "<div>go limit=10</div>",
+		} {
+			w := httptest.NewRecorder()
+			registry.Handler(w, httptest.NewRequest(http.MethodGet, target, nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, expected, w.Body.String())
+		}
+	})
+
+	t.Run("non-pointer factory result fails without registering anything", func(t *testing.T) {
+		registry := components.NewRegistry()
+		err := components.RegisterAll(registry,
+			components.Registration{Name: "counter", New: func() templ.Component { return &TestSimpleCounter{} }},
+			components.Registration{Name: "value", New: func() templ.Component { return TestValueComponent{} }},
+		)
+		assert.EqualError(t, err, "registration 1: component factory must return a pointer to a struct, got components_test.TestValueComponent (component name: value)")
+		assert.Empty(t, registry.ListComponents())
+	})
+
+	t.Run("invalid registrations", func(t *testing.T) {
+		registry := components.NewRegistry()
+		registry.New("counter").From(func() templ.Component { return &TestSimpleCounter{} })
+		factory := func() templ.Component { return &TestSimpleCounter{} }
+
+		tests := []struct {
+			name     string
+			regs     []components.Registration
+			expected string
+		}{
+			{"empty name", []components.Registration{{New: factory}}, "registration 0: component name cannot be empty"},
+			{"nil factory", []components.Registration{{Name: "nil"}}, "registration 0: component factory cannot be nil (component name: nil)"},
+			{"already registered", []components.Registration{{Name: "counter", New: factory}}, "registration 0: component 'counter' already registered"},
+			{"duplicate in list", []components.Registration{{Name: "a", New: factory}, {Name: "a", New: factory}}, "registration 1: component 'a' already registered"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.EqualError(t, components.RegisterAll(registry, tt.regs...), tt.expected)
+			})
+		}
+		assert.Equal(t, []string{"counter"}, registry.ListComponents())
+	})
+
+	t.Run("errors that Register panics on are returned", func(t *testing.T) {
+		registry := components.NewRegistry()
+		registry.SetCaseInsensitiveNames(true)
+		registry.New("counter").From(func() templ.Component { return &TestSimpleCounter{} })
+		factory := func() templ.Component { return &TestSimpleCounter{} }
+
+		tests := []struct {
+			name     string
+			regs     []components.Registration
+			expected string
+		}{
+			{"malformed default tag", []components.Registration{
+				{Name: "ok", New: factory},
+				{Name: "bad", New: func() templ.Component { return &TestBadDefaultComponent{} }},
+			}, "registration 1: component 'bad': "},
+			{"differs only by case", []components.Registration{{Name: "Counter", New: factory}}, "registration 0: component 'Counter' differs only by case from registered component 'counter'"},
+			{"differs only by case in list", []components.Registration{{Name: "a", New: factory}, {Name: "A", New: factory}}, "registration 1: component 'A' differs only by case from registered component 'a'"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var err error
+				assert.NotPanics(t, func() { err = components.RegisterAll(registry, tt.regs...) })
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expected)
+			})
+		}
+		assert.Equal(t, []string{"counter"}, registry.ListComponents())
+	})
+}
+
+func TestRegisterAllIsAtomic(t *testing.T) {
+	registry := components.NewRegistry()
+	assert.PanicsWithValue(t,
+		"component 'list' implements ChunkedRenderer, which cannot be combined with WithBufferedRender, WithETag or WithCache",
+		func() {
+			registry.RegisterAll(map[string]func() templ.Component{
+				"counter": func() templ.Component { return &TestSimpleCounter{} },
+				"list":    func() templ.Component { return &TestLargeListComponent{} },
+			}, components.WithETag())
+		})
+	assert.Empty(t, registry.ListComponents())
+}
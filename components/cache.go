@@ -0,0 +1,141 @@
+package components
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the number of responses cached per component.
+const maxCacheEntries = 1000
+
+// responseCache is a concurrency-safe in-memory cache of rendered component responses.
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	keyFunc func(*http.Request) string
+	entries map[string]cachedResponse
+}
+
+// cachedResponse is a rendered response body and the HX-* headers sent with it.
+type cachedResponse struct {
+	body    []byte
+	headers http.Header
+	expires time.Time
+}
+
+// newResponseCache creates a cache whose entries live for ttl.
+// If keyFunc is nil, the request's query string is used as the key.
+func newResponseCache(ttl time.Duration, keyFunc func(*http.Request) string) *responseCache {
+	if ttl <= 0 {
+		panic("cache ttl must be greater than zero")
+	}
+	if keyFunc == nil {
+		keyFunc = defaultCacheKey
+	}
+	return &responseCache{
+		ttl:     ttl,
+		keyFunc: keyFunc,
+		entries: make(map[string]cachedResponse),
+	}
+}
+
+// defaultCacheKey keys responses by the request's (sorted) query parameters.
+func defaultCacheKey(req *http.Request) string {
+	return req.URL.Query().Encode()
+}
+
+// cacheVariant returns the parts of a request, besides its cache key, that the
+// component's output depends on: the headers it names with VaryProvider,
+// HX-Request for HxRequest components (which render a partial or a full page)
+// and the resolved locale for LocaleAware components. Responses are cached
+// separately for each variant.
+func cacheVariant(req *http.Request, instance any, locale string) string {
+	var parts []string
+	if _, ok := instance.(HxRequest); ok {
+		parts = append(parts, "HX-Request="+req.Header.Get("HX-Request"))
+	}
+	if v, ok := instance.(VaryProvider); ok {
+		for _, name := range v.Vary() {
+			parts = append(parts, http.CanonicalHeaderKey(name)+"="+strings.Join(req.Header.Values(name), ","))
+		}
+	}
+	if _, ok := instance.(LocaleAware); ok {
+		parts = append(parts, "locale="+locale)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// get returns the cached response for key if present and not expired.
+func (c *responseCache) get(key string, now time.Time) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp, ok := c.entries[key]
+	if !ok {
+		return cachedResponse{}, false
+	}
+	if now.After(resp.expires) {
+		delete(c.entries, key)
+		return cachedResponse{}, false
+	}
+	return resp, true
+}
+
+// set stores a response under key, removing any expired entries and, if the
+// cache is full, the entry closest to expiry.
+func (c *responseCache) set(key string, body []byte, headers http.Header, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, resp := range c.entries {
+		if now.After(resp.expires) {
+			delete(c.entries, k)
+		}
+	}
+	if _, exists := c.entries[key]; !exists {
+		for len(c.entries) >= maxCacheEntries {
+			var oldestKey string
+			var oldest time.Time
+			for k, resp := range c.entries {
+				if oldestKey == "" || resp.expires.Before(oldest) {
+					oldestKey, oldest = k, resp.expires
+				}
+			}
+			delete(c.entries, oldestKey)
+		}
+	}
+	c.entries[key] = cachedResponse{
+		body:    body,
+		headers: headers,
+		expires: now.Add(c.ttl),
+	}
+}
+
+// clear removes all cached responses.
+func (c *responseCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cachedResponse)
+}
+
+// hxResponseHeaders returns a copy of the HX-* headers in h.
+func hxResponseHeaders(h http.Header) http.Header {
+	out := make(http.Header)
+	for name, values := range h {
+		if strings.HasPrefix(name, "Hx-") {
+			out[name] = append([]string(nil), values...)
+		}
+	}
+	return out
+}
+
+// InvalidateCache removes all cached responses for the named component.
+// It is a no-op if the component is not registered or has no cache.
+func (r *Registry) InvalidateCache(name string) {
+	r.mu.RLock()
+	entry, exists := r.components[name]
+	r.mu.RUnlock()
+	if exists && entry.cache != nil {
+		entry.cache.clear()
+	}
+}
@@ -0,0 +1,153 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cachedRenders counts renders of TestCachedComponent
+var cachedRenders atomic.Int32
+
+// TestCachedComponent renders its query and how many times it has been rendered
+type TestCachedComponent struct {
+	Query string `form:"q"`
+}
+
+func (c *TestCachedComponent) OnRefresh(ctx context.Context) error {
+	return nil
+}
+
+func (c *TestCachedComponent) GetHxTrigger() string {
+	return "resultsLoaded"
+}
+
+func (c *TestCachedComponent) Render(ctx context.Context, w io.Writer) error {
+	n := cachedRenders.Add(1)
+	fmt.Fprintf(w, "<div>%s (render %d)</div>", c.Query, n)
+	return nil
+}
+
+func TestWithCache(t *testing.T) {
+	cachedRenders.Store(0)
+	registry := components.NewRegistry()
+	components.Register[*TestCachedComponent](registry, "search", components.WithCache(time.Minute, nil))
+
+	get := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("search")(w, req)
+		return w
+	}
+
+	t.Run("second identical GET is served from cache", func(t *testing.T) {
+		first := get("/component/search?q=go")
+		require.Equal(t, http.StatusOK, first.Code)
+		assert.Contains(t, first.Body.String(), "go (render 1)")
+
+		second := get("/component/search?q=go")
+		require.Equal(t, http.StatusOK, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "resultsLoaded", second.Header().Get("HX-Trigger"))
+		assert.Equal(t, int32(1), cachedRenders.Load())
+	})
+
+	t.Run("different values use a different key", func(t *testing.T) {
+		w := get("/component/search?q=templ")
+		assert.Contains(t, w.Body.String(), "templ (render 2)")
+	})
+
+	t.Run("event request bypasses and invalidates the cache", func(t *testing.T) {
+		w := get("/component/search?q=go&hxc-event=refresh")
+		assert.Contains(t, w.Body.String(), "go (render 3)")
+
+		w = get("/component/search?q=go")
+		assert.Contains(t, w.Body.String(), "go (render 4)")
+	})
+
+	t.Run("POST requests are not cached", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/component/search", strings.NewReader("q=go"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("search")(w, req)
+
+		assert.Contains(t, w.Body.String(), "go (render 5)")
+	})
+
+	t.Run("InvalidateCache clears cached responses", func(t *testing.T) {
+		registry.InvalidateCache("search")
+
+		w := get("/component/search?q=go")
+		assert.Contains(t, w.Body.String(), "go (render 6)")
+	})
+}
+
+func TestWithCacheExpiry(t *testing.T) {
+	cachedRenders.Store(0)
+	registry := components.NewRegistry()
+	components.Register[*TestCachedComponent](registry, "search", components.WithCache(10*time.Millisecond, nil))
+
+	get := func() string {
+		req := httptest.NewRequest(http.MethodGet, "/component/search?q=go", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("search")(w, req)
+		return w.Body.String()
+	}
+
+	assert.Contains(t, get(), "render 1")
+	assert.Contains(t, get(), "render 1")
+	time.Sleep(20 * time.Millisecond)
+	assert.Contains(t, get(), "render 2")
+}
+
+// TestVariantCachedComponent renders output that depends on a request header,
+// HX-Request and the locale
+type TestVariantCachedComponent struct {
+	partial bool
+	locale  string
+	theme   string
+}
+
+func (c *TestVariantCachedComponent) SetHxRequest(partial bool) { c.partial = partial }
+func (c *TestVariantCachedComponent) SetLocale(locale string)   { c.locale = locale }
+func (c *TestVariantCachedComponent) Vary() []string            { return []string{"X-Theme"} }
+
+func (c *TestVariantCachedComponent) SetRequest(req *http.Request) {
+	c.theme = req.Header.Get("X-Theme")
+}
+
+func (c *TestVariantCachedComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>partial=%v locale=%s theme=%s</div>", c.partial, c.locale, c.theme)
+	return nil
+}
+
+func TestWithCacheVariants(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestVariantCachedComponent](registry, "banner", components.WithCache(time.Minute, nil))
+
+	get := func(headers map[string]string) string {
+		req := httptest.NewRequest(http.MethodGet, "/component/banner", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		registry.HandlerFor("banner")(w, req)
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "<div>partial=false locale=en theme=</div>", get(nil))
+	assert.Equal(t, "<div>partial=true locale=en theme=</div>", get(map[string]string{"HX-Request": "true"}))
+	assert.Equal(t, "<div>partial=false locale=fr theme=</div>", get(map[string]string{"Accept-Language": "fr"}))
+	assert.Equal(t, "<div>partial=false locale=en theme=dark</div>", get(map[string]string{"X-Theme": "dark"}))
+	assert.Equal(t, "<div>partial=true locale=en theme=</div>", get(map[string]string{"HX-Request": "true"}))
+}
@@ -0,0 +1,104 @@
+package components
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Capability identifies an optional behavior of a component, for
+// ComponentsImplementing.
+type Capability int
+
+const (
+	// CapabilityProcessor matches components implementing Processor.
+	CapabilityProcessor Capability = iota
+	// CapabilityValidator matches components implementing Validator.
+	CapabilityValidator
+	// CapabilityInitializer matches components implementing Initializer.
+	CapabilityInitializer
+	// CapabilityEvents matches event-driven components, which have at least one
+	// On{Event} handler method and so can mutate state.
+	CapabilityEvents
+)
+
+var (
+	processorType   = reflect.TypeOf((*Processor)(nil)).Elem()
+	validatorType   = reflect.TypeOf((*Validator)(nil)).Elem()
+	initializerType = reflect.TypeOf((*Initializer)(nil)).Elem()
+)
+
+// String returns the name of the capability.
+func (c Capability) String() string {
+	switch c {
+	case CapabilityProcessor:
+		return "Processor"
+	case CapabilityValidator:
+		return "Validator"
+	case CapabilityInitializer:
+		return "Initializer"
+	case CapabilityEvents:
+		return "Events"
+	}
+	return "Capability(" + strconv.Itoa(int(c)) + ")"
+}
+
+// allCapabilities lists every Capability, for capabilitiesOf.
+var allCapabilities = []Capability{CapabilityProcessor, CapabilityValidator, CapabilityInitializer, CapabilityEvents}
+
+// capabilitySet is a set of capabilities, computed once per component at
+// registration so ComponentsImplementing does not reflect on every call.
+type capabilitySet uint
+
+// has reports whether the set contains c.
+func (s capabilitySet) has(c Capability) bool {
+	return c >= 0 && c < Capability(len(allCapabilities)) && s&(1<<uint(c)) != 0
+}
+
+// matches reports whether a component pointer type has the capability, using
+// eventPrefix to find event handler methods.
+func (c Capability) matches(ptrType reflect.Type, eventPrefix string) bool {
+	switch c {
+	case CapabilityProcessor:
+		return ptrType.Implements(processorType)
+	case CapabilityValidator:
+		return ptrType.Implements(validatorType)
+	case CapabilityInitializer:
+		return ptrType.Implements(initializerType)
+	case CapabilityEvents:
+		return len(discoverEvents(ptrType, eventPrefix)) > 0
+	}
+	return false
+}
+
+// capabilitiesOf returns the capabilities of a component struct type, using
+// eventPrefix to find event handler methods.
+func capabilitiesOf(structType reflect.Type, eventPrefix string) capabilitySet {
+	ptrType := reflect.PointerTo(structType)
+	var set capabilitySet
+	for _, c := range allCapabilities {
+		if c.matches(ptrType, eventPrefix) {
+			set |= 1 << uint(c)
+		}
+	}
+	return set
+}
+
+// ComponentsImplementing returns the names of the registered components with the
+// given capability, in alphabetical order, e.g. to list every component that
+// handles events in an admin tool:
+//
+//	mutating := registry.ComponentsImplementing(components.CapabilityEvents)
+func (r *Registry) ComponentsImplementing(capability Capability) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := []string{}
+	for name, entry := range r.components {
+		if entry.capabilities.has(capability) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
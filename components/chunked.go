@@ -0,0 +1,92 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// ChunkedRenderer is an optional interface for components that render large
+// output, such as a table with thousands of rows, progressively instead of
+// building the whole response in memory. The registry calls RenderChunked instead
+// of Render; the component writes to w in pieces and calls flush whenever the
+// client should receive what has been written so far. The response has no
+// Content-Length, so the server sends it with chunked transfer encoding.
+//
+// Chunked rendering cannot be combined with WithBufferedRender, WithETag or
+// WithCache, which need the whole response in a buffer; Register panics if they
+// are used together. For HEAD requests, debug traces, idempotent replays and
+// replacement components returned by event handlers the component is rendered
+// with Render as usual, and SetBufferedRender and SetRenderTimeout do not apply.
+//
+// Example:
+//
+//	func (t *ReportTable) RenderChunked(ctx context.Context, w io.Writer, flush func()) error {
+//	    for i, row := range t.Rows {
+//	        if err := ReportRow(row).Render(ctx, w); err != nil {
+//	            return err
+//	        }
+//	        if i%500 == 499 {
+//	            flush()
+//	        }
+//	    }
+//	    return nil
+//	}
+type ChunkedRenderer interface {
+	RenderChunked(ctx context.Context, w io.Writer, flush func()) error
+}
+
+var chunkedRendererType = reflect.TypeOf((*ChunkedRenderer)(nil)).Elem()
+
+// checkChunked returns an error if a ChunkedRenderer component was registered with
+// an option that buffers its response.
+func checkChunked(name string, entry componentEntry) error {
+	if !reflect.PointerTo(entry.structType).Implements(chunkedRendererType) {
+		return nil
+	}
+	if entry.bufferedRender || entry.etag || entry.cache != nil {
+		return fmt.Errorf("component '%s' implements ChunkedRenderer, which cannot be combined with WithBufferedRender, WithETag or WithCache", name)
+	}
+	return nil
+}
+
+// renderChunked renders a ChunkedRenderer straight to the response, flushing
+// whenever the component asks to. An error before any output is rendered by the
+// error handler; after that the response has started and it can only be logged.
+func (r *Registry) renderChunked(w http.ResponseWriter, req *http.Request, chunked ChunkedRenderer, componentName string, observer LifecycleObserver) {
+	out := &countingWriter{Writer: w}
+	flush := flusherFor(w)
+	err := observePhase(req.Context(), observer, componentName, PhaseRender, func() error {
+		if err := chunked.RenderChunked(req.Context(), out, flush); err != nil {
+			return &ErrRender{ComponentName: componentName, BytesWritten: out.n, Err: err}
+		}
+		return nil
+	})
+	if err == nil {
+		r.componentStats(componentName).renders.Add(1)
+		r.afterRender(req.Context(), chunked, componentName)
+		return
+	}
+	r.logger().Error("component render error",
+		"component", componentName,
+		"error", err)
+	r.reportError(req.Context(), componentName, "render", err, http.StatusInternalServerError)
+	if out.n == 0 {
+		r.renderComponentError(w, req, componentName, err, "Render Error", fmt.Sprintf("Component rendering failed: %v", errors.Unwrap(err)), http.StatusInternalServerError)
+	}
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.Writer.Write(p)
+	c.n += n
+	return n, err
+}
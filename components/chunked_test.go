@@ -0,0 +1,145 @@
+package components_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLargeListComponent renders Rows rows, flushing every 100
+type TestLargeListComponent struct {
+	Rows   int `form:"rows"`
+	FailAt int `form:"fail"`
+}
+
+func (c *TestLargeListComponent) RenderChunked(ctx context.Context, w io.Writer, flush func()) error {
+	for i := 0; i < c.Rows; i++ {
+		if c.FailAt > 0 && i == c.FailAt {
+			return errors.New("row unavailable")
+		}
+		fmt.Fprintf(w, "<tr><td>%d</td></tr>", i)
+		if i%100 == 99 {
+			flush()
+		}
+	}
+	if c.FailAt < 0 {
+		return errors.New("list unavailable")
+	}
+	return nil
+}
+
+func (c *TestLargeListComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprint(w, "<table>buffered</table>")
+	return nil
+}
+
+// flushRecorder counts flushes and the body size seen at each one
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushedAt []int
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushedAt = append(f.flushedAt, f.Body.Len())
+	f.ResponseRecorder.Flush()
+}
+
+func TestChunkedRenderer(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestLargeListComponent](registry, "list")
+
+	serve := func(method, query string) *flushRecorder {
+		req := httptest.NewRequest(method, "/component/list?"+query, nil)
+		w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		registry.HandlerFor("list")(w, req)
+		return w
+	}
+
+	t.Run("flushes progressively", func(t *testing.T) {
+		w := serve(http.MethodGet, "rows=1000")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, w.flushedAt, 10)
+		assert.Less(t, w.flushedAt[0], w.flushedAt[1])
+		assert.Equal(t, 1000, strings.Count(w.Body.String(), "<tr>"))
+		assert.Empty(t, w.Header().Get("Content-Length"))
+	})
+
+	t.Run("error before output renders the error handler", func(t *testing.T) {
+		w := serve(http.MethodGet, "rows=0&fail=-1")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("error after output keeps the partial response", func(t *testing.T) {
+		w := serve(http.MethodGet, "rows=300&fail=150")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 150, strings.Count(w.Body.String(), "<tr>"))
+	})
+
+	t.Run("HEAD uses Render", func(t *testing.T) {
+		w := serve(http.MethodHead, "rows=1000")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.flushedAt)
+		assert.Equal(t, fmt.Sprint(len("<table>buffered</table>")), w.Header().Get("Content-Length"))
+	})
+
+	t.Run("rejects buffering options", func(t *testing.T) {
+		assert.Panics(t, func() {
+			components.Register[*TestLargeListComponent](registry, "list-etag", components.WithETag())
+		})
+		assert.Panics(t, func() {
+			components.Register[*TestLargeListComponent](registry, "list-buffered", components.WithBufferedRender())
+		})
+	})
+}
+
+// chunkedAfterRenders counts TestChunkedAfterRenderComponent.AfterRender calls
+var chunkedAfterRenders atomic.Int32
+
+// TestChunkedAfterRenderComponent is a ChunkedRenderer with an AfterRender hook
+type TestChunkedAfterRenderComponent struct {
+	Fail bool `form:"fail"`
+}
+
+func (c *TestChunkedAfterRenderComponent) RenderChunked(ctx context.Context, w io.Writer, flush func()) error {
+	if c.Fail {
+		return errors.New("list unavailable")
+	}
+	fmt.Fprint(w, "<table></table>")
+	return nil
+}
+
+func (c *TestChunkedAfterRenderComponent) Render(ctx context.Context, w io.Writer) error {
+	return nil
+}
+
+func (c *TestChunkedAfterRenderComponent) AfterRender(ctx context.Context) error {
+	chunkedAfterRenders.Add(1)
+	return nil
+}
+
+func TestChunkedRendererAfterRender(t *testing.T) {
+	chunkedAfterRenders.Store(0)
+	registry := components.NewRegistry()
+	components.Register[*TestChunkedAfterRenderComponent](registry, "list")
+
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		registry.HandlerFor("list")(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	assert.Equal(t, "<table></table>", get("/component/list").Body.String())
+	assert.Equal(t, int32(1), chunkedAfterRenders.Load())
+
+	assert.Equal(t, http.StatusInternalServerError, get("/component/list?fail=true").Code)
+	assert.Equal(t, int32(1), chunkedAfterRenders.Load())
+}
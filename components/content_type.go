@@ -0,0 +1,60 @@
+package components
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// ContentTyper is an optional interface for components whose response is not HTML,
+// such as a CSV export or a JSON feed. The returned value is sent as the
+// Content-Type header instead of "text/html"; an empty value keeps the default.
+type ContentTyper interface {
+	ContentType() string
+}
+
+// RawResponder is an optional interface for components that write their response
+// body directly instead of rendering a template, e.g. to stream a file download.
+// When implemented, WriteResponse is called in place of Render (unless an event
+// handler returned a replacement component). Combine it with ContentTyper to set
+// the content type:
+//
+//	func (e *OrdersExport) ContentType() string { return "text/csv" }
+//
+//	func (e *OrdersExport) WriteResponse(ctx context.Context, w io.Writer) error {
+//	    cw := csv.NewWriter(w)
+//	    for _, o := range e.Orders {
+//	        cw.Write([]string{o.ID, o.Total})
+//	    }
+//	    cw.Flush()
+//	    return cw.Error()
+//	}
+//
+// Response headers, such as Content-Disposition for downloads, can be set through
+// RequestAware or the HX-* response interfaces before the body is written.
+type RawResponder interface {
+	WriteResponse(ctx context.Context, w io.Writer) error
+}
+
+// contentTypeOf returns the Content-Type for a component's response.
+func contentTypeOf(instance any) string {
+	if typer, ok := instance.(ContentTyper); ok {
+		if contentType := typer.ContentType(); contentType != "" {
+			return contentType
+		}
+	}
+	return "text/html"
+}
+
+// storedResponseHeaders returns the headers kept with a cached or replayed
+// response: the HX-* headers, the Content-Type and Vary.
+func storedResponseHeaders(h http.Header) http.Header {
+	out := hxResponseHeaders(h)
+	if contentType := h.Get("Content-Type"); contentType != "" {
+		out.Set("Content-Type", contentType)
+	}
+	if vary := h.Values("Vary"); len(vary) > 0 {
+		out["Vary"] = append([]string(nil), vary...)
+	}
+	return out
+}
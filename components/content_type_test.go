@@ -0,0 +1,104 @@
+package components_test
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCSVExportComponent writes its rows as CSV instead of rendering HTML
+type TestCSVExportComponent struct {
+	Rows int `form:"rows"`
+}
+
+func (c *TestCSVExportComponent) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (c *TestCSVExportComponent) WriteResponse(ctx context.Context, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "name"})
+	for i := 1; i <= c.Rows; i++ {
+		cw.Write([]string{fmt.Sprint(i), fmt.Sprintf("item %d", i)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (c *TestCSVExportComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, "<div>not used</div>")
+	return err
+}
+
+// TestJSONContentTypeComponent renders through templ but declares a JSON content type
+type TestJSONContentTypeComponent struct{}
+
+func (c *TestJSONContentTypeComponent) ContentType() string {
+	return "application/json"
+}
+
+func (c *TestJSONContentTypeComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, `{"ok":true}`)
+	return err
+}
+
+func TestContentTypeAndRawResponse(t *testing.T) {
+	t.Run("RawResponder writes CSV with its content type", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestCSVExportComponent](registry, "export")
+
+		req := httptest.NewRequest(http.MethodGet, "/component/export?rows=2", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("export")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "id,name\n1,item 1\n2,item 2\n", w.Body.String())
+	})
+
+	t.Run("ContentTyper applies to templ rendering", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestJSONContentTypeComponent](registry, "json")
+
+		req := httptest.NewRequest(http.MethodGet, "/component/json", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("json")(w, req)
+
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Equal(t, `{"ok":true}`, w.Body.String())
+	})
+
+	t.Run("cached response keeps its content type", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestCSVExportComponent](registry, "export",
+			components.WithCache(time.Minute, nil))
+
+		for i := 0; i < 2; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/component/export?rows=1", nil)
+			w := httptest.NewRecorder()
+			registry.HandlerFor("export")(w, req)
+
+			assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+			assert.Equal(t, "id,name\n1,item 1\n", w.Body.String())
+		}
+	})
+
+	t.Run("components without ContentTyper stay text/html", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSimpleCounter](registry, "counter")
+
+		req := httptest.NewRequest(http.MethodGet, "/component/counter", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+
+		assert.Equal(t, "text/html", w.Header().Get("Content-Type"))
+	})
+}
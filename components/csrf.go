@@ -0,0 +1,150 @@
+package components
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+)
+
+// Default names used by CSRF protection when CSRFConfig leaves them empty.
+const (
+	DefaultCSRFCookieName = "hxc_csrf"
+	DefaultCSRFHeaderName = "X-CSRF-Token"
+	DefaultCSRFFieldName  = "csrf_token"
+)
+
+// CSRFConfig configures CSRF protection for state-changing requests.
+//
+// By default the double-submit cookie pattern is used: the token submitted in the
+// header (or form field) must match the token stored in the cookie.
+//
+// Example:
+//
+//	registry.EnableCSRF(components.CSRFConfig{})
+//
+//	// In your page handler, issue the token and render it in forms:
+//	token := registry.CSRFToken(w, req)
+//	<input type="hidden" name="csrf_token" value={ token }/>
+//
+//	// Or send it with every HTMX request:
+//	<body hx-headers={ fmt.Sprintf(`{"X-CSRF-Token": %q}`, token) }>
+type CSRFConfig struct {
+	// CookieName is the cookie holding the expected token. Defaults to "hxc_csrf".
+	CookieName string
+	// HeaderName is the request header carrying the submitted token. Defaults to "X-CSRF-Token".
+	HeaderName string
+	// FieldName is the form field carrying the submitted token. Defaults to "csrf_token".
+	// The header takes precedence when both are present.
+	FieldName string
+	// Validate reports whether the submitted token is valid for the request.
+	// Defaults to a constant-time comparison against the cookie value.
+	Validate func(req *http.Request, token string) bool
+}
+
+// EnableCSRF enables CSRF protection for the registry.
+// POST, PUT, PATCH and DELETE requests must carry a valid token or they are rejected
+// with a 403 before form decoding. Safe methods (GET, HEAD, OPTIONS) are not checked.
+func (r *Registry) EnableCSRF(config CSRFConfig) {
+	if config.CookieName == "" {
+		config.CookieName = DefaultCSRFCookieName
+	}
+	if config.HeaderName == "" {
+		config.HeaderName = DefaultCSRFHeaderName
+	}
+	if config.FieldName == "" {
+		config.FieldName = DefaultCSRFFieldName
+	}
+	if config.Validate == nil {
+		cookieName := config.CookieName
+		config.Validate = func(req *http.Request, token string) bool {
+			cookie, err := req.Cookie(cookieName)
+			if err != nil || cookie.Value == "" {
+				return false
+			}
+			return subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(token)) == 1
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.csrf = &config
+}
+
+// csrfConfig returns the CSRF configuration, or nil if CSRF protection is disabled.
+func (r *Registry) csrfConfig() *CSRFConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.csrf
+}
+
+// CSRFToken returns the CSRF token for the request, issuing a new token cookie
+// if the request does not already carry one. Render the returned value in a hidden
+// form field (named CSRFConfig.FieldName) or send it in the CSRF header.
+// Returns an empty string if CSRF protection is not enabled.
+func (r *Registry) CSRFToken(w http.ResponseWriter, req *http.Request) string {
+	config := r.csrfConfig()
+	if config == nil {
+		return ""
+	}
+
+	if cookie, err := req.Cookie(config.CookieName); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+
+	token, err := generateCSRFToken()
+	if err != nil {
+		r.logger().Error("failed to generate CSRF token", "error", err)
+		return ""
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     config.CookieName,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   req.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return token
+}
+
+// checkCSRF validates the CSRF token for state-changing requests.
+// The token is read from the header, or from the form field when readField is true,
+// in which case the form must already be parsed. Components whose body is left
+// unread (WithoutFormParsing) must send the token in the header.
+func (r *Registry) checkCSRF(req *http.Request, readField bool) error {
+	config := r.csrfConfig()
+	if config == nil || isSafeMethod(req.Method) {
+		return nil
+	}
+
+	token := req.Header.Get(config.HeaderName)
+	if token == "" && readField {
+		token = req.PostFormValue(config.FieldName)
+	}
+	if token == "" {
+		return &ErrCSRF{Reason: "missing token"}
+	}
+	if !config.Validate(req, token) {
+		return &ErrCSRF{Reason: "token mismatch"}
+	}
+	return nil
+}
+
+// isSafeMethod reports whether the HTTP method is considered safe (non-mutating).
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// generateCSRFToken returns a new random URL-safe token.
+func generateCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
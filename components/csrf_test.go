@@ -0,0 +1,124 @@
+package components_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSRF(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSimpleCounter](registry, "counter")
+	registry.EnableCSRF(components.CSRFConfig{})
+
+	postWithToken := func(cookieToken, formToken, headerToken string) *httptest.ResponseRecorder {
+		form := url.Values{"count": {"1"}, "hxc-event": {"increment"}}
+		if formToken != "" {
+			form.Set(components.DefaultCSRFFieldName, formToken)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/component/counter", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if cookieToken != "" {
+			req.AddCookie(&http.Cookie{Name: components.DefaultCSRFCookieName, Value: cookieToken})
+		}
+		if headerToken != "" {
+			req.Header.Set(components.DefaultCSRFHeaderName, headerToken)
+		}
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+		return w
+	}
+
+	t.Run("valid form token passes", func(t *testing.T) {
+		w := postWithToken("secret-token", "secret-token", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "<div>2</div>")
+	})
+
+	t.Run("valid header token passes", func(t *testing.T) {
+		w := postWithToken("secret-token", "", "secret-token")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("missing token is rejected", func(t *testing.T) {
+		w := postWithToken("secret-token", "", "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "missing token")
+		assert.NotContains(t, w.Body.String(), "<div>2</div>")
+	})
+
+	t.Run("mismatched token is rejected", func(t *testing.T) {
+		w := postWithToken("secret-token", "forged-token", "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "token mismatch")
+	})
+
+	t.Run("GET bypasses the check", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/counter?count=1&hxc-event=increment", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "<div>2</div>")
+	})
+}
+
+func TestCSRFToken(t *testing.T) {
+	t.Run("issues a cookie when none is present", func(t *testing.T) {
+		registry := components.NewRegistry()
+		registry.EnableCSRF(components.CSRFConfig{})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		token := registry.CSRFToken(w, req)
+		require.NotEmpty(t, token)
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, components.DefaultCSRFCookieName, cookies[0].Name)
+		assert.Equal(t, token, cookies[0].Value)
+		assert.True(t, cookies[0].HttpOnly)
+	})
+
+	t.Run("reuses an existing cookie", func(t *testing.T) {
+		registry := components.NewRegistry()
+		registry.EnableCSRF(components.CSRFConfig{})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: components.DefaultCSRFCookieName, Value: "existing"})
+		w := httptest.NewRecorder()
+
+		assert.Equal(t, "existing", registry.CSRFToken(w, req))
+		assert.Empty(t, w.Result().Cookies())
+	})
+
+	t.Run("custom validator is used", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSimpleCounter](registry, "counter")
+		registry.EnableCSRF(components.CSRFConfig{
+			FieldName: "_token",
+			Validate: func(req *http.Request, token string) bool {
+				return token == "static"
+			},
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/component/counter", strings.NewReader("_token=static"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("returns empty string when disabled", func(t *testing.T) {
+		registry := components.NewRegistry()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		assert.Empty(t, registry.CSRFToken(httptest.NewRecorder(), req))
+	})
+}
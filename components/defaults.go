@@ -0,0 +1,103 @@
+package components
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// applyDefaults sets every field of the struct that instance points to that has a
+// `default` tag and is still at its zero value after decoding, e.g.
+//
+//	Limit int      `form:"limit" default:"5"`
+//	Sort  string   `form:"sort" default:"name"`
+//	Tags  []string `form:"tags" default:"new,popular"`
+//
+// Strings, bools, signed and unsigned integers, floats and slices of these
+// (comma-separated) are supported. Embedded structs are walked as well. Because
+// only zero values are replaced, a submitted 0, "" or false also receives the
+// default, so tag only fields whose zero value is not a meaningful input.
+func applyDefaults(instance any) error {
+	v := reflect.ValueOf(instance)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	return applyStructDefaults(v.Elem())
+}
+
+func applyStructDefaults(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		value := v.Field(i)
+		if field.Anonymous && value.Kind() == reflect.Struct {
+			if err := applyStructDefaults(value); err != nil {
+				return err
+			}
+			continue
+		}
+		def, ok := field.Tag.Lookup("default")
+		if !ok || !value.IsZero() {
+			continue
+		}
+		if err := setDefault(value, def); err != nil {
+			return fmt.Errorf("invalid default %q for field %s: %w", def, field.Name, err)
+		}
+	}
+	return nil
+}
+
+// setDefault parses def into value according to its kind.
+func setDefault(value reflect.Value, def string) error {
+	if value.Kind() == reflect.Slice {
+		parts := strings.Split(def, ",")
+		slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setScalar(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		value.Set(slice)
+		return nil
+	}
+	return setScalar(value, def)
+}
+
+// setScalar parses s into a string, bool, integer or float value.
+func setScalar(value reflect.Value, s string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", value.Type())
+	}
+	return nil
+}
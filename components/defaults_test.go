@@ -0,0 +1,83 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDefaultsSearchComponent declares its defaults with struct tags
+type TestDefaultsSearchComponent struct {
+	Query     string   `form:"q"`
+	Limit     int      `form:"limit" default:"5"`
+	Sort      string   `form:"sort" default:"relevance"`
+	Highlight bool     `form:"highlight" default:"true"`
+	Sources   []string `form:"sources" default:"docs, blog"`
+	Ratio     float64  `form:"ratio" default:"0.5"`
+}
+
+func (c *TestDefaultsSearchComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s|%d|%s|%t|%s|%g", c.Query, c.Limit, c.Sort, c.Highlight, strings.Join(c.Sources, "+"), c.Ratio)
+	return err
+}
+
+// TestBadDefaultComponent has a default that cannot be parsed
+type TestBadDefaultComponent struct {
+	Limit int `form:"limit" default:"five"`
+}
+
+func (c *TestBadDefaultComponent) Render(ctx context.Context, w io.Writer) error {
+	return nil
+}
+
+func TestDefaultTags(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestDefaultsSearchComponent](registry, "search")
+
+	get := func(query string) string {
+		req := httptest.NewRequest(http.MethodGet, "/component/search?"+query, nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("search")(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	t.Run("defaults fill omitted fields", func(t *testing.T) {
+		assert.Equal(t, "go|5|relevance|true|docs+blog|0.5", get("q=go"))
+	})
+
+	t.Run("posted values win", func(t *testing.T) {
+		assert.Equal(t, "go|20|date|true|news|0.25", get("q=go&limit=20&sort=date&sources=news&ratio=0.25"))
+	})
+
+	t.Run("RenderComponent applies defaults", func(t *testing.T) {
+		component, err := registry.RenderComponent(context.Background(), "search", url.Values{"limit": {"3"}})
+		require.NoError(t, err)
+		html, err := components.RenderToString(context.Background(), component)
+		require.NoError(t, err)
+		assert.Equal(t, "|3|relevance|true|docs+blog|0.5", html)
+	})
+
+	t.Run("SimulateRequest applies defaults", func(t *testing.T) {
+		component := &TestDefaultsSearchComponent{}
+		require.NoError(t, components.SimulateRequest(context.Background(), component, http.MethodGet, url.Values{}, nil))
+		assert.Equal(t, 5, component.Limit)
+	})
+
+	t.Run("invalid default panics at registration", func(t *testing.T) {
+		assert.PanicsWithValue(t,
+			`component 'bad': invalid default "five" for field Limit: strconv.ParseInt: parsing "five": invalid syntax`,
+			func() {
+				components.Register[*TestBadDefaultComponent](components.NewRegistry(), "bad")
+			})
+	})
+}
@@ -0,0 +1,54 @@
+package components
+
+// ComponentFilter selects which components ListComponents returns.
+type ComponentFilter int
+
+const (
+	// AllComponents lists every registered component (the default).
+	AllComponents ComponentFilter = iota
+	// EnabledComponents lists only components that are enabled.
+	EnabledComponents
+	// DisabledComponents lists only components disabled with SetEnabled.
+	DisabledComponents
+)
+
+// matches reports whether a component with the given enabled state passes the filter.
+func (f ComponentFilter) matches(enabled bool) bool {
+	switch f {
+	case EnabledComponents:
+		return enabled
+	case DisabledComponents:
+		return !enabled
+	}
+	return true
+}
+
+// SetEnabled enables or disables a registered component without unregistering it,
+// e.g. behind a feature flag or during maintenance. Requests for a disabled
+// component are answered with 503 Service Unavailable through the error handler,
+// and RenderComponent and BatchHandler fail with *ErrComponentDisabled. Components
+// are enabled when registered. Unknown names are ignored with a warning.
+//
+//	registry.SetEnabled("checkout", false) // checkout is temporarily unavailable
+func (r *Registry) SetEnabled(name string, enabled bool) {
+	r.mu.Lock()
+	entry, exists := r.components[name]
+	if exists {
+		entry.disabled = !enabled
+		r.components[name] = entry
+	}
+	r.mu.Unlock()
+
+	if !exists {
+		r.logger().Warn("cannot enable or disable unknown component",
+			"component", name)
+	}
+}
+
+// IsEnabled reports whether a component is registered and enabled.
+func (r *Registry) IsEnabled(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, exists := r.components[name]
+	return exists && !entry.disabled
+}
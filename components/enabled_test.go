@@ -0,0 +1,64 @@
+package components_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetEnabled(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSimpleCounter](registry, "counter")
+	components.Register[*TestSimpleCounter](registry, "checkout")
+
+	get := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/component/checkout", nil)
+		w := httptest.NewRecorder()
+		registry.Handler(w, req)
+		return w
+	}
+
+	t.Run("disabled component returns 503", func(t *testing.T) {
+		registry.SetEnabled("checkout", false)
+
+		w := get()
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), "temporarily unavailable")
+		assert.False(t, registry.IsEnabled("checkout"))
+		assert.True(t, registry.IsRegistered("checkout"))
+
+		_, err := registry.RenderComponent(context.Background(), "checkout", nil)
+		var disabled *components.ErrComponentDisabled
+		assert.ErrorAs(t, err, &disabled)
+	})
+
+	t.Run("ListComponents filters by state", func(t *testing.T) {
+		assert.Equal(t, []string{"checkout", "counter"}, registry.ListComponents())
+		assert.Equal(t, []string{"counter"}, registry.ListComponents(components.EnabledComponents))
+		assert.Equal(t, []string{"checkout"}, registry.ListComponents(components.DisabledComponents))
+
+		info, err := registry.GetComponentInfo("checkout")
+		assert.NoError(t, err)
+		assert.False(t, info.Enabled)
+	})
+
+	t.Run("re-enabled component renders", func(t *testing.T) {
+		registry.SetEnabled("checkout", true)
+
+		w := get()
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>0</div>", w.Body.String())
+		assert.True(t, registry.IsEnabled("checkout"))
+		assert.Empty(t, registry.ListComponents(components.DisabledComponents))
+	})
+
+	t.Run("unknown component is ignored", func(t *testing.T) {
+		registry.SetEnabled("missing", false)
+		assert.False(t, registry.IsEnabled("missing"))
+		assert.False(t, registry.IsRegistered("missing"))
+	})
+}
@@ -0,0 +1,43 @@
+package components
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ErrorTriggerEvent is the client-side event that the default error handler
+// triggers on HTMX requests, so pages can show a toast when a component fails:
+//
+//	document.body.addEventListener("showError", (e) => {
+//	    showToast(e.detail.title, e.detail.message)
+//	})
+const ErrorTriggerEvent = "showError"
+
+// ErrorTriggerDetail is the event.detail payload of ErrorTriggerEvent.
+type ErrorTriggerDetail struct {
+	Title     string `json:"title"`
+	Message   string `json:"message"`
+	Code      int    `json:"code"`
+	Component string `json:"component,omitempty"`
+}
+
+// TriggerError sets an HX-Trigger response header that fires ErrorTriggerEvent
+// with the error's details, if the request was made by HTMX. It replaces any
+// HX-Trigger header already set. The default error handler calls it; custom
+// handlers set with SetContextErrorHandler can call it to keep the behavior.
+// It must be called before the response status is written.
+func TriggerError(w http.ResponseWriter, ec ErrorContext) {
+	if !ec.IsHTMX {
+		return
+	}
+	// A struct of strings and ints always encodes
+	value, _ := json.Marshal(map[string]ErrorTriggerDetail{
+		ErrorTriggerEvent: {
+			Title:     ec.Title,
+			Message:   ec.Message,
+			Code:      ec.Code,
+			Component: ec.ComponentName,
+		},
+	})
+	w.Header().Set("HX-Trigger", string(value))
+}
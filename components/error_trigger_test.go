@@ -0,0 +1,60 @@
+package components_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorTrigger(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestQuantityComponent](registry, "quantity")
+
+	t.Run("default handler triggers showError for HTMX requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/quantity?quantity=lots", nil)
+		req.Header.Set("HX-Request", "true")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("quantity")(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var trigger map[string]components.ErrorTriggerDetail
+		require.NoError(t, json.Unmarshal([]byte(w.Header().Get("HX-Trigger")), &trigger))
+		detail := trigger[components.ErrorTriggerEvent]
+		assert.Equal(t, "Decode Error", detail.Title)
+		assert.Equal(t, http.StatusBadRequest, detail.Code)
+		assert.Equal(t, "quantity", detail.Component)
+		assert.Contains(t, detail.Message, "quantity")
+	})
+
+	t.Run("plain requests get no trigger", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/quantity?quantity=lots", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("quantity")(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, w.Header().Get("HX-Trigger"))
+	})
+
+	t.Run("custom handlers can reuse TriggerError", func(t *testing.T) {
+		custom := components.NewRegistry()
+		components.Register[*TestQuantityComponent](custom, "quantity")
+		custom.SetContextErrorHandler(func(w http.ResponseWriter, req *http.Request, ec components.ErrorContext) {
+			components.TriggerError(w, ec)
+			w.WriteHeader(ec.Code)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/component/quantity?quantity=lots", nil)
+		req.Header.Set("HX-Request", "true")
+		w := httptest.NewRecorder()
+		custom.HandlerFor("quantity")(w, req)
+
+		assert.Contains(t, w.Header().Get("HX-Trigger"), `"showError"`)
+		assert.Empty(t, w.Body.String())
+	})
+}
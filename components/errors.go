@@ -1,11 +1,15 @@
 package components
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // ComponentError represents an error that occurred during component processing.
 type ComponentError struct {
 	ComponentName string
-	Operation     string // "parse", "decode", "process", "render", "event"
+	Operation     string // "parse", "csrf", "decode", "authorize", "state", "init", "event", "process", "render", "panic"
 	Err           error
 	StatusCode    int
 }
@@ -27,6 +31,15 @@ func (e *ErrComponentNotFound) Error() string {
 	return fmt.Sprintf("component '%s' not found", e.ComponentName)
 }
 
+// ErrComponentDisabled represents a request for a component disabled with SetEnabled.
+type ErrComponentDisabled struct {
+	ComponentName string
+}
+
+func (e *ErrComponentDisabled) Error() string {
+	return fmt.Sprintf("component '%s' is temporarily unavailable", e.ComponentName)
+}
+
 // ErrEventNotFound represents an event handler not found error.
 type ErrEventNotFound struct {
 	ComponentName string
@@ -37,6 +50,21 @@ func (e *ErrEventNotFound) Error() string {
 	return fmt.Sprintf("event handler '%s' not found on component '%s'", e.EventName, e.ComponentName)
 }
 
+// ErrEventSignature represents an On{Event} method that exists but cannot be called
+// as an event handler because its signature is wrong, e.g. OnIncrement(ctx, id).
+type ErrEventSignature struct {
+	ComponentName string
+	MethodName    string
+	// Expected lists the accepted signatures; Actual is the method's signature.
+	Expected string
+	Actual   string
+}
+
+func (e *ErrEventSignature) Error() string {
+	return fmt.Sprintf("event handler '%s' on component '%s' has signature %s, expected %s",
+		e.MethodName, e.ComponentName, e.Actual, e.Expected)
+}
+
 // ErrInvalidComponentName represents an invalid component name error.
 type ErrInvalidComponentName struct {
 	ComponentName string
@@ -49,3 +77,119 @@ func (e *ErrInvalidComponentName) Error() string {
 	}
 	return fmt.Sprintf("invalid component name '%s'", e.ComponentName)
 }
+
+// ErrNotRenderable represents a component whose instance cannot be rendered because
+// it does not implement templ.Component. Register rejects such types, so this
+// indicates a misconfigured registry.
+type ErrNotRenderable struct {
+	ComponentName string
+	Type          string
+}
+
+func (e *ErrNotRenderable) Error() string {
+	return fmt.Sprintf("component '%s' (%s) does not implement templ.Component", e.ComponentName, e.Type)
+}
+
+// ErrCSRF represents a failed CSRF token validation.
+type ErrCSRF struct {
+	Reason string
+}
+
+func (e *ErrCSRF) Error() string {
+	return fmt.Sprintf("CSRF validation failed: %s", e.Reason)
+}
+
+// ErrUnauthorized can be returned from Authorizer.Authorize to reject a request
+// with 401 Unauthorized rather than 403 Forbidden.
+type ErrUnauthorized struct {
+	Reason string
+}
+
+func (e *ErrUnauthorized) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("unauthorized: %s", e.Reason)
+	}
+	return "unauthorized"
+}
+
+// ErrUnknownFields represents form fields that do not match any form-tagged field
+// of a component registered WithStrictFields.
+type ErrUnknownFields struct {
+	Fields []string
+}
+
+func (e *ErrUnknownFields) Error() string {
+	return fmt.Sprintf("unexpected form fields: %s", strings.Join(e.Fields, ", "))
+}
+
+// FieldError describes a form value that could not be decoded into a component field.
+type FieldError struct {
+	Field string // Form field name (e.g. "count" or "items[0].qty")
+	Value string // Submitted value, if any
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("field '%s' (value %q): %v", e.Field, e.Value, e.Err)
+}
+
+// ErrDecode represents a failure to decode form data into a component.
+// Fields lists the offending fields when the decoder reports them.
+type ErrDecode struct {
+	Fields []FieldError
+	Err    error
+}
+
+func (e *ErrDecode) Error() string {
+	if len(e.Fields) == 0 {
+		return fmt.Sprintf("failed to decode form data: %v", e.Err)
+	}
+	msgs := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		msgs[i] = field.Error()
+	}
+	return fmt.Sprintf("failed to decode form data: %s", strings.Join(msgs, "; "))
+}
+
+func (e *ErrDecode) Unwrap() error {
+	return e.Err
+}
+
+// ErrRenderTimeout represents a Render that did not finish within the registry's
+// render timeout (see SetRenderTimeout).
+type ErrRenderTimeout struct {
+	ComponentName string
+	Timeout       time.Duration
+}
+
+func (e *ErrRenderTimeout) Error() string {
+	return fmt.Sprintf("component '%s' did not render within %s", e.ComponentName, e.Timeout)
+}
+
+// ErrInvalidState represents signed state that failed verification, e.g. because
+// the client changed it.
+type ErrInvalidState struct {
+	Reason string
+}
+
+func (e *ErrInvalidState) Error() string {
+	return "invalid signed state: " + e.Reason
+}
+
+// ErrRender wraps an error returned while rendering a component, recording how many
+// bytes the component had written before it failed. BytesWritten == 0 means the
+// render failed before producing any output; otherwise it failed mid-stream, and
+// without buffered rendering that partial output has already reached the client.
+type ErrRender struct {
+	ComponentName string
+	BytesWritten  int
+	Err           error
+}
+
+func (e *ErrRender) Error() string {
+	return fmt.Sprintf("component '%s' render failed after %d bytes: %v", e.ComponentName, e.BytesWritten, e.Err)
+}
+
+func (e *ErrRender) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,310 @@
+package components_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQuantityComponent has typed fields that can fail to decode
+type TestQuantityComponent struct {
+	Quantity int    `form:"quantity"`
+	Note     string `form:"note"`
+}
+
+func (c *TestQuantityComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>%d</div>", c.Quantity)
+	return nil
+}
+
+func TestErrDecode(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestQuantityComponent](registry, "quantity")
+
+	var reported *components.ComponentError
+	registry.SetOnError(func(ctx context.Context, err *components.ComponentError) {
+		reported = err
+	})
+
+	form := url.Values{"quantity": {"lots"}, "note": {"hello"}}
+	req := httptest.NewRequest(http.MethodPost, "/component/quantity", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	registry.HandlerFor("quantity")(w, req)
+
+	t.Run("responds with 400 naming the field", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Decode Error")
+		assert.Contains(t, w.Body.String(), "field &#39;quantity&#39;")
+	})
+
+	t.Run("OnError hook receives field details", func(t *testing.T) {
+		require.NotNil(t, reported)
+		assert.Equal(t, "quantity", reported.ComponentName)
+		assert.Equal(t, "decode", reported.Operation)
+		assert.Equal(t, http.StatusBadRequest, reported.StatusCode)
+
+		var decodeErr *components.ErrDecode
+		require.True(t, errors.As(reported, &decodeErr))
+		require.Len(t, decodeErr.Fields, 1)
+		assert.Equal(t, "quantity", decodeErr.Fields[0].Field)
+		assert.Equal(t, "lots", decodeErr.Fields[0].Value)
+		assert.Error(t, decodeErr.Fields[0].Err)
+	})
+}
+
+func TestSetOnError(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestPlainPanickingComponent](registry, "plain")
+
+	var operations []string
+	registry.SetOnError(func(ctx context.Context, err *components.ComponentError) {
+		operations = append(operations, err.Operation)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/component/plain", nil)
+	registry.HandlerFor("plain")(httptest.NewRecorder(), req)
+	assert.Equal(t, []string{"panic"}, operations)
+
+	registry.SetOnError(nil)
+	registry.HandlerFor("plain")(httptest.NewRecorder(), req)
+	assert.Len(t, operations, 1)
+}
+
+func TestSetContextErrorHandler(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestQuantityComponent](registry, "quantity")
+
+	var got components.ErrorContext
+	registry.SetContextErrorHandler(func(w http.ResponseWriter, req *http.Request, ec components.ErrorContext) {
+		got = ec
+		w.WriteHeader(ec.Code)
+		fmt.Fprintf(w, "%s failed: %s", ec.ComponentName, ec.Title)
+	})
+
+	t.Run("receives the component name and underlying error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/quantity?quantity=lots", nil)
+		req.Header.Set("HX-Request", "true")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("quantity")(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "quantity failed: Decode Error", w.Body.String())
+		assert.Equal(t, "quantity", got.ComponentName)
+		assert.Equal(t, http.StatusBadRequest, got.Code)
+		assert.True(t, got.IsHTMX)
+
+		var decodeErr *components.ErrDecode
+		require.True(t, errors.As(got.Err, &decodeErr))
+		assert.Equal(t, "quantity", decodeErr.Fields[0].Field)
+	})
+
+	t.Run("reports plain requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/missing", nil)
+		registry.Handler(httptest.NewRecorder(), req)
+
+		assert.Equal(t, "missing", got.ComponentName)
+		assert.False(t, got.IsHTMX)
+		var notFound *components.ErrComponentNotFound
+		assert.True(t, errors.As(got.Err, &notFound))
+	})
+
+	t.Run("SetErrorHandler keeps the original signature", func(t *testing.T) {
+		registry.SetErrorHandler(func(w http.ResponseWriter, req *http.Request, title, message string, code int) {
+			w.WriteHeader(code)
+			fmt.Fprintf(w, "%d %s", code, title)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/component/quantity?quantity=lots", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("quantity")(w, req)
+
+		assert.Equal(t, "400 Decode Error", w.Body.String())
+	})
+}
+
+func TestSetPostDecode(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestLifecycleComponent](registry, "lifecycle")
+	components.Register[*TestQuantityComponent](registry, "quantity")
+
+	var lifecycle *TestLifecycleComponent
+	registry.SetPostDecode(func(ctx context.Context, component any) error {
+		switch c := component.(type) {
+		case *TestLifecycleComponent:
+			lifecycle = c
+			c.Log = append(c.Log, fmt.Sprintf("PostDecode(%d)", c.Value))
+		case *TestQuantityComponent:
+			if c.Quantity < 0 {
+				return fmt.Errorf("quantity must not be negative")
+			}
+			c.Quantity = min(c.Quantity, 100)
+		}
+		return nil
+	})
+
+	t.Run("runs after decode and before Init", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/lifecycle?value=5", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("lifecycle")(w, req)
+
+		require.NotNil(t, lifecycle)
+		require.GreaterOrEqual(t, len(lifecycle.Log), 2)
+		assert.Equal(t, []string{"PostDecode(5)", "Init"}, lifecycle.Log[:2])
+	})
+
+	t.Run("normalizes decoded values", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/quantity?quantity=5000", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("quantity")(w, req)
+
+		assert.Equal(t, "<div>100</div>", w.Body.String())
+	})
+
+	t.Run("error renders the decode error page", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/quantity?quantity=-1", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("quantity")(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Decode Error")
+		assert.Contains(t, w.Body.String(), "Quantity must not be negative")
+	})
+}
+
+func TestErrNotRenderable(t *testing.T) {
+	err := &components.ErrNotRenderable{ComponentName: "widget", Type: "*widgets.Widget"}
+	assert.Equal(t, "component 'widget' (*widgets.Widget) does not implement templ.Component", err.Error())
+}
+
+func TestRegisteredComponentsAlwaysRender(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestQuantityComponent](registry, "quantity")
+
+	var reported *components.ComponentError
+	registry.SetOnError(func(ctx context.Context, err *components.ComponentError) {
+		reported = err
+	})
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/component/quantity?quantity=2", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("quantity")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>2</div>", w.Body.String())
+	}
+	assert.Nil(t, reported)
+}
+
+// TestMisSignedComponent has event handlers with invalid signatures
+type TestMisSignedComponent struct{}
+
+func (c *TestMisSignedComponent) OnIncrement(ctx context.Context, step int) error {
+	return nil
+}
+
+func (c *TestMisSignedComponent) OnReset(ctx context.Context) {}
+
+func (c *TestMisSignedComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, "<div>mis-signed</div>")
+	return err
+}
+
+func TestErrEventSignature(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestMisSignedComponent](registry, "missigned")
+
+	var reported error
+	registry.SetOnError(func(ctx context.Context, err *components.ComponentError) {
+		reported = err.Err
+	})
+
+	dispatch := func(event string) *httptest.ResponseRecorder {
+		reported = nil
+		req := httptest.NewRequest(http.MethodGet, "/component/missigned?hxc-event="+event, nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("missigned")(w, req)
+		return w
+	}
+
+	t.Run("extra parameter", func(t *testing.T) {
+		w := dispatch("increment")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var sigErr *components.ErrEventSignature
+		require.ErrorAs(t, reported, &sigErr)
+		assert.Equal(t, "missigned", sigErr.ComponentName)
+		assert.Equal(t, "OnIncrement", sigErr.MethodName)
+		assert.Equal(t, "func(context.Context, int) error", sigErr.Actual)
+		assert.Contains(t, sigErr.Expected, "func(context.Context) error")
+		assert.Contains(t, w.Body.String(), "OnIncrement")
+	})
+
+	t.Run("missing error result", func(t *testing.T) {
+		dispatch("reset")
+
+		var sigErr *components.ErrEventSignature
+		require.ErrorAs(t, reported, &sigErr)
+		assert.Equal(t, "func(context.Context)", sigErr.Actual)
+	})
+
+	t.Run("unknown event is still ErrEventNotFound", func(t *testing.T) {
+		dispatch("launch")
+
+		var notFound *components.ErrEventNotFound
+		assert.ErrorAs(t, reported, &notFound)
+	})
+
+	t.Run("SimulateEvent reports the same error", func(t *testing.T) {
+		err := components.SimulateEvent(context.Background(), &TestMisSignedComponent{}, "increment")
+
+		var sigErr *components.ErrEventSignature
+		require.ErrorAs(t, err, &sigErr)
+		assert.Equal(t, "*components_test.TestMisSignedComponent", sigErr.ComponentName)
+	})
+}
+
+// TestFailingRenderComponent writes part of its output and then fails
+type TestFailingRenderComponent struct{}
+
+func (c *TestFailingRenderComponent) Render(ctx context.Context, w io.Writer) error {
+	if _, err := io.WriteString(w, "<div>partial"); err != nil {
+		return err
+	}
+	return errors.New("template exploded")
+}
+
+func TestErrRender(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestFailingRenderComponent](registry, "partial")
+
+	var reported *components.ComponentError
+	registry.SetOnError(func(ctx context.Context, err *components.ComponentError) {
+		reported = err
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/component/partial", nil)
+	w := httptest.NewRecorder()
+	registry.HandlerFor("partial")(w, req)
+
+	require.NotNil(t, reported)
+	assert.Equal(t, "render", reported.Operation)
+
+	var renderErr *components.ErrRender
+	require.ErrorAs(t, reported, &renderErr)
+	assert.Equal(t, "partial", renderErr.ComponentName)
+	assert.Equal(t, len("<div>partial"), renderErr.BytesWritten)
+	assert.EqualError(t, renderErr.Err, "template exploded")
+	assert.Contains(t, w.Body.String(), "Component rendering failed: template exploded")
+}
@@ -0,0 +1,92 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPolledComponent renders a status and sets an HX-Trigger header
+type TestPolledComponent struct {
+	Status string `form:"status"`
+}
+
+func (c *TestPolledComponent) GetHxTrigger() string {
+	return "statusChecked"
+}
+
+func (c *TestPolledComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>Status: %s</div>", c.Status)
+	return nil
+}
+
+func TestWithETag(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestPolledComponent](registry, "status", components.WithETag())
+	components.Register[*TestPolledComponent](registry, "plain")
+
+	get := func(name, status, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/component/"+name+"?status="+status, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		registry.HandlerFor(name)(w, req)
+		return w
+	}
+
+	first := get("status", "ok", "")
+	etag := first.Header().Get("ETag")
+
+	t.Run("sets ETag header", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, first.Code)
+		assert.Regexp(t, `^"[0-9a-f]+"$`, etag)
+		assert.Contains(t, first.Body.String(), "Status: ok")
+	})
+
+	t.Run("returns 304 when If-None-Match matches", func(t *testing.T) {
+		w := get("status", "ok", etag)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+		assert.Equal(t, "statusChecked", w.Header().Get("HX-Trigger"))
+	})
+
+	t.Run("matches weak and listed ETags", func(t *testing.T) {
+		w := get("status", "ok", `"other", W/`+etag)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+
+	t.Run("returns 200 when the content changed", func(t *testing.T) {
+		w := get("status", "degraded", etag)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, etag, w.Header().Get("ETag"))
+		assert.Contains(t, w.Body.String(), "Status: degraded")
+		assert.Equal(t, "statusChecked", w.Header().Get("HX-Trigger"))
+	})
+
+	t.Run("HEAD returns the ETag and headers without a body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodHead, "/component/status?status=ok", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("status")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+		assert.Equal(t, "statusChecked", w.Header().Get("HX-Trigger"))
+		assert.Equal(t, fmt.Sprint(first.Body.Len()), w.Header().Get("Content-Length"))
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("components without WithETag are unaffected", func(t *testing.T) {
+		w := get("plain", "ok", etag)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+	})
+}
@@ -1,6 +1,12 @@
 package components
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/a-h/templ"
+)
 
 // BeforeEventHandler is an optional interface that components can implement to perform
 // logic before any event handler is called. This is useful for loading data, authentication,
@@ -68,3 +74,80 @@ type BeforeEventHandler interface {
 type AfterEventHandler interface {
 	AfterEvent(ctx context.Context, eventName string) error
 }
+
+// Per-event hooks: besides the BeforeEvent and AfterEvent hooks that run for every
+// event, a component can declare BeforeOn{Event} and AfterOn{Event} methods, found
+// by name like the On{Event} handlers, that run only around that one handler:
+//
+//	func (c *Cart) BeforeOnCheckout(ctx context.Context) error {
+//	    return c.reserveStock(ctx)
+//	}
+//
+//	func (c *Cart) AfterOnCheckout(ctx context.Context) error {
+//	    return c.sendConfirmation(ctx)
+//	}
+//
+// The order is BeforeEvent, BeforeOn{Event}, On{Event}, AfterOn{Event},
+// AfterEvent. An error from any of them stops the rest. The hooks follow the event
+// method prefix, e.g. BeforeHandleCheckout after SetEventMethodPrefix("Handle").
+
+// eventHookSignature is the accepted signature of BeforeOn{Event} and AfterOn{Event} methods.
+const eventHookSignature = "func(context.Context) error"
+
+// eventSignatures describes the accepted event handler signatures, as reported by ErrEventSignature.
+const eventSignatures = "func(context.Context) error, func(context.Context) (templ.Component, error) or func(context.Context) (*EventResult, error)"
+
+// checkEventSignature verifies that method (bound to its receiver) can be called as
+// an event handler: On{Event}(ctx context.Context) error,
+// On{Event}(ctx context.Context) (templ.Component, error) or
+// On{Event}(ctx context.Context) (*EventResult, error).
+func checkEventSignature(componentName, methodName string, method reflect.Type) error {
+	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	errType := reflect.TypeOf((*error)(nil)).Elem()
+	componentType := reflect.TypeOf((*templ.Component)(nil)).Elem()
+
+	valid := method.NumIn() == 1 && method.In(0).Implements(ctxType)
+	switch method.NumOut() {
+	case 1:
+		valid = valid && method.Out(0) == errType
+	case 2:
+		valid = valid && (method.Out(0) == componentType || method.Out(0) == eventResultType) && method.Out(1) == errType
+	default:
+		valid = false
+	}
+	if valid {
+		return nil
+	}
+	return &ErrEventSignature{
+		ComponentName: componentName,
+		MethodName:    methodName,
+		Expected:      eventSignatures,
+		Actual:        method.String(),
+	}
+}
+
+// callEventHook calls the per-event hook methodName (e.g. BeforeOnIncrement) on
+// instance if it has one. A hook with a signature other than
+// func(ctx context.Context) error fails with an *ErrEventSignature.
+func callEventHook(ctx context.Context, instance reflect.Value, componentName, eventName, methodName string) error {
+	method := instance.MethodByName(methodName)
+	if !method.IsValid() {
+		return nil
+	}
+	hook, ok := method.Interface().(func(context.Context) error)
+	if !ok {
+		return &ErrEventSignature{
+			ComponentName: componentName,
+			MethodName:    methodName,
+			Expected:      eventHookSignature,
+			Actual:        method.Type().String(),
+		}
+	}
+	if err := eventContextErr(ctx, eventName, methodName); err != nil {
+		return err
+	}
+	if err := hook(ctx); err != nil {
+		return fmt.Errorf("%s failed: %w", methodName, err)
+	}
+	return nil
+}
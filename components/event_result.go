@@ -0,0 +1,82 @@
+package components
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// EventResult lets a single event handler shape the response, without the whole
+// component implementing the response header interfaces. Return it from an event
+// handler with the signature On{Event}(ctx context.Context) (*EventResult, error):
+//
+//	func (c *TodoItem) OnDelete(ctx context.Context) (*components.EventResult, error) {
+//	    if err := c.store.Delete(ctx, c.ID); err != nil {
+//	        return nil, err
+//	    }
+//	    return &components.EventResult{Retarget: "#todo-list", Reswap: "outerHTML"}, nil
+//	}
+//
+// Empty fields are ignored, and set fields take precedence over the values of the
+// component's HxRetargetResponse, HxReswapResponse and HxTriggerResponse methods.
+// A nil *EventResult behaves like an event handler that returns only error.
+// WebSocketHandler has no response headers, so it ignores EventResult.
+type EventResult struct {
+	// Retarget sets HX-Retarget, a CSS selector for the element to swap into.
+	Retarget string
+	// Reswap sets HX-Reswap, e.g. "outerHTML" or "beforeend".
+	Reswap string
+	// Trigger sets HX-Trigger, the client-side event(s) to fire.
+	Trigger string
+	// StatusCode replaces the 200 OK status of the rendered response.
+	StatusCode int
+}
+
+var eventResultType = reflect.TypeOf((*EventResult)(nil))
+
+// applyHeaders sets the HX-* response headers requested by res.
+func (res *EventResult) applyHeaders(h http.Header) {
+	if res == nil {
+		return
+	}
+	if res.Retarget != "" {
+		h.Set("HX-Retarget", res.Retarget)
+	}
+	if res.Reswap != "" {
+		h.Set("HX-Reswap", res.Reswap)
+	}
+	if res.Trigger != "" {
+		h.Set("HX-Trigger", res.Trigger)
+	}
+}
+
+// statusCodeWriter sends code instead of 200 OK, whether the status is written
+// explicitly or implicitly by the first Write. Other statuses, such as 304 Not
+// Modified or an error status, pass through unchanged.
+type statusCodeWriter struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (s *statusCodeWriter) WriteHeader(code int) {
+	if s.wroteHeader {
+		return
+	}
+	s.wroteHeader = true
+	if code == http.StatusOK {
+		code = s.code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (s *statusCodeWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+func (s *statusCodeWriter) Write(p []byte) (int, error) {
+	if !s.wroteHeader {
+		s.WriteHeader(http.StatusOK)
+	}
+	return s.ResponseWriter.Write(p)
+}
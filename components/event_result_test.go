@@ -0,0 +1,97 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTodoItemComponent returns an EventResult from its event handlers
+type TestTodoItemComponent struct {
+	ID      int  `form:"id"`
+	Deleted bool `json:"-"`
+}
+
+func (c *TestTodoItemComponent) OnDelete(ctx context.Context) (*components.EventResult, error) {
+	c.Deleted = true
+	return &components.EventResult{
+		Retarget:   "#todo-list",
+		Reswap:     "beforeend",
+		Trigger:    "todoDeleted",
+		StatusCode: http.StatusAccepted,
+	}, nil
+}
+
+func (c *TestTodoItemComponent) OnArchive(ctx context.Context) (*components.EventResult, error) {
+	return nil, nil
+}
+
+func (c *TestTodoItemComponent) OnFail(ctx context.Context) (*components.EventResult, error) {
+	return &components.EventResult{Retarget: "#ignored"}, fmt.Errorf("todo is locked")
+}
+
+func (c *TestTodoItemComponent) GetHxRetarget() string {
+	return "#todo-item"
+}
+
+func (c *TestTodoItemComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "<li>%d deleted=%t</li>", c.ID, c.Deleted)
+	return err
+}
+
+func TestEventResult(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestTodoItemComponent](registry, "todo-item")
+
+	post := func(event string) *httptest.ResponseRecorder {
+		form := url.Values{"id": {"7"}, "hxc-event": {event}}
+		req := httptest.NewRequest(http.MethodPost, "/component/todo-item", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("todo-item")(w, req)
+		return w
+	}
+
+	t.Run("applies retarget, reswap, trigger and status code", func(t *testing.T) {
+		w := post("delete")
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "#todo-list", w.Header().Get("HX-Retarget"))
+		assert.Equal(t, "beforeend", w.Header().Get("HX-Reswap"))
+		assert.Equal(t, "todoDeleted", w.Header().Get("HX-Trigger"))
+		assert.Equal(t, "<li>7 deleted=true</li>", w.Body.String())
+	})
+
+	t.Run("nil result keeps the component's headers", func(t *testing.T) {
+		w := post("archive")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "#todo-item", w.Header().Get("HX-Retarget"))
+		assert.Empty(t, w.Header().Get("HX-Reswap"))
+	})
+
+	t.Run("error ignores the result", func(t *testing.T) {
+		w := post("fail")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotEqual(t, "#ignored", w.Header().Get("HX-Retarget"))
+		assert.Contains(t, w.Body.String(), "todo is locked")
+	})
+
+	t.Run("events are discovered", func(t *testing.T) {
+		info, err := registry.GetComponentInfo("todo-item")
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"archive", "delete", "fail"}, info.Events)
+	})
+
+	t.Run("SimulateEvent accepts the signature", func(t *testing.T) {
+		component := &TestTodoItemComponent{ID: 3}
+		assert.NoError(t, components.SimulateEvent(context.Background(), component, "delete"))
+		assert.True(t, component.Deleted)
+	})
+}
@@ -0,0 +1,197 @@
+package components
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultFlashCookieName is the cookie that identifies a pending flash message.
+const DefaultFlashCookieName = "hxc_flash"
+
+// DefaultFlashTTL is how long a flash message waits to be consumed.
+const DefaultFlashTTL = 5 * time.Minute
+
+// Flasher is an optional interface for components that pass a one-time message to
+// the page they redirect to, e.g. "Welcome back" after a login. When Flash returns
+// a non-empty message once Process has succeeded, the registry stores it in the
+// registry's FlashStore and sets a cookie identifying it; the destination reads it
+// with Registry.ConsumeFlash or a FlashReceiver component.
+//
+//	func (c *LoginComponent) Flash() (string, string) {
+//	    if c.RedirectTo == "" {
+//	        return "", ""
+//	    }
+//	    return "success", "Welcome back, " + c.Username
+//	}
+type Flasher interface {
+	Flash() (level, message string)
+}
+
+// FlashStore holds flash messages by ID until they are consumed. Implementations
+// must be safe for concurrent use. Use Registry.SetFlashStore to share flashes
+// between server instances, e.g. in a session store or cache.
+type FlashStore interface {
+	// Put stores a flash message under id.
+	Put(ctx context.Context, id, level, message string) error
+	// Take returns and removes the flash message stored under id, or ok=false if
+	// there is none.
+	Take(ctx context.Context, id string) (level, message string, ok bool, err error)
+}
+
+type flashMessage struct {
+	level   string
+	message string
+	expires time.Time
+}
+
+// MemoryFlashStore is an in-memory FlashStore. Messages that are not consumed
+// within its TTL are discarded.
+type MemoryFlashStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	flashes map[string]flashMessage
+}
+
+// NewMemoryFlashStore creates an empty in-memory flash store whose messages expire
+// after ttl (DefaultFlashTTL if ttl <= 0).
+func NewMemoryFlashStore(ttl time.Duration) *MemoryFlashStore {
+	if ttl <= 0 {
+		ttl = DefaultFlashTTL
+	}
+	return &MemoryFlashStore{ttl: ttl, flashes: make(map[string]flashMessage)}
+}
+
+// Put stores a flash message under id, discarding expired messages.
+func (s *MemoryFlashStore) Put(ctx context.Context, id, level, message string) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, flash := range s.flashes {
+		if now.After(flash.expires) {
+			delete(s.flashes, key)
+		}
+	}
+	s.flashes[id] = flashMessage{level: level, message: message, expires: now.Add(s.ttl)}
+	return nil
+}
+
+// Take returns and removes the flash message stored under id.
+func (s *MemoryFlashStore) Take(ctx context.Context, id string) (string, string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flash, ok := s.flashes[id]
+	if !ok {
+		return "", "", false, nil
+	}
+	delete(s.flashes, id)
+	if time.Now().After(flash.expires) {
+		return "", "", false, nil
+	}
+	return flash.level, flash.message, true, nil
+}
+
+// SetFlashStore sets the store used for Flasher messages.
+// Passing nil restores the in-memory default.
+func (r *Registry) SetFlashStore(store FlashStore) {
+	if store == nil {
+		store = NewMemoryFlashStore(DefaultFlashTTL)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flashStore = store
+}
+
+// flashes returns the registry's flash store.
+func (r *Registry) flashes() FlashStore {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.flashStore
+}
+
+// setFlash stores the message of a Flasher component, if any, and sets the
+// cookie identifying it. The cookie holds only a random ID, so the message
+// cannot be read or forged by the client.
+func (r *Registry) setFlash(w http.ResponseWriter, req *http.Request, instance any, componentName string) {
+	flasher, ok := instance.(Flasher)
+	if !ok {
+		return
+	}
+	level, message := flasher.Flash()
+	if message == "" {
+		return
+	}
+	id, err := generateCSRFToken()
+	if err == nil {
+		err = r.flashes().Put(req.Context(), id, level, message)
+	}
+	if err != nil {
+		r.logger().Error("failed to store flash message",
+			"component", componentName,
+			"error", err)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     DefaultFlashCookieName,
+		Value:    id,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   req.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// FlashReceiver is an optional interface for components that display a flash
+// message, e.g. a banner loaded on the page a Flasher redirected to. When the
+// request carries a pending flash, the registry consumes it after Authorize and
+// passes it to SetFlash, so each message is delivered exactly once.
+//
+//	func (c *BannerComponent) SetFlash(level, message string) {
+//	    c.Level, c.Message = level, message
+//	}
+type FlashReceiver interface {
+	SetFlash(level, message string)
+}
+
+// ConsumeFlash returns the flash message set for the request's client by a
+// Flasher component and removes it, so each message is returned exactly once.
+// The flash cookie is expired in w. Use it in page handlers outside the registry:
+//
+//	router.Get("/dashboard", func(w http.ResponseWriter, req *http.Request) {
+//	    level, message, ok := registry.ConsumeFlash(w, req)
+//	    ...
+//	})
+func (r *Registry) ConsumeFlash(w http.ResponseWriter, req *http.Request) (level, message string, ok bool) {
+	cookie, err := req.Cookie(DefaultFlashCookieName)
+	if err != nil || cookie.Value == "" {
+		return "", "", false
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     DefaultFlashCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   req.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	level, message, ok, err = r.flashes().Take(req.Context(), cookie.Value)
+	if err != nil {
+		r.logger().Error("failed to load flash message", "error", err)
+		return "", "", false
+	}
+	return level, message, ok
+}
+
+// deliverFlash consumes the request's flash message, if any, into a
+// FlashReceiver component.
+func (r *Registry) deliverFlash(w http.ResponseWriter, req *http.Request, instance any) {
+	receiver, ok := instance.(FlashReceiver)
+	if !ok {
+		return
+	}
+	if level, message, ok := r.ConsumeFlash(w, req); ok {
+		receiver.SetFlash(level, message)
+	}
+}
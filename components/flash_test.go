@@ -0,0 +1,133 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TestFlashLoginComponent struct {
+	Username   string `form:"username"`
+	RedirectTo string `json:"-"`
+}
+
+func (c *TestFlashLoginComponent) Process(ctx context.Context) error {
+	if c.Username != "" {
+		c.RedirectTo = "/dashboard"
+	}
+	return nil
+}
+
+func (c *TestFlashLoginComponent) GetHxRedirect() string {
+	return c.RedirectTo
+}
+
+func (c *TestFlashLoginComponent) Flash() (string, string) {
+	if c.RedirectTo == "" {
+		return "", ""
+	}
+	return "success", "Welcome back, " + c.Username
+}
+
+func (c *TestFlashLoginComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, "<form></form>")
+	return err
+}
+
+type TestFlashBannerComponent struct {
+	level   string
+	message string
+}
+
+func (c *TestFlashBannerComponent) SetFlash(level, message string) {
+	c.level, c.message = level, message
+}
+
+func (c *TestFlashBannerComponent) Render(ctx context.Context, w io.Writer) error {
+	if c.message != "" {
+		fmt.Fprintf(w, "<div class=%q>%s</div>", c.level, c.message)
+	}
+	return nil
+}
+
+func TestFlash(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestFlashLoginComponent](registry, "login")
+	components.Register[*TestFlashBannerComponent](registry, "banner")
+
+	login := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("login")(w, req)
+		return w
+	}
+	flashCookie := func(w *httptest.ResponseRecorder) *http.Cookie {
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == components.DefaultFlashCookieName {
+				return cookie
+			}
+		}
+		return nil
+	}
+
+	t.Run("login sets a flash consumed exactly once", func(t *testing.T) {
+		w := login("username=alice")
+		assert.Equal(t, "/dashboard", w.Header().Get("HX-Redirect"))
+		cookie := flashCookie(w)
+		require.NotNil(t, cookie)
+		assert.NotContains(t, cookie.Value, "Welcome")
+		assert.True(t, cookie.HttpOnly)
+
+		req := httptest.NewRequest(http.MethodGet, "/dashboard", nil)
+		req.AddCookie(cookie)
+		w = httptest.NewRecorder()
+		level, message, ok := registry.ConsumeFlash(w, req)
+		assert.True(t, ok)
+		assert.Equal(t, "success", level)
+		assert.Equal(t, "Welcome back, alice", message)
+		expired := flashCookie(w)
+		require.NotNil(t, expired)
+		assert.Equal(t, -1, expired.MaxAge)
+
+		_, _, ok = registry.ConsumeFlash(httptest.NewRecorder(), req)
+		assert.False(t, ok)
+	})
+
+	t.Run("FlashReceiver component consumes the flash", func(t *testing.T) {
+		cookie := flashCookie(login("username=bob"))
+		require.NotNil(t, cookie)
+
+		render := func() *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/component/banner", nil)
+			req.AddCookie(cookie)
+			w := httptest.NewRecorder()
+			registry.HandlerFor("banner")(w, req)
+			return w
+		}
+		w := render()
+		assert.Equal(t, `<div class="success">Welcome back, bob</div>`, w.Body.String())
+		require.NotNil(t, flashCookie(w))
+		assert.Equal(t, -1, flashCookie(w).MaxAge)
+		assert.Empty(t, render().Body.String())
+	})
+
+	t.Run("no flash without a message", func(t *testing.T) {
+		assert.Nil(t, flashCookie(login("username=")))
+	})
+
+	t.Run("requests without the cookie have no flash", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		_, _, ok := registry.ConsumeFlash(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.False(t, ok)
+		assert.Nil(t, flashCookie(w))
+	})
+}
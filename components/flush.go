@@ -0,0 +1,60 @@
+package components
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Flushable is an optional interface for components that render with Render but
+// need the client to receive part of the response before the rest is ready, such
+// as a long-polling component that writes a placeholder and then waits for a slow
+// source. Before rendering, the registry calls SetFlush with a function that sends
+// everything written so far to the client.
+//
+// The flush function degrades gracefully: it does nothing and returns nil when the
+// underlying ResponseWriter does not support flushing, and when the response is
+// buffered (WithBufferedRender, WithETag, WithCache, SetRenderTimeout, HEAD
+// requests and debug traces), since nothing reaches the client until rendering
+// finishes. Other errors, such as a disconnected client, are returned.
+//
+// A server's WriteTimeout bounds the whole response, including time spent between
+// flushes, so servers with long-polling components need a WriteTimeout longer
+// than their longest wait (or none, relying on the request context instead).
+//
+// Example:
+//
+//	type LivePrice struct {
+//	    flush func() error
+//	}
+//
+//	func (c *LivePrice) SetFlush(flush func() error) { c.flush = flush }
+//
+//	func (c *LivePrice) Render(ctx context.Context, w io.Writer) error {
+//	    if err := Spinner().Render(ctx, w); err != nil {
+//	        return err
+//	    }
+//	    if err := c.flush(); err != nil {
+//	        return err
+//	    }
+//	    return PriceRow(c.waitForPrice(ctx)).Render(ctx, w)
+//	}
+type Flushable interface {
+	SetFlush(flush func() error)
+}
+
+// responseFlusher returns a function that flushes w. Wrapped writers are
+// unwrapped via http.ResponseController, and a writer that cannot flush is
+// treated as a successful no-op.
+func responseFlusher(w http.ResponseWriter) func() error {
+	rc := http.NewResponseController(w)
+	return func() error {
+		if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+}
+
+// noFlush is the flush function given to Flushable components whose response is
+// buffered.
+func noFlush() error { return nil }
@@ -0,0 +1,67 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLongPollComponent writes a placeholder and flushes it before the result
+type TestLongPollComponent struct {
+	flush func() error
+}
+
+func (c *TestLongPollComponent) SetFlush(flush func() error) { c.flush = flush }
+
+func (c *TestLongPollComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprint(w, "<div>waiting</div>")
+	if err := c.flush(); err != nil {
+		return err
+	}
+	fmt.Fprint(w, "<div>result</div>")
+	return nil
+}
+
+// unflushableWriter hides the recorder's Flush method
+type unflushableWriter struct {
+	http.ResponseWriter
+}
+
+func TestFlushable(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestLongPollComponent](registry, "poll")
+	components.Register[*TestLongPollComponent](registry, "poll-buffered", components.WithBufferedRender())
+
+	t.Run("flushes the output written so far", func(t *testing.T) {
+		w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		registry.HandlerFor("poll")(w, httptest.NewRequest(http.MethodGet, "/component/poll", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []int{len("<div>waiting</div>")}, w.flushedAt)
+		assert.Equal(t, "<div>waiting</div><div>result</div>", w.Body.String())
+	})
+
+	t.Run("writer without flush support", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.HandlerFor("poll")(unflushableWriter{rec}, httptest.NewRequest(http.MethodGet, "/component/poll", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, rec.Flushed)
+		assert.Equal(t, "<div>waiting</div><div>result</div>", rec.Body.String())
+	})
+
+	t.Run("buffered response is not flushed", func(t *testing.T) {
+		w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		registry.HandlerFor("poll-buffered")(w, httptest.NewRequest(http.MethodGet, "/component/poll-buffered", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.flushedAt)
+		assert.Equal(t, "<div>waiting</div><div>result</div>", w.Body.String())
+	})
+}
@@ -1,6 +1,17 @@
 package components
 
-import "github.com/go-playground/form/v4"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/form/v4"
+)
 
 // FormDecoder is an optional interface that components can implement to provide
 // a custom form decoder. This allows components to configure form decoding behavior,
@@ -36,3 +47,188 @@ import "github.com/go-playground/form/v4"
 type FormDecoder interface {
 	GetFormDecoder() *form.Decoder
 }
+
+// newDecodeError wraps a decoder error in an *ErrDecode, extracting per-field
+// failures from form.DecodeErrors along with the submitted values.
+func newDecodeError(err error, values map[string][]string) *ErrDecode {
+	decodeErr := &ErrDecode{Err: err}
+
+	var fieldErrs form.DecodeErrors
+	if errors.As(err, &fieldErrs) {
+		for field, fieldErr := range fieldErrs {
+			decodeErr.Fields = append(decodeErr.Fields, FieldError{
+				Field: field,
+				Value: strings.Join(values[field], ","),
+				Err:   fieldErr,
+			})
+		}
+		sort.Slice(decodeErr.Fields, func(i, j int) bool {
+			return decodeErr.Fields[i].Field < decodeErr.Fields[j].Field
+		})
+	}
+	return decodeErr
+}
+
+// mergeFormValues combines POST body and query parameters into one set of values.
+// When a field appears in both, the body's values are used unless queryWins is set.
+func mergeFormValues(body, query url.Values, queryWins bool) url.Values {
+	primary, secondary := body, query
+	if queryWins {
+		primary, secondary = query, body
+	}
+	merged := make(url.Values, len(body)+len(query))
+	for key, values := range secondary {
+		merged[key] = values
+	}
+	for key, values := range primary {
+		merged[key] = values
+	}
+	return merged
+}
+
+// buildDecoderLocked builds the component's registration-time decoder from the
+// options passed to WithDecoderTagName and WithDecoderMode and the registry's
+// current time formats. Components without such options use the registry's
+// decoder. r.mu must be held.
+func (r *Registry) buildDecoderLocked(e *componentEntry) {
+	if len(e.decoderConfig) == 0 {
+		return
+	}
+	e.decoder = newFormDecoder(r.timeFormats)
+	for _, configure := range e.decoderConfig {
+		configure(e.decoder)
+	}
+}
+
+// formDecoder returns the decoder for a component instance: its own FormDecoder if
+// implemented, then the decoder configured at registration, then fallback (the
+// registry's default decoder).
+func (e componentEntry) formDecoder(instance any, fallback *form.Decoder) *form.Decoder {
+	if customDecoder, ok := instance.(FormDecoder); ok {
+		return customDecoder.GetFormDecoder()
+	}
+	if e.decoder != nil {
+		return e.decoder
+	}
+	return fallback
+}
+
+// sortedKeys returns the field names of form data in alphabetical order.
+func sortedKeys(values map[string][]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// countingReader counts the bytes read from a request body, for the debug
+// mode X-HxComponent-BodySize header.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// parseForm reports whether the request body is parsed and decoded into the
+// component, i.e. it was not registered WithoutFormParsing.
+func (e componentEntry) parseForm() bool {
+	return !e.skipFormParse
+}
+
+// checkFormKeys rejects form field names whose slice indexes exceed maxIndex or
+// whose nesting exceeds maxDepth, before the decoder allocates for them. A limit
+// <= 0 is not enforced.
+func checkFormKeys(values map[string][]string, maxIndex, maxDepth int) error {
+	for key := range values {
+		depth := strings.Count(key, ".") + strings.Count(key, "[")
+		if maxDepth > 0 && depth > maxDepth {
+			return fmt.Errorf("form field %q is nested %d levels deep, exceeding the limit of %d", key, depth, maxDepth)
+		}
+		if maxIndex <= 0 {
+			continue
+		}
+		for rest := key; ; {
+			start := strings.IndexByte(rest, '[')
+			if start < 0 {
+				break
+			}
+			end := strings.IndexByte(rest[start:], ']')
+			if end < 0 {
+				break
+			}
+			index := rest[start+1 : start+end]
+			rest = rest[start+end+1:]
+			if !isDigits(index) {
+				// Map keys are not indexes
+				continue
+			}
+			if n, err := strconv.Atoi(index); err != nil || n > maxIndex {
+				return fmt.Errorf("form field %q has index %s, exceeding the limit of %d", key, index, maxIndex)
+			}
+		}
+	}
+	return nil
+}
+
+// checkUnknownFields returns an *ErrUnknownFields listing the form fields whose
+// top-level name is neither a `form`-tagged field of structType nor in allowed.
+func checkUnknownFields(values map[string][]string, structType reflect.Type, allowed ...string) error {
+	known := make(map[string]bool)
+	for _, name := range discoverFormFields(structType) {
+		known[name] = true
+	}
+	for _, name := range allowed {
+		known[name] = true
+	}
+
+	var unknown []string
+	for _, key := range sortedKeys(values) {
+		name := key
+		if i := strings.IndexAny(key, ".["); i >= 0 {
+			name = key[:i]
+		}
+		if !known[name] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		return &ErrUnknownFields{Fields: unknown}
+	}
+	return nil
+}
+
+// frameworkFields returns the form fields the registry itself reads, which are
+// never unknown to a component.
+func (r *Registry) frameworkFields(eventParam string) []string {
+	fields := []string{eventParam, TraceParam}
+	if csrf := r.csrfConfig(); csrf != nil {
+		fields = append(fields, csrf.FieldName)
+	}
+	r.mu.RLock()
+	methodField := r.methodField
+	r.mu.RUnlock()
+	if methodField != "" {
+		fields = append(fields, methodField)
+	}
+	return fields
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,252 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostMergesQueryParameters(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestPagedComponent](registry, "paged")
+
+	post := func(target, body string) string {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("paged")(w, req)
+		return w.Body.String()
+	}
+
+	t.Run("decodes body and query fields", func(t *testing.T) {
+		assert.Equal(t, "<div>go limit=5</div>", post("/component/paged?limit=5", "q=go"))
+	})
+
+	t.Run("body wins by default", func(t *testing.T) {
+		assert.Equal(t, "<div>go limit=3</div>", post("/component/paged?limit=5", "q=go&limit=3"))
+	})
+
+	t.Run("query wins when configured", func(t *testing.T) {
+		registry.SetQueryPrecedence(true)
+		defer registry.SetQueryPrecedence(false)
+
+		assert.Equal(t, "<div>go limit=5</div>", post("/component/paged?limit=5", "q=go&limit=3"))
+	})
+}
+
+func TestSetMaxBodySize(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestPagedComponent](registry, "paged")
+	registry.SetMaxBodySize(64)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/paged", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("paged")(w, req)
+		return w
+	}
+
+	t.Run("body under the limit succeeds", func(t *testing.T) {
+		w := post("q=go&limit=3")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>go limit=3</div>", w.Body.String())
+	})
+
+	t.Run("body over the limit is rejected", func(t *testing.T) {
+		w := post("q=" + strings.Repeat("x", 100))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), "Request Entity Too Large")
+	})
+}
+
+// TestRawBodyComponent reads the request body itself
+type TestRawBodyComponent struct {
+	Name  string `form:"name"`
+	Body  string `json:"-"`
+	Event string `json:"-"`
+	req   *http.Request
+}
+
+func (c *TestRawBodyComponent) SetRequest(req *http.Request) {
+	c.req = req
+}
+
+func (c *TestRawBodyComponent) OnImport(ctx context.Context) error {
+	body, err := io.ReadAll(c.req.Body)
+	if err != nil {
+		return err
+	}
+	c.Body = string(body)
+	c.Event = "import"
+	return nil
+}
+
+func (c *TestRawBodyComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>name=%q event=%s body=%s</div>", c.Name, c.Event, c.Body)
+	return nil
+}
+
+func TestWithoutFormParsing(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestRawBodyComponent](registry, "raw", components.WithoutFormParsing())
+
+	t.Run("component receives the unread body", func(t *testing.T) {
+		body := `{"name":"ada"}`
+		req := httptest.NewRequest(http.MethodPost, "/component/raw?hxc-event=import", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("raw")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `<div>name="" event=import body={"name":"ada"}</div>`, w.Body.String())
+	})
+
+	t.Run("unparseable content types are not rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/component/raw?hxc-event=import", strings.NewReader("a,b,c"))
+		req.Header.Set("Content-Type", "multipart/form-data")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("raw")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "body=a,b,c")
+	})
+}
+
+// TestIndexedComponent decodes indexed and nested form fields
+type TestIndexedComponent struct {
+	Tags  []string          `form:"tags"`
+	Attrs map[string]string `form:"attrs"`
+}
+
+func (c *TestIndexedComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>%v %v</div>", c.Tags, c.Attrs)
+	return nil
+}
+
+func TestFormKeyLimits(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestIndexedComponent](registry, "indexed")
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/indexed", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("indexed")(w, req)
+		return w
+	}
+
+	t.Run("normal indexed fields decode", func(t *testing.T) {
+		w := post("tags[0]=a&tags[1]=b&attrs[color]=red")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>[a b] map[color:red]</div>", w.Body.String())
+	})
+
+	t.Run("huge index is rejected", func(t *testing.T) {
+		w := post("tags[999999999]=a")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "exceeding the limit of 1000")
+	})
+
+	t.Run("index overflowing int is rejected", func(t *testing.T) {
+		w := post("tags[99999999999999999999999]=a")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("deep nesting is rejected", func(t *testing.T) {
+		w := post("attrs" + strings.Repeat("[x]", 9) + "=a")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "nested 9 levels deep")
+	})
+
+	t.Run("limits are configurable", func(t *testing.T) {
+		registry.SetMaxFormIndex(1)
+		registry.SetMaxFormDepth(0)
+		defer registry.SetMaxFormIndex(components.DefaultMaxFormIndex)
+		defer registry.SetMaxFormDepth(components.DefaultMaxFormDepth)
+
+		assert.Equal(t, http.StatusBadRequest, post("tags[2]=a").Code)
+		assert.Equal(t, http.StatusOK, post("tags[1]=a").Code)
+		assert.Equal(t, http.StatusOK, post("attrs"+strings.Repeat("[x]", 9)+"=a").Code)
+	})
+}
+
+type TestJSONTodoItem struct {
+	ID   int
+	Text string
+	Done bool
+}
+
+// TestJSONFieldComponent keeps its items as JSON in a single form field
+type TestJSONFieldComponent struct {
+	Items   []TestJSONTodoItem `form:"items,json"`
+	Filters map[string]string  `form:"filters,json"`
+	Title   string             `form:"title"`
+}
+
+func (c *TestJSONFieldComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s: %+v %v", c.Title, c.Items, c.Filters)
+	return err
+}
+
+func TestJSONFormFields(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestJSONFieldComponent](registry, "todos")
+
+	post := func(values url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/todos", strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("todos")(w, req)
+		return w
+	}
+
+	t.Run("JSON array decodes without a manual step", func(t *testing.T) {
+		w := post(url.Values{
+			"title":   {"Chores"},
+			"items":   {`[{"ID":1,"Text":"Wash up","Done":true},{"ID":2,"Text":"Shop"}]`},
+			"filters": {`{"status":"open"}`},
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Chores: [{ID:1 Text:Wash up Done:true} {ID:2 Text:Shop Done:false}] map[status:open]", w.Body.String())
+	})
+
+	t.Run("missing field stays empty", func(t *testing.T) {
+		w := post(url.Values{"title": {"Empty"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Empty: [] map[]", w.Body.String())
+	})
+
+	t.Run("invalid JSON is a decode error for the field", func(t *testing.T) {
+		var reported error
+		registry.SetOnError(func(ctx context.Context, err *components.ComponentError) {
+			reported = err.Err
+		})
+		defer registry.SetOnError(nil)
+
+		w := post(url.Values{"items": {`[{"ID":`}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var decodeErr *components.ErrDecode
+		require.ErrorAs(t, reported, &decodeErr)
+		require.Len(t, decodeErr.Fields, 1)
+		assert.Equal(t, "items", decodeErr.Fields[0].Field)
+		assert.Equal(t, `[{"ID":`, decodeErr.Fields[0].Value)
+	})
+
+	t.Run("SimulateRequest decodes JSON fields", func(t *testing.T) {
+		component := &TestJSONFieldComponent{}
+		err := components.SimulateRequest(context.Background(), component, http.MethodPost, url.Values{"items": {`[{"ID":7}]`}}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []TestJSONTodoItem{{ID: 7}}, component.Items)
+	})
+}
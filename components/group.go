@@ -0,0 +1,109 @@
+package components
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/a-h/templ"
+)
+
+// Group is a named area of a registry, such as "admin" or "public". Components
+// registered in a group are named "<group>/<name>" and are served by the registry's
+// handlers like any other component, but requests to them first pass through the
+// group's middleware. Groups are created with Registry.Group and can be nested.
+//
+// Example:
+//
+//	admin := registry.Group("admin")
+//	admin.Use(requireAdmin)
+//	components.RegisterIn[*users.UsersComponent](admin, "users") // served at /component/admin/users
+type Group struct {
+	registry *Registry
+	parent   *Group
+	prefix   string
+
+	mu         sync.RWMutex
+	middleware []func(http.Handler) http.Handler
+}
+
+// Group returns a group whose components are named "<name>/...". The name follows
+// the same rules as component names.
+func (r *Registry) Group(name string) *Group {
+	if !isValidComponentName(name) {
+		panic((&ErrInvalidComponentName{ComponentName: name, Reason: "invalid group name"}).Error())
+	}
+	return &Group{registry: r, prefix: name}
+}
+
+// Group returns a nested group whose components are named "<group>/<name>/...".
+// Requests pass through this group's middleware before the nested group's.
+func (g *Group) Group(name string) *Group {
+	nested := g.registry.Group(name)
+	nested.parent = g
+	nested.prefix = g.prefix + "/" + name
+	return nested
+}
+
+// Use appends middleware applied to requests for every component in the group,
+// including components registered before Use is called. Middleware is compatible
+// with chi and most net/http routers.
+func (g *Group) Use(middleware ...func(http.Handler) http.Handler) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.middleware = append(g.middleware, middleware...)
+}
+
+// Name returns the full registry name of the component registered in the group as
+// name, e.g. "admin/users", for use with HandlerFor or RenderComponent.
+func (g *Group) Name(name string) string {
+	return g.prefix + "/" + name
+}
+
+// RegisterIn registers a component in a group under the name "<group>/<name>".
+// It behaves like Register otherwise.
+func RegisterIn[T templ.Component](g *Group, name string, opts ...RegisterOption) {
+	Register[T](g.registry, g.Name(name), append(opts, inGroup(g))...)
+}
+
+// New starts registering a component in the group from a factory function; see
+// Registry.New.
+func (g *Group) New(name string) *ComponentBuilder {
+	return &ComponentBuilder{registry: g.registry, name: g.Name(name), group: g}
+}
+
+// inGroup records the group a component was registered in.
+func inGroup(g *Group) RegisterOption {
+	return func(e *componentEntry) {
+		e.group = g
+	}
+}
+
+// wrap applies the middleware of g and its parent groups to next, outermost first.
+func (g *Group) wrap(next http.Handler) http.Handler {
+	for group := g; group != nil; group = group.parent {
+		group.mu.RLock()
+		middleware := group.middleware
+		group.mu.RUnlock()
+		for i := len(middleware) - 1; i >= 0; i-- {
+			next = middleware[i](next)
+		}
+	}
+	return next
+}
+
+// groupMiddleware wraps a component handler in the middleware of the group the
+// component was registered in. The group is looked up per request, so handlers can
+// be created before the component is registered.
+func (r *Registry) groupMiddleware(componentName string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		r.mu.RLock()
+		group := r.components[componentName].group
+		r.mu.RUnlock()
+
+		if group == nil {
+			next(w, req)
+			return
+		}
+		group.wrap(next).ServeHTTP(w, req)
+	}
+}
@@ -0,0 +1,97 @@
+package components_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// requireUser blocks requests without an X-User header
+func requireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-User") == "" {
+			http.Error(w, "login required", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// tag records the order middleware ran in the X-Middleware response header
+func tag(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Add("X-Middleware", name)
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+func TestRegistryGroup(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSimpleCounter](registry, "counter")
+
+	admin := registry.Group("admin")
+	admin.Use(requireUser)
+	components.RegisterIn[*TestSimpleCounter](admin, "users")
+
+	get := func(target, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if user != "" {
+			req.Header.Set("X-User", user)
+		}
+		w := httptest.NewRecorder()
+		registry.Handler(w, req)
+		return w
+	}
+
+	t.Run("components are named with the group prefix", func(t *testing.T) {
+		assert.Equal(t, "admin/users", admin.Name("users"))
+		assert.Equal(t, []string{"admin/users", "counter"}, registry.ListComponents())
+	})
+
+	t.Run("group middleware blocks anonymous requests", func(t *testing.T) {
+		w := get("/component/admin/users", "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "login required")
+	})
+
+	t.Run("group middleware lets authorized requests through", func(t *testing.T) {
+		w := get("/component/admin/users?count=2", "alice")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>2</div>", w.Body.String())
+	})
+
+	t.Run("components outside the group are unaffected", func(t *testing.T) {
+		w := get("/component/counter?count=1", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>1</div>", w.Body.String())
+	})
+
+	t.Run("HandlerFor applies group middleware", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		registry.HandlerFor(admin.Name("users"))(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestNestedGroups(t *testing.T) {
+	registry := components.NewRegistry()
+	admin := registry.Group("admin")
+	reports := admin.Group("reports")
+	reports.Use(tag("reports"))
+	admin.Use(tag("admin"))
+	reports.New("daily").From(func() templ.Component { return &TestSimpleCounter{} })
+
+	req := httptest.NewRequest(http.MethodGet, "/component/admin/reports/daily", nil)
+	w := httptest.NewRecorder()
+	registry.Handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "admin/reports/daily", reports.Name("daily"))
+	assert.Equal(t, []string{"admin", "reports"}, w.Header().Values("X-Middleware"))
+}
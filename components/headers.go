@@ -1,7 +1,10 @@
 package components
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 // applyHxHeaders applies HTMX request headers to the instance if it implements
@@ -25,12 +28,34 @@ func applyHxHeaders(instance interface{}, req *http.Request) {
 	if v, ok := instance.(HxTrigger); ok {
 		v.SetHxTrigger(req.Header.Get("HX-Trigger"))
 	}
+	if v, ok := instance.(HxTriggerJSON); ok {
+		var detail map[string]any
+		if err := json.Unmarshal([]byte(req.Header.Get("HX-Trigger")), &detail); err == nil && detail != nil {
+			v.SetHxTriggerJSON(detail)
+		}
+	}
 	if v, ok := instance.(HxTriggerName); ok {
 		v.SetHxTriggerName(req.Header.Get("HX-Trigger-Name"))
 	}
+	if v, ok := instance.(HxTriggerInfo); ok {
+		v.SetHxTriggerInfo(TriggerInfo{
+			ID:   req.Header.Get("HX-Trigger"),
+			Name: req.Header.Get("HX-Trigger-Name"),
+		})
+	}
 	if v, ok := instance.(HttpMethod); ok {
 		v.SetHttpMethod(req.Method)
 	}
+	if v, ok := instance.(RequestAware); ok {
+		v.SetRequest(req)
+	}
+	if v, ok := instance.(RawParamsAware); ok {
+		body := req.PostForm
+		if body == nil {
+			body = url.Values{}
+		}
+		v.SetRawParams(req.URL.Query(), body)
+	}
 }
 
 // applyHxResponseHeaders applies HTMX response headers from the instance if it implements
@@ -41,7 +66,11 @@ func applyHxResponseHeaders(w http.ResponseWriter, instance interface{}) {
 			w.Header().Set("HX-Location", location)
 		}
 	}
-	if v, ok := instance.(HxPushUrlResponse); ok {
+	if v, ok := instance.(HxPushUrlOptResponse); ok {
+		if pushUrl, set := v.GetHxPushUrlOpt(); set {
+			w.Header().Set("HX-Push-Url", pushUrl)
+		}
+	} else if v, ok := instance.(HxPushUrlResponse); ok {
 		if pushUrl := v.GetHxPushUrl(); pushUrl != "" {
 			w.Header().Set("HX-Push-Url", pushUrl)
 		}
@@ -56,7 +85,11 @@ func applyHxResponseHeaders(w http.ResponseWriter, instance interface{}) {
 			w.Header().Set("HX-Refresh", "true")
 		}
 	}
-	if v, ok := instance.(HxReplaceUrlResponse); ok {
+	if v, ok := instance.(HxReplaceUrlOptResponse); ok {
+		if replaceUrl, set := v.GetHxReplaceUrlOpt(); set {
+			w.Header().Set("HX-Replace-Url", replaceUrl)
+		}
+	} else if v, ok := instance.(HxReplaceUrlResponse); ok {
 		if replaceUrl := v.GetHxReplaceUrl(); replaceUrl != "" {
 			w.Header().Set("HX-Replace-Url", replaceUrl)
 		}
@@ -81,14 +114,59 @@ func applyHxResponseHeaders(w http.ResponseWriter, instance interface{}) {
 			w.Header().Set("HX-Trigger", trigger)
 		}
 	}
+	if v, ok := instance.(HxStructuredTriggerResponse); ok {
+		if trigger := v.GetHxStructuredTrigger(); trigger != nil && trigger.Name != "" {
+			if value, err := trigger.HeaderValue(); err == nil {
+				w.Header().Set("HX-Trigger", value)
+			}
+		}
+	}
+	if v, ok := instance.(HxTriggerEventsResponse); ok {
+		setTriggerEvents(w.Header(), "HX-Trigger", v.GetHxTriggerEvents())
+	}
 	if v, ok := instance.(HxTriggerAfterSettleResponse); ok {
 		if trigger := v.GetHxTriggerAfterSettle(); trigger != "" {
 			w.Header().Set("HX-Trigger-After-Settle", trigger)
 		}
 	}
+	if v, ok := instance.(HxTriggerAfterSettleEventsResponse); ok {
+		setTriggerEvents(w.Header(), "HX-Trigger-After-Settle", v.GetHxTriggerAfterSettleEvents())
+	}
 	if v, ok := instance.(HxTriggerAfterSwapResponse); ok {
 		if trigger := v.GetHxTriggerAfterSwap(); trigger != "" {
 			w.Header().Set("HX-Trigger-After-Swap", trigger)
 		}
 	}
+	if v, ok := instance.(HxTriggerAfterSwapEventsResponse); ok {
+		setTriggerEvents(w.Header(), "HX-Trigger-After-Swap", v.GetHxTriggerAfterSwapEvents())
+	}
+	if v, ok := instance.(CookieResponse); ok {
+		for _, cookie := range v.ResponseCookies() {
+			http.SetCookie(w, cookie)
+		}
+	}
+	if _, ok := instance.(HxRequest); ok {
+		addVary(w.Header(), "HX-Request")
+	}
+	if v, ok := instance.(VaryProvider); ok {
+		addVary(w.Header(), v.Vary()...)
+	}
+}
+
+// addVary appends names to the Vary header, skipping any already listed.
+func addVary(h http.Header, names ...string) {
+	listed := make(map[string]bool)
+	for _, value := range h.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			listed[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+		}
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || listed[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		listed[http.CanonicalHeaderKey(name)] = true
+		h.Add("Vary", name)
+	}
 }
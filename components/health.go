@@ -0,0 +1,47 @@
+package components
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// HealthStatus is the JSON body served by HealthHandler.
+type HealthStatus struct {
+	Status     string `json:"status"`
+	Components int    `json:"components"`
+	DebugMode  bool   `json:"debugMode"`
+}
+
+// HealthHandler serves a lightweight liveness check that does not render any
+// component or page: a 200 with the body "OK", or, when the request asks for JSON
+// (?format=json or an Accept header containing application/json), a HealthStatus
+// with the number of registered components and whether debug mode is on.
+//
+//	router.Get("/healthz", registry.HealthHandler)
+func (r *Registry) HealthHandler(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	if req.URL.Query().Get("format") != "json" && !strings.Contains(req.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if req.Method != http.MethodHead {
+			_, _ = w.Write([]byte("OK"))
+		}
+		return
+	}
+
+	r.mu.RLock()
+	status := HealthStatus{
+		Status:     "ok",
+		Components: len(r.components),
+		DebugMode:  r.debugMode,
+	}
+	r.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		r.logger().Error("failed to encode health status",
+			"error", err)
+	}
+}
@@ -0,0 +1,50 @@
+package components_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSimpleCounter](registry, "counter")
+	components.Register[*TestQuantityComponent](registry, "quantity")
+
+	t.Run("plain body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		registry.HealthHandler(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "OK", w.Body.String())
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	})
+
+	t.Run("JSON reports registrations and debug mode", func(t *testing.T) {
+		registry.EnableDebugMode()
+		defer registry.DisableDebugMode()
+
+		for _, req := range []*http.Request{
+			httptest.NewRequest(http.MethodGet, "/healthz?format=json", nil),
+			func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+				req.Header.Set("Accept", "application/json")
+				return req
+			}(),
+		} {
+			w := httptest.NewRecorder()
+			registry.HealthHandler(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			var status components.HealthStatus
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+			assert.Equal(t, components.HealthStatus{Status: "ok", Components: 2, DebugMode: true}, status)
+		}
+	})
+}
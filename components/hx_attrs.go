@@ -0,0 +1,35 @@
+package components
+
+import "github.com/a-h/templ"
+
+// HxDefaults is implemented by components that declare the hx-target and hx-swap
+// their own requests should use, so templates don't repeat them on every element.
+// Spread them into an element with HxAttrs:
+//
+//	func (c *CounterComponent) HxTarget() string { return "closest .counter-component" }
+//	func (c *CounterComponent) HxSwap() string   { return "outerHTML" }
+//
+//	<button hx-post="/component/counter" { components.HxAttrs(data)... }>+</button>
+type HxDefaults interface {
+	HxTarget() string
+	HxSwap() string
+}
+
+// HxAttrs returns the hx-target and hx-swap attributes declared by a component
+// implementing HxDefaults, for spreading into a templ element. Empty values are
+// left out, and a component that does not implement HxDefaults gets no attributes,
+// so HTMX's own defaults apply.
+func HxAttrs(c any) templ.Attributes {
+	attrs := templ.Attributes{}
+	defaults, ok := c.(HxDefaults)
+	if !ok {
+		return attrs
+	}
+	if target := defaults.HxTarget(); target != "" {
+		attrs["hx-target"] = target
+	}
+	if swap := defaults.HxSwap(); swap != "" {
+		attrs["hx-swap"] = swap
+	}
+	return attrs
+}
@@ -0,0 +1,42 @@
+package components_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHxDefaultsComponent declares where its requests swap
+type TestHxDefaultsComponent struct {
+	Swap string
+}
+
+func (c *TestHxDefaultsComponent) HxTarget() string { return "closest .counter-component" }
+func (c *TestHxDefaultsComponent) HxSwap() string   { return c.Swap }
+
+func (c *TestHxDefaultsComponent) Render(ctx context.Context, w io.Writer) error {
+	return nil
+}
+
+func TestHxAttrs(t *testing.T) {
+	t.Run("declared target and swap", func(t *testing.T) {
+		attrs := components.HxAttrs(&TestHxDefaultsComponent{Swap: "outerHTML"})
+		assert.Equal(t, templ.Attributes{
+			"hx-target": "closest .counter-component",
+			"hx-swap":   "outerHTML",
+		}, attrs)
+	})
+
+	t.Run("empty values are left out", func(t *testing.T) {
+		attrs := components.HxAttrs(&TestHxDefaultsComponent{})
+		assert.Equal(t, templ.Attributes{"hx-target": "closest .counter-component"}, attrs)
+	})
+
+	t.Run("no attributes without HxDefaults", func(t *testing.T) {
+		assert.Empty(t, components.HxAttrs(&TestPartialRenderComponent{}))
+	})
+}
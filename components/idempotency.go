@@ -0,0 +1,176 @@
+package components
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader is the request header carrying an idempotency key.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeys bounds the number of responses stored per component.
+const maxIdempotencyKeys = 1000
+
+// idempotencyStore remembers the responses of mutating event requests by
+// idempotency key so duplicate submissions are replayed instead of re-run.
+type idempotencyStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*idempotentResponse
+}
+
+// idempotentResponse is the response for a key. done is closed once the first
+// request has finished; ok reports whether it succeeded and status, body and
+// headers are set.
+type idempotentResponse struct {
+	done    chan struct{}
+	ok      bool
+	status  int
+	body    []byte
+	headers http.Header
+	expires time.Time
+}
+
+// newIdempotencyStore creates a store whose responses are replayed for ttl.
+func newIdempotencyStore(ttl time.Duration) *idempotencyStore {
+	if ttl <= 0 {
+		panic("idempotency ttl must be greater than zero")
+	}
+	return &idempotencyStore{
+		ttl:     ttl,
+		entries: make(map[string]*idempotentResponse),
+	}
+}
+
+// idempotencyKey returns the request's idempotency key from the Idempotency-Key
+// header, or from the form field if the header is absent and field is set, scoped
+// to the client (by clientKey, or IP address if nil) so clients cannot replay each
+// other's responses. It returns "" if the request has no key.
+func idempotencyKey(req *http.Request, field string, clientKey func(*http.Request) string) string {
+	key := req.Header.Get(IdempotencyKeyHeader)
+	if key == "" && field != "" {
+		key = req.PostForm.Get(field)
+	}
+	if key == "" {
+		return ""
+	}
+	if clientKey == nil {
+		clientKey = clientIP
+	}
+	return clientKey(req) + "|" + key
+}
+
+// claim registers key for the calling request. If another request already
+// claimed it, claim returns that request's response for the caller to wait on.
+func (s *idempotencyStore) claim(key string, now time.Time) (*idempotentResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.entries[key]; ok {
+		if !existing.isExpired(now) {
+			return existing, false
+		}
+		delete(s.entries, key)
+	}
+
+	s.evict(now)
+	resp := &idempotentResponse{done: make(chan struct{})}
+	s.entries[key] = resp
+	return resp, true
+}
+
+// complete stores the successful response for a claimed key and releases waiters.
+func (s *idempotencyStore) complete(resp *idempotentResponse, status int, body []byte, headers http.Header, now time.Time) {
+	s.mu.Lock()
+	resp.ok = true
+	resp.status = status
+	resp.body = body
+	resp.headers = headers
+	resp.expires = now.Add(s.ttl)
+	s.mu.Unlock()
+	close(resp.done)
+}
+
+// release forgets a claimed key whose request failed, so it can be retried.
+func (s *idempotencyStore) release(key string, resp *idempotentResponse) {
+	s.mu.Lock()
+	if s.entries[key] == resp {
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+	close(resp.done)
+}
+
+// evict removes expired responses and, if the store is still full, the response
+// closest to expiry. Callers must hold s.mu.
+func (s *idempotencyStore) evict(now time.Time) {
+	for key, resp := range s.entries {
+		if resp.isExpired(now) {
+			delete(s.entries, key)
+		}
+	}
+	for len(s.entries) >= maxIdempotencyKeys {
+		var oldestKey string
+		var oldest *idempotentResponse
+		for key, resp := range s.entries {
+			if resp.ok && (oldest == nil || resp.expires.Before(oldest.expires)) {
+				oldestKey, oldest = key, resp
+			}
+		}
+		if oldest == nil {
+			// Only in-flight requests remain
+			return
+		}
+		delete(s.entries, oldestKey)
+	}
+}
+
+// isExpired reports whether a completed response is past its TTL.
+func (r *idempotentResponse) isExpired(now time.Time) bool {
+	return r.ok && now.After(r.expires)
+}
+
+// debounceKey identifies a repeated event for WithDebounce: the same client (by
+// keyFunc, or sessionKey if nil) sending the same event with the same form
+// values. Fields in ignore, such as the trace parameter, are left out of the
+// values compared.
+func debounceKey(req *http.Request, eventName string, values url.Values, keyFunc func(*http.Request) string, ignore ...string) string {
+	if keyFunc == nil {
+		keyFunc = sessionKey
+	}
+	compared := make(url.Values, len(values))
+	for key, vals := range values {
+		compared[key] = vals
+	}
+	for _, key := range ignore {
+		delete(compared, key)
+	}
+	sum := sha256.Sum256([]byte(compared.Encode()))
+	return keyFunc(req) + "|" + eventName + "|" + hex.EncodeToString(sum[:])
+}
+
+// sessionKey identifies the client's session by its IP address and a hash of the
+// cookies it sent, so users sharing an address (e.g. behind a NAT) but holding
+// different session cookies are told apart. Clients without cookies are identified
+// by IP address alone.
+func sessionKey(req *http.Request) string {
+	cookies := req.Header.Values("Cookie")
+	if len(cookies) == 0 {
+		return clientIP(req)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(cookies, "; ")))
+	return clientIP(req) + "|" + hex.EncodeToString(sum[:])
+}
+
+// replayableHeaders returns the response headers to store for replay. Set-Cookie
+// is dropped, so a replayed response never hands one client's cookies to another.
+func replayableHeaders(h http.Header) http.Header {
+	stored := h.Clone()
+	stored.Del("Set-Cookie")
+	return stored
+}
@@ -0,0 +1,284 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// likes is the server-side state mutated by TestLikeComponent
+var likes atomic.Int32
+
+// TestLikeComponent increments a shared counter on each "like" event
+type TestLikeComponent struct {
+	Count int32 `json:"-"`
+}
+
+func (c *TestLikeComponent) OnLike(ctx context.Context) error {
+	time.Sleep(5 * time.Millisecond)
+	c.Count = likes.Add(1)
+	return nil
+}
+
+func (c *TestLikeComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>Likes: %d</div>", c.Count)
+	return nil
+}
+
+func TestWithIdempotency(t *testing.T) {
+	likes.Store(0)
+	registry := components.NewRegistry()
+	components.Register[*TestLikeComponent](registry, "like",
+		components.WithIdempotency(time.Minute),
+		components.WithIdempotencyField("idempotency_key"),
+	)
+
+	post := func(body, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/like", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		registry.HandlerFor("like")(w, req)
+		return w
+	}
+
+	t.Run("duplicate key replays the first response", func(t *testing.T) {
+		first := post("hxc-event=like", "abc")
+		second := post("hxc-event=like", "abc")
+
+		assert.Equal(t, "<div>Likes: 1</div>", first.Body.String())
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, int32(1), likes.Load())
+	})
+
+	t.Run("different keys run the event", func(t *testing.T) {
+		w := post("hxc-event=like", "def")
+		assert.Equal(t, "<div>Likes: 2</div>", w.Body.String())
+	})
+
+	t.Run("requests without a key are not deduplicated", func(t *testing.T) {
+		post("hxc-event=like", "")
+		post("hxc-event=like", "")
+		assert.Equal(t, int32(4), likes.Load())
+	})
+
+	t.Run("key can be sent as a form field", func(t *testing.T) {
+		post("hxc-event=like&idempotency_key=xyz", "")
+		w := post("hxc-event=like&idempotency_key=xyz", "")
+		assert.Equal(t, "<div>Likes: 5</div>", w.Body.String())
+		assert.Equal(t, int32(5), likes.Load())
+	})
+
+	t.Run("concurrent duplicates run the event once", func(t *testing.T) {
+		var wg sync.WaitGroup
+		bodies := make([]string, 5)
+		for i := range bodies {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				bodies[i] = post("hxc-event=like", "concurrent").Body.String()
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(6), likes.Load())
+		for _, body := range bodies {
+			assert.Equal(t, "<div>Likes: 6</div>", body)
+		}
+	})
+
+	t.Run("rejects a non-positive ttl", func(t *testing.T) {
+		assert.Panics(t, func() { components.WithIdempotency(0) })
+	})
+}
+
+// orders counts how many times TestOrderComponent.OnPlace ran
+var orders atomic.Int32
+
+// TestOrderComponent answers its "place" event with a 201 and response headers
+type TestOrderComponent struct {
+	ID int32 `json:"-"`
+}
+
+func (c *TestOrderComponent) OnPlace(ctx context.Context) (*components.EventResult, error) {
+	c.ID = orders.Add(1)
+	return &components.EventResult{StatusCode: http.StatusCreated, Trigger: "orderPlaced"}, nil
+}
+
+func (c *TestOrderComponent) ResponseCookies() []*http.Cookie {
+	return []*http.Cookie{{Name: "last_order", Value: fmt.Sprint(c.ID)}}
+}
+
+func (c *TestOrderComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>Order %d</div>", c.ID)
+	return nil
+}
+
+func TestWithIdempotencyReplaysStatusAndHeaders(t *testing.T) {
+	orders.Store(0)
+	registry := components.NewRegistry()
+	components.Register[*TestOrderComponent](registry, "order",
+		components.WithIdempotency(time.Minute))
+	// The key options apply whether they are passed before or after WithIdempotency
+	components.Register[*TestOrderComponent](registry, "session-order",
+		components.WithIdempotencyClientKey(func(req *http.Request) string { return req.Header.Get("X-Session") }),
+		components.WithIdempotency(time.Minute))
+
+	post := func(name, remoteAddr, session string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/"+name, strings.NewReader("hxc-event=place"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Idempotency-Key", "order-1")
+		req.Header.Set("X-Session", session)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		registry.HandlerFor(name)(w, req)
+		return w
+	}
+
+	t.Run("replay keeps the status code and headers but not cookies", func(t *testing.T) {
+		first := post("order", "10.0.0.1:1234", "")
+		second := post("order", "10.0.0.1:1234", "")
+
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, "orderPlaced", second.Header().Get("HX-Trigger"))
+		assert.Equal(t, "last_order=1", first.Header().Get("Set-Cookie"))
+		assert.Empty(t, second.Header().Get("Set-Cookie"))
+		assert.Equal(t, first.Header().Get("Content-Type"), second.Header().Get("Content-Type"))
+		assert.Equal(t, "<div>Order 1</div>", second.Body.String())
+		assert.Equal(t, int32(1), orders.Load())
+	})
+
+	t.Run("keys are scoped to the client", func(t *testing.T) {
+		w := post("order", "10.0.0.2:1234", "")
+		assert.Equal(t, "<div>Order 2</div>", w.Body.String())
+	})
+
+	t.Run("client key function identifies clients", func(t *testing.T) {
+		assert.Equal(t, "<div>Order 3</div>", post("session-order", "10.0.0.1:1234", "alice").Body.String())
+		assert.Equal(t, "<div>Order 3</div>", post("session-order", "10.0.0.2:1234", "alice").Body.String())
+		assert.Equal(t, "<div>Order 4</div>", post("session-order", "10.0.0.1:1234", "bob").Body.String())
+	})
+}
+
+// increments counts how many times TestDebouncedCounter.OnIncrement ran
+var increments atomic.Int32
+
+// TestDebouncedCounter increments a shared counter on each "increment" event
+type TestDebouncedCounter struct {
+	Step  int   `form:"step"`
+	Count int32 `json:"-"`
+}
+
+func (c *TestDebouncedCounter) OnIncrement(ctx context.Context) error {
+	c.Count = increments.Add(1)
+	return nil
+}
+
+func (c *TestDebouncedCounter) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>Count: %d</div>", c.Count)
+	return nil
+}
+
+func TestWithDebounce(t *testing.T) {
+	increments.Store(0)
+	registry := components.NewRegistry()
+	components.Register[*TestDebouncedCounter](registry, "counter",
+		components.WithDebounce(200*time.Millisecond))
+
+	fire := func(remoteAddr, event string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/counter", strings.NewReader("hxc-event="+event))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+		return w
+	}
+
+	t.Run("repeated events within the window replay the response", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			w := fire("10.0.0.1:1234", "increment")
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "<div>Count: 1</div>", w.Body.String())
+		}
+		assert.Equal(t, int32(1), increments.Load())
+	})
+
+	t.Run("other clients are not debounced", func(t *testing.T) {
+		w := fire("10.0.0.2:1234", "increment")
+		assert.Equal(t, "<div>Count: 2</div>", w.Body.String())
+	})
+
+	t.Run("events run again after the window", func(t *testing.T) {
+		time.Sleep(250 * time.Millisecond)
+		w := fire("10.0.0.1:1234", "increment")
+		assert.Equal(t, "<div>Count: 3</div>", w.Body.String())
+	})
+
+	t.Run("rejects a non-positive window", func(t *testing.T) {
+		assert.Panics(t, func() { components.WithDebounce(0) })
+	})
+}
+
+func TestWithDebounceComparesValuesAndClients(t *testing.T) {
+	increments.Store(0)
+	registry := components.NewRegistry()
+	components.Register[*TestDebouncedCounter](registry, "counter",
+		components.WithDebounce(time.Minute))
+	components.Register[*TestDebouncedCounter](registry, "session-counter",
+		components.WithDebounce(time.Minute),
+		components.WithDebounceKey(func(req *http.Request) string { return req.Header.Get("X-Session") }))
+
+	fire := func(name, session, body string) {
+		req := httptest.NewRequest(http.MethodPost, "/component/"+name, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Session", session)
+		w := httptest.NewRecorder()
+		registry.HandlerFor(name)(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	t.Run("different form values are not debounced", func(t *testing.T) {
+		fire("counter", "", "hxc-event=increment&step=1")
+		fire("counter", "", "hxc-event=increment&step=2")
+		fire("counter", "", "step=1&hxc-event=increment&"+components.TraceParam+"=abc")
+		assert.Equal(t, int32(2), increments.Load())
+	})
+
+	t.Run("clients sharing an address are told apart by their cookies", func(t *testing.T) {
+		increments.Store(0)
+		send := func(cookie string) {
+			req := httptest.NewRequest(http.MethodPost, "/component/counter", strings.NewReader("hxc-event=increment&step=3"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("Cookie", cookie)
+			w := httptest.NewRecorder()
+			registry.HandlerFor("counter")(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+		}
+		send("session=alice")
+		send("session=alice")
+		send("session=bob")
+		assert.Equal(t, int32(2), increments.Load())
+	})
+
+	t.Run("key function identifies clients", func(t *testing.T) {
+		increments.Store(0)
+		fire("session-counter", "alice", "hxc-event=increment")
+		fire("session-counter", "alice", "hxc-event=increment")
+		fire("session-counter", "bob", "hxc-event=increment")
+		assert.Equal(t, int32(2), increments.Load())
+	})
+}
@@ -1,6 +1,9 @@
 package components
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 // Initializer is an optional interface that components can implement to perform
 // initialization logic. This is particularly useful when using constructor functions
@@ -81,3 +84,19 @@ import "context"
 type Initializer interface {
 	Init(ctx context.Context) error
 }
+
+// ErrRedirect can be returned (or wrapped) by Init to stop the request and send the
+// component's response headers instead of an error page, e.g. to redirect to a
+// login page when the session is missing. Set the target via HxRedirectResponse
+// (or HxLocationResponse) before returning it:
+//
+//	func (c *Dashboard) Init(ctx context.Context) error {
+//	    if c.SessionID == "" {
+//	        c.RedirectTo = "/login"
+//	        return components.ErrRedirect
+//	    }
+//	    return nil
+//	}
+//
+// The handler responds with 200 and an empty body so HTMX follows the redirect.
+var ErrRedirect = errors.New("redirect requested")
@@ -0,0 +1,72 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGuardedComponent redirects to the login page when no session is supplied
+type TestGuardedComponent struct {
+	Session    string `form:"session"`
+	Broken     bool   `form:"broken"`
+	RedirectTo string `json:"-"`
+}
+
+func (c *TestGuardedComponent) Init(ctx context.Context) error {
+	if c.Broken {
+		return fmt.Errorf("database unavailable")
+	}
+	if c.Session == "" {
+		c.RedirectTo = "/login"
+		return fmt.Errorf("no session: %w", components.ErrRedirect)
+	}
+	return nil
+}
+
+func (c *TestGuardedComponent) GetHxRedirect() string {
+	return c.RedirectTo
+}
+
+func (c *TestGuardedComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>Welcome %s</div>", c.Session)
+	return nil
+}
+
+func TestInitErrRedirect(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestGuardedComponent](registry, "guarded")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/component/guarded"+query, nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("guarded")(w, req)
+		return w
+	}
+
+	t.Run("redirect is honored without an error page", func(t *testing.T) {
+		w := get("")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "/login", w.Header().Get("HX-Redirect"))
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("successful init renders normally", func(t *testing.T) {
+		w := get("?session=abc")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("HX-Redirect"))
+		assert.Equal(t, "<div>Welcome abc</div>", w.Body.String())
+	})
+
+	t.Run("other init errors are still a 500", func(t *testing.T) {
+		w := get("?broken=true")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Initialization Error")
+	})
+}
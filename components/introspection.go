@@ -0,0 +1,153 @@
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// knownInterfaces lists the optional interfaces reported in ComponentInfo.Interfaces
+var knownInterfaces = []struct {
+	name string
+	typ  reflect.Type
+}{
+	{"AfterDecoder", reflect.TypeOf((*AfterDecoder)(nil)).Elem()},
+	{"Initializer", reflect.TypeOf((*Initializer)(nil)).Elem()},
+	{"Validator", reflect.TypeOf((*Validator)(nil)).Elem()},
+	{"ValidationErrorRenderer", reflect.TypeOf((*ValidationErrorRenderer)(nil)).Elem()},
+	{"Processor", reflect.TypeOf((*Processor)(nil)).Elem()},
+	{"Authorizer", reflect.TypeOf((*Authorizer)(nil)).Elem()},
+	{"BeforeEventHandler", reflect.TypeOf((*BeforeEventHandler)(nil)).Elem()},
+	{"AfterEventHandler", reflect.TypeOf((*AfterEventHandler)(nil)).Elem()},
+	{"Transactional", reflect.TypeOf((*Transactional)(nil)).Elem()},
+	{"FormDecoder", reflect.TypeOf((*FormDecoder)(nil)).Elem()},
+	{"RequestAware", reflect.TypeOf((*RequestAware)(nil)).Elem()},
+	{"RawParamsAware", reflect.TypeOf((*RawParamsAware)(nil)).Elem()},
+	{"LocaleAware", reflect.TypeOf((*LocaleAware)(nil)).Elem()},
+	{"CookieResponse", reflect.TypeOf((*CookieResponse)(nil)).Elem()},
+	{"VaryProvider", reflect.TypeOf((*VaryProvider)(nil)).Elem()},
+	{"StreamComponent", reflect.TypeOf((*StreamComponent)(nil)).Elem()},
+	{"ChunkedRenderer", reflect.TypeOf((*ChunkedRenderer)(nil)).Elem()},
+	{"Flushable", reflect.TypeOf((*Flushable)(nil)).Elem()},
+	{"RecoverComponent", reflect.TypeOf((*RecoverComponent)(nil)).Elem()},
+	{"AfterRenderHandler", reflect.TypeOf((*AfterRenderHandler)(nil)).Elem()},
+	{"OutOfBandComponent", reflect.TypeOf((*OutOfBandComponent)(nil)).Elem()},
+	{"ContentTyper", reflect.TypeOf((*ContentTyper)(nil)).Elem()},
+	{"RawResponder", reflect.TypeOf((*RawResponder)(nil)).Elem()},
+	{"StatefulComponent", reflect.TypeOf((*StatefulComponent)(nil)).Elem()},
+	{"SignedStateComponent", reflect.TypeOf((*SignedStateComponent)(nil)).Elem()},
+	{"Flasher", reflect.TypeOf((*Flasher)(nil)).Elem()},
+}
+
+// ComponentCapabilities describes a registered component and the optional
+// interfaces it implements. It is the JSON representation served by InfoHandler.
+type ComponentCapabilities struct {
+	Name        string   `json:"name"`
+	StructType  string   `json:"structType"`
+	Processor   bool     `json:"processor"`
+	Validator   bool     `json:"validator"`
+	Initializer bool     `json:"initializer"`
+	Events      []string `json:"events"`
+	Interfaces  []string `json:"interfaces"`
+	FormFields  []string `json:"formFields"`
+	Enabled     bool     `json:"enabled"`
+}
+
+// InfoHandler serves a JSON array describing every registered component, in
+// alphabetical order. It is intended for tooling and admin dashboards:
+//
+//	router.Get("/_components", registry.InfoHandler)
+func (r *Registry) InfoHandler(w http.ResponseWriter, req *http.Request) {
+	names := r.ListComponents()
+	result := make([]ComponentCapabilities, 0, len(names))
+	for _, name := range names {
+		info, err := r.GetComponentInfo(name)
+		if err != nil {
+			// Unregistered between listing and lookup
+			continue
+		}
+
+		result = append(result, ComponentCapabilities{
+			Name:        info.Name,
+			StructType:  info.StructType,
+			Processor:   slices.Contains(info.Interfaces, "Processor"),
+			Validator:   slices.Contains(info.Interfaces, "Validator"),
+			Initializer: slices.Contains(info.Interfaces, "Initializer"),
+			Events:      info.Events,
+			Interfaces:  info.Interfaces,
+			FormFields:  info.FormFields,
+			Enabled:     info.Enabled,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		r.logger().Error("failed to encode component info",
+			"error", err)
+	}
+}
+
+// discoverEvents returns the event names handled by a component type, derived from
+// its On{Event}(ctx context.Context) error,
+// On{Event}(ctx context.Context) (templ.Component, error) and
+// On{Event}(ctx context.Context) (*EventResult, error) methods
+// (e.g. OnAddItem -> "addItem"), where "On" is the given method prefix.
+func discoverEvents(ptrType reflect.Type, prefix string) []string {
+	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	errType := reflect.TypeOf((*error)(nil)).Elem()
+	componentType := reflect.TypeOf((*templ.Component)(nil)).Elem()
+
+	events := []string{}
+	for i := 0; i < ptrType.NumMethod(); i++ {
+		method := ptrType.Method(i)
+		name := strings.TrimPrefix(method.Name, prefix)
+		if name == method.Name || name == "" {
+			continue
+		}
+		// Method types include the receiver as the first parameter
+		mt := method.Type
+		if mt.NumIn() != 2 || !mt.In(1).Implements(ctxType) ||
+			mt.NumOut() < 1 || mt.NumOut() > 2 || mt.Out(mt.NumOut()-1) != errType {
+			continue
+		}
+		if mt.NumOut() == 2 && mt.Out(0) != componentType && mt.Out(0) != eventResultType {
+			continue
+		}
+		events = append(events, strings.ToLower(name[:1])+name[1:])
+	}
+	return events
+}
+
+// discoverInterfaces returns the names of the optional interfaces implemented by a component type.
+func discoverInterfaces(ptrType reflect.Type) []string {
+	interfaces := []string{}
+	for _, known := range knownInterfaces {
+		if ptrType.Implements(known.typ) {
+			interfaces = append(interfaces, known.name)
+		}
+	}
+	return interfaces
+}
+
+// discoverFormFields returns the form field names of a component struct, taken from
+// `form` tags. Fields tagged `form:"-"` are skipped.
+func discoverFormFields(structType reflect.Type) []string {
+	fields := []string{}
+	for i := 0; i < structType.NumField(); i++ {
+		tag, ok := structType.Field(i).Tag.Lookup("form")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
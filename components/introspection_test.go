@@ -0,0 +1,86 @@
+package components_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/ocomsoft/HxComponents/examples/card"
+	"github.com/ocomsoft/HxComponents/examples/counter"
+	"github.com/ocomsoft/HxComponents/examples/login"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfoHandler(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestLifecycleComponent](registry, "lifecycle")
+	components.Register[*TestSessionLogin](registry, "login")
+	components.Register[*TestValidatingComponent](registry, "signup")
+
+	req := httptest.NewRequest(http.MethodGet, "/_components", nil)
+	w := httptest.NewRecorder()
+	registry.InfoHandler(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var infos []components.ComponentCapabilities
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &infos))
+	require.Len(t, infos, 3)
+
+	byName := make(map[string]components.ComponentCapabilities)
+	for _, info := range infos {
+		byName[info.Name] = info
+	}
+	assert.Equal(t, []string{"lifecycle", "login", "signup"}, []string{infos[0].Name, infos[1].Name, infos[2].Name})
+
+	lifecycle := byName["lifecycle"]
+	assert.Equal(t, "components_test.TestLifecycleComponent", lifecycle.StructType)
+	assert.True(t, lifecycle.Initializer)
+	assert.ElementsMatch(t, []string{"increment", "decrement", "error"}, lifecycle.Events)
+
+	login := byName["login"]
+	assert.True(t, login.Processor)
+	assert.False(t, login.Validator)
+	assert.Empty(t, login.Events)
+
+	signup := byName["signup"]
+	assert.True(t, signup.Validator)
+	assert.True(t, signup.Initializer)
+}
+
+func TestGetComponentInfoDetails(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSessionLogin](registry, "login")
+
+	info, err := registry.GetComponentInfo("login")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"username", "password"}, info.FormFields)
+	assert.Equal(t, []string{"Processor", "CookieResponse"}, info.Interfaces)
+	assert.Empty(t, info.Events)
+}
+
+func TestComponentsImplementing(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*counter.CounterComponent](registry, "counter")
+	components.Register[*login.LoginComponent](registry, "login")
+	components.Register[*card.CardComponent](registry, "card")
+	components.Register[*TestValidatingComponent](registry, "signup")
+
+	assert.Equal(t, []string{"counter"}, registry.ComponentsImplementing(components.CapabilityEvents))
+	assert.Equal(t, []string{"login"}, registry.ComponentsImplementing(components.CapabilityProcessor))
+	assert.Equal(t, []string{"signup"}, registry.ComponentsImplementing(components.CapabilityValidator))
+	assert.Equal(t, []string{"card", "signup"}, registry.ComponentsImplementing(components.CapabilityInitializer))
+	assert.Empty(t, registry.ComponentsImplementing(components.Capability(99)))
+	assert.Equal(t, "Events", components.CapabilityEvents.String())
+
+	t.Run("events follow the event method prefix", func(t *testing.T) {
+		registry.SetEventMethodPrefix("Handle")
+		defer registry.SetEventMethodPrefix(components.DefaultEventMethodPrefix)
+		assert.Empty(t, registry.ComponentsImplementing(components.CapabilityEvents))
+	})
+}
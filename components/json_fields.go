@@ -0,0 +1,105 @@
+package components
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/form/v4"
+)
+
+// jsonField is a struct field tagged `form:"name,json"`, whose single form value
+// holds JSON to unmarshal into the field, e.g. state kept in a hidden input:
+//
+//	Items []TodoItem `form:"items,json"`
+//
+//	<input type="hidden" name="items" value={ itemsJSON }/>
+type jsonField struct {
+	name  string
+	index []int
+}
+
+// jsonFields returns the `form:"name,json"` fields of structType, including those
+// of embedded structs.
+func jsonFields(structType reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for _, embedded := range jsonFields(field.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				fields = append(fields, embedded)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == "" || name == "-" || !hasTagOption(opts, "json") {
+			continue
+		}
+		fields = append(fields, jsonField{name: name, index: field.Index})
+	}
+	return fields
+}
+
+// hasTagOption reports whether the comma-separated tag options include option.
+func hasTagOption(opts, option string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeForm decodes values into the struct that instance points to. Fields tagged
+// `form:"name,json"` are left out of the form decoding and their value is
+// unmarshalled as JSON instead; a missing or empty value leaves the field at its
+// zero value. Failures are returned as an *ErrDecode.
+func decodeForm(decoder *form.Decoder, instance any, values url.Values) error {
+	v := reflect.ValueOf(instance)
+	var fields []jsonField
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		fields = jsonFields(v.Elem().Type())
+	}
+	if len(fields) == 0 {
+		if err := decoder.Decode(instance, values); err != nil {
+			return newDecodeError(err, values)
+		}
+		return nil
+	}
+
+	formValues := make(url.Values, len(values))
+	for key, vals := range values {
+		formValues[key] = vals
+	}
+	for _, field := range fields {
+		delete(formValues, field.name)
+	}
+	if err := decoder.Decode(instance, formValues); err != nil {
+		return newDecodeError(err, formValues)
+	}
+
+	decodeErr := &ErrDecode{}
+	for _, field := range fields {
+		raw := values.Get(field.name)
+		if raw == "" {
+			continue
+		}
+		target := v.Elem().FieldByIndex(field.index)
+		target.SetZero()
+		if err := json.Unmarshal([]byte(raw), target.Addr().Interface()); err != nil {
+			decodeErr.Fields = append(decodeErr.Fields, FieldError{Field: field.name, Value: raw, Err: err})
+		}
+	}
+	if len(decodeErr.Fields) > 0 {
+		decodeErr.Err = decodeErr.Fields[0].Err
+		return decodeErr
+	}
+	return nil
+}
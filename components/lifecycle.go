@@ -0,0 +1,200 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/a-h/templ"
+	"github.com/go-playground/form/v4"
+)
+
+// The component lifecycle is shared by every path that builds a component from
+// client input: HandlerFor, RenderComponent, BatchHandler and WebSocketHandler.
+// decodeComponent runs the decode phase and its hooks, and runLifecycle the phases
+// from Init to Process; each path adds only what is specific to its transport.
+
+// decodeSettings holds the registry settings used by decodeComponent, read under
+// the registry lock.
+type decodeSettings struct {
+	decoder      *form.Decoder
+	sanitizer    SanitizePolicy
+	postDecode   func(ctx context.Context, component any) error
+	eventParam   string
+	maxFormIndex int
+	maxFormDepth int
+}
+
+// decodeSettingsLocked returns the current decode settings. r.mu must be held.
+func (r *Registry) decodeSettingsLocked() decodeSettings {
+	return decodeSettings{
+		decoder:      r.decoder,
+		sanitizer:    r.sanitizer,
+		postDecode:   r.postDecode,
+		eventParam:   r.eventParam,
+		maxFormIndex: r.maxFormIndex,
+		maxFormDepth: r.maxFormDepth,
+	}
+}
+
+// decodeComponent decodes values into instance and runs the decode hooks: the
+// form key limits and, for components registered WithStrictFields, the unknown
+// field check (only when fromClient, i.e. the values were sent by a client), then
+// decoding, WithSanitize, `default` tags, the SetPostDecode hook and AfterDecode.
+func (r *Registry) decodeComponent(ctx context.Context, entry componentEntry, instance any, values url.Values, settings decodeSettings, fromClient bool) error {
+	if fromClient {
+		if err := checkFormKeys(values, settings.maxFormIndex, settings.maxFormDepth); err != nil {
+			return err
+		}
+		if entry.strictFields {
+			if err := checkUnknownFields(values, entry.structType, r.frameworkFields(settings.eventParam)...); err != nil {
+				return err
+			}
+		}
+	}
+	if err := decodeForm(entry.formDecoder(instance, settings.decoder), instance, values); err != nil {
+		return err
+	}
+	if entry.sanitize {
+		sanitizeFields(instance, settings.sanitizer)
+	}
+	if err := applyDefaults(instance); err != nil {
+		return err
+	}
+	if settings.postDecode != nil {
+		if err := settings.postDecode(ctx, instance); err != nil {
+			return err
+		}
+	}
+	return afterDecode(ctx, instance)
+}
+
+// authorizeFailure maps an error returned by Authorizer.Authorize to the response
+// title, message and status code: 401 for an *ErrUnauthorized, otherwise 403.
+func authorizeFailure(err error) (title, message string, code int) {
+	var unauthorized *ErrUnauthorized
+	if errors.As(err, &unauthorized) {
+		return "Unauthorized", err.Error(), http.StatusUnauthorized
+	}
+	return "Forbidden", fmt.Sprintf("Access denied: %v", err), http.StatusForbidden
+}
+
+// lifecycleOptions configures runLifecycle for one request.
+type lifecycleOptions struct {
+	componentName string
+	// eventName is dispatched after Validate, if not empty.
+	eventName string
+	observer  LifecycleObserver
+	// strictValidation stops the lifecycle when Validate reports errors.
+	strictValidation bool
+	// initialized skips Init, for instances that outlive a request.
+	initialized bool
+	// afterEvent, if set, runs once the event has succeeded.
+	afterEvent func()
+}
+
+// lifecycleResult is what runLifecycle produced besides an error.
+type lifecycleResult struct {
+	replacement      templ.Component
+	eventResult      *EventResult
+	validationErrors []ValidationError
+}
+
+// runLifecycle runs Init (unless opts.initialized), Validate, the event named by
+// opts.eventName and Process on a decoded component, reporting each phase to
+// opts.observer. A failure is returned as a *ComponentError naming the operation
+// ("init", "validate", "event" or "process"). Validation errors are stored in the
+// component and only stop the lifecycle with strict validation, in which case they
+// are also returned in the result for rendering.
+func (r *Registry) runLifecycle(ctx context.Context, instance any, opts lifecycleOptions) (lifecycleResult, error) {
+	var result lifecycleResult
+	fail := func(operation string, err error) (lifecycleResult, error) {
+		return result, &ComponentError{ComponentName: opts.componentName, Operation: operation, Err: err}
+	}
+
+	if !opts.initialized {
+		if err := r.initComponent(ctx, instance, opts); err != nil {
+			return result, err
+		}
+	}
+
+	if validator, ok := instance.(Validator); ok {
+		var errs []ValidationError
+		_ = observePhase(ctx, opts.observer, opts.componentName, PhaseValidate, func() error {
+			errs = validator.Validate(ctx)
+			return nil
+		})
+		if len(errs) > 0 {
+			r.logger().Debug("validation errors",
+				"component", opts.componentName,
+				"errors", errs)
+			if opts.strictValidation {
+				causes := make([]error, len(errs))
+				for i, e := range errs {
+					causes[i] = e
+				}
+				result.validationErrors = errs
+				return fail("validate", errors.Join(causes...))
+			}
+		}
+	}
+
+	if opts.eventName != "" {
+		r.logger().Debug("processing event",
+			"component", opts.componentName,
+			"event", opts.eventName)
+		err := observePhase(ctx, opts.observer, opts.componentName, PhaseEvent, func() error {
+			var err error
+			result.replacement, result.eventResult, err = r.handleEvent(ctx, instance, opts.eventName, opts.componentName)
+			return err
+		})
+		if err != nil {
+			return fail("event", err)
+		}
+		if opts.afterEvent != nil {
+			opts.afterEvent()
+		}
+	}
+
+	if processor, ok := instance.(Processor); ok {
+		err := observePhase(ctx, opts.observer, opts.componentName, PhaseProcess, func() error {
+			return processor.Process(ctx)
+		})
+		if err != nil {
+			return fail("process", err)
+		}
+	}
+	return result, nil
+}
+
+// initComponent runs Init if the component implements Initializer, returning a
+// failure as an "init" *ComponentError.
+func (r *Registry) initComponent(ctx context.Context, instance any, opts lifecycleOptions) error {
+	initializer, ok := instance.(Initializer)
+	if !ok {
+		return nil
+	}
+	err := observePhase(ctx, opts.observer, opts.componentName, PhaseInit, func() error {
+		return initializer.Init(ctx)
+	})
+	if err != nil {
+		return &ComponentError{ComponentName: opts.componentName, Operation: "init", Err: err}
+	}
+	return nil
+}
+
+// lifecycleFailure returns the response title and message for a runLifecycle
+// failure during operation, with err its cause.
+func lifecycleFailure(operation, eventName string, err error) (title, message string) {
+	switch operation {
+	case "init":
+		return "Initialization Error", fmt.Sprintf("Component initialization failed: %v", err)
+	case "validate":
+		return "Validation Error", fmt.Sprintf("Validation failed: %v", err)
+	case "event":
+		return "Event Error", fmt.Sprintf("Event '%s' failed: %v", eventName, err)
+	}
+	return "Processing Error", fmt.Sprintf("Component processing failed: %v", err)
+}
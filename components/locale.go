@@ -0,0 +1,114 @@
+package components
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the locale used when a request expresses no preference.
+const DefaultLocale = "en"
+
+// LocaleAware is implemented by components that render localized text. SetLocale
+// receives the request's preferred language tag (e.g. "fr-CH") after form
+// decoding, before Authorize, Init and the event handlers.
+type LocaleAware interface {
+	SetLocale(string)
+}
+
+// LocaleConfig configures how the registry chooses a request's locale.
+type LocaleConfig struct {
+	// QueryParam, if set, names a query parameter that overrides the header
+	// (e.g. "lang" for ?lang=de).
+	QueryParam string
+	// CookieName, if set, names a cookie that overrides the header, for a language
+	// the user picked earlier. The query parameter takes precedence.
+	CookieName string
+	// Default is used when the request expresses no preference. Defaults to
+	// DefaultLocale.
+	Default string
+}
+
+// SetLocaleConfig configures locale resolution. Without it, the locale is the
+// highest-weighted tag of the Accept-Language header, or DefaultLocale.
+//
+//	registry.SetLocaleConfig(components.LocaleConfig{QueryParam: "lang", CookieName: "lang", Default: "en-GB"})
+func (r *Registry) SetLocaleConfig(config LocaleConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.locale = config
+}
+
+type localeKey struct{}
+
+// withLocale returns a copy of ctx carrying locale.
+func withLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale chosen by HandlerFor for the request and
+// whether one was present (it is not when a component is used outside a handler).
+func LocaleFromContext(ctx context.Context) (string, bool) {
+	locale, ok := ctx.Value(localeKey{}).(string)
+	return locale, ok
+}
+
+// resolveLocale returns the locale for req: the configured query parameter or
+// cookie, then the Accept-Language header, then the default.
+func resolveLocale(req *http.Request, config LocaleConfig) string {
+	if config.QueryParam != "" {
+		if locale := req.URL.Query().Get(config.QueryParam); locale != "" {
+			return locale
+		}
+	}
+	if config.CookieName != "" {
+		if cookie, err := req.Cookie(config.CookieName); err == nil && cookie.Value != "" {
+			return cookie.Value
+		}
+	}
+	if locale := preferredLanguage(req.Header.Get("Accept-Language")); locale != "" {
+		return locale
+	}
+	if config.Default != "" {
+		return config.Default
+	}
+	return DefaultLocale
+}
+
+// preferredLanguage returns the language tag with the highest quality value in an
+// Accept-Language header, keeping header order between equal weights. Wildcards
+// and tags with q=0 are ignored. It returns "" if no tag qualifies.
+func preferredLanguage(header string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				parsed, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					parsed = 0
+				}
+				q = parsed
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	return tags[0].tag
+}
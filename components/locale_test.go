@@ -0,0 +1,77 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGreetingLocaleComponent renders the locale it was given and the one in its context
+type TestGreetingLocaleComponent struct {
+	Locale string `json:"-"`
+}
+
+func (c *TestGreetingLocaleComponent) SetLocale(locale string) {
+	c.Locale = locale
+}
+
+func (c *TestGreetingLocaleComponent) Render(ctx context.Context, w io.Writer) error {
+	fromContext, _ := components.LocaleFromContext(ctx)
+	_, err := fmt.Fprintf(w, "%s|%s", c.Locale, fromContext)
+	return err
+}
+
+func TestLocale(t *testing.T) {
+	get := func(registry *components.Registry, url, acceptLanguage string, cookies ...*http.Cookie) string {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		registry.HandlerFor("greeting")(w, req)
+		return w.Body.String()
+	}
+
+	registry := components.NewRegistry()
+	components.Register[*TestGreetingLocaleComponent](registry, "greeting")
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{"single tag", "de", "de|de"},
+		{"region tag", "fr-CH", "fr-CH|fr-CH"},
+		{"first of equal weights", "es, it", "es|es"},
+		{"highest quality wins", "fr;q=0.5, nl;q=0.9, en;q=0.8", "nl|nl"},
+		{"wildcard and q=0 ignored", "*, pt;q=0, sv;q=0.2", "sv|sv"},
+		{"default when absent", "", "en|en"},
+		{"default when nothing qualifies", "*;q=0.5", "en|en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, get(registry, "/component/greeting", tt.acceptLanguage))
+		})
+	}
+
+	t.Run("query and cookie overrides", func(t *testing.T) {
+		configured := components.NewRegistry()
+		components.Register[*TestGreetingLocaleComponent](configured, "greeting")
+		configured.SetLocaleConfig(components.LocaleConfig{QueryParam: "lang", CookieName: "lang", Default: "en-GB"})
+
+		cookie := &http.Cookie{Name: "lang", Value: "ja"}
+		assert.Equal(t, "ko|ko", get(configured, "/component/greeting?lang=ko", "de", cookie))
+		assert.Equal(t, "ja|ja", get(configured, "/component/greeting", "de", cookie))
+		assert.Equal(t, "de|de", get(configured, "/component/greeting", "de"))
+		assert.Equal(t, "en-GB|en-GB", get(configured, "/component/greeting", ""))
+	})
+}
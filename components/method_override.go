@@ -0,0 +1,69 @@
+package components
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DefaultMethodOverrideField is the form field read by EnableMethodOverride when
+// no field name is given.
+const DefaultMethodOverrideField = "_method"
+
+// MethodOverrideHeader is the request header that can carry an overridden method,
+// taking precedence over the form field.
+const MethodOverrideHeader = "X-HTTP-Method-Override"
+
+// EnableMethodOverride lets POST requests ask to be treated as PUT, PATCH or
+// DELETE, since HTML forms can only submit GET and POST. The method is read from
+// the X-HTTP-Method-Override header or, failing that, the fieldName form field in
+// the request body (DefaultMethodOverrideField if fieldName is empty).
+//
+// An overridden request is handled like any other POST: its body is decoded, CSRF
+// is checked and events are dispatched. The overridden method is what the
+// component sees through HttpMethod, RequestAware and RequestInfoFromContext. An
+// override to POST is ignored. An override to GET or HEAD is rejected with 400 Bad
+// Request, since the request was decoded and CSRF-checked as a POST; send those as
+// plain GET requests instead. An override to any other method is rejected with 405
+// Method Not Allowed, just like a request that used that method directly.
+//
+// Example:
+//
+//	registry.EnableMethodOverride("")
+//
+//	<form hx-post="/component/todo">
+//	    <input type="hidden" name="_method" value="DELETE"/>
+//	    <input type="hidden" name="hxc-event" value="remove"/>
+//	</form>
+func (r *Registry) EnableMethodOverride(fieldName string) {
+	if fieldName == "" {
+		fieldName = DefaultMethodOverrideField
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.methodField = fieldName
+}
+
+// overriddenMethod returns the method a POST request asks to be treated as, or ""
+// if it doesn't ask; the body must already be parsed for field to be read. An
+// override to POST is no override. status is 0 for an accepted override, 400 Bad
+// Request for GET (or HEAD), which can't be reached from a POST body, and 405
+// Method Not Allowed for any other method than PUT, PATCH and DELETE.
+func overriddenMethod(req *http.Request, field string) (method string, status int) {
+	if field == "" || req.Method != http.MethodPost {
+		return "", 0
+	}
+	method = req.Header.Get(MethodOverrideHeader)
+	if method == "" {
+		method = req.PostForm.Get(field)
+	}
+	method = strings.ToUpper(strings.TrimSpace(method))
+	switch method {
+	case "", http.MethodPost:
+		return "", 0
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return method, 0
+	case http.MethodGet, http.MethodHead:
+		return method, http.StatusBadRequest
+	}
+	return method, http.StatusMethodNotAllowed
+}
@@ -0,0 +1,108 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMethodOverrideComponent reports the method it was handled as
+type TestMethodOverrideComponent struct {
+	Title   string `form:"title"`
+	method  string
+	removed bool
+}
+
+func (c *TestMethodOverrideComponent) SetHttpMethod(method string) { c.method = method }
+
+func (c *TestMethodOverrideComponent) OnRemove(ctx context.Context) error {
+	c.removed = true
+	return nil
+}
+
+func (c *TestMethodOverrideComponent) Render(ctx context.Context, w io.Writer) error {
+	info, _ := components.RequestInfoFromContext(ctx)
+	_, err := fmt.Fprintf(w, "<div>%s %s removed=%v</div>", c.method, info.Method, c.removed)
+	return err
+}
+
+func TestMethodOverride(t *testing.T) {
+	registry := components.NewRegistry()
+	registry.EnableMethodOverride("")
+	components.Register[*TestMethodOverrideComponent](registry, "todo")
+	components.Register[*TestMethodOverrideComponent](registry, "todo-strict", components.WithStrictFields())
+
+	post := func(registry *components.Registry, name string, values url.Values, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/"+name, strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		for key, vals := range header {
+			req.Header[key] = vals
+		}
+		w := httptest.NewRecorder()
+		registry.Handler(w, req)
+		return w
+	}
+
+	t.Run("form field overrides the method", func(t *testing.T) {
+		w := post(registry, "todo", url.Values{"_method": {"DELETE"}, "hxc-event": {"remove"}}, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>DELETE DELETE removed=true</div>", w.Body.String())
+	})
+
+	t.Run("header overrides the method", func(t *testing.T) {
+		w := post(registry, "todo", url.Values{"_method": {"DELETE"}}, http.Header{"X-Http-Method-Override": {"put"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>PUT PUT removed=false</div>", w.Body.String())
+	})
+
+	t.Run("strict fields accept the override field", func(t *testing.T) {
+		w := post(registry, "todo-strict", url.Values{"_method": {"PATCH"}, "title": {"milk"}}, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>PATCH PATCH removed=false</div>", w.Body.String())
+	})
+
+	t.Run("disallowed override is rejected", func(t *testing.T) {
+		w := post(registry, "todo", url.Values{"_method": {"TRACE"}, "hxc-event": {"remove"}}, nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Contains(t, w.Body.String(), "Method TRACE is not allowed")
+	})
+
+	t.Run("override to POST is no override", func(t *testing.T) {
+		w := post(registry, "todo", url.Values{"_method": {"post"}, "hxc-event": {"remove"}}, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>POST POST removed=true</div>", w.Body.String())
+	})
+
+	t.Run("override to GET is a bad request", func(t *testing.T) {
+		w := post(registry, "todo", url.Values{"_method": {"GET"}}, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Cannot override POST to GET; send a GET request instead")
+	})
+
+	t.Run("direct DELETE is still not allowed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		registry.Handler(w, httptest.NewRequest(http.MethodDelete, "/component/todo", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("GET ignores the override", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		registry.Handler(w, httptest.NewRequest(http.MethodGet, "/component/todo?_method=DELETE", nil))
+		assert.Equal(t, "<div>GET GET removed=false</div>", w.Body.String())
+	})
+
+	t.Run("override disabled by default", func(t *testing.T) {
+		plain := components.NewRegistry()
+		components.Register[*TestMethodOverrideComponent](plain, "todo")
+		w := post(plain, "todo", url.Values{"_method": {"DELETE"}}, nil)
+		assert.Equal(t, "<div>POST POST removed=false</div>", w.Body.String())
+	})
+}
@@ -0,0 +1,60 @@
+package components
+
+import "context"
+
+// Lifecycle phase names reported to a LifecycleObserver.
+const (
+	PhaseDecode   = "decode"
+	PhaseInit     = "init"
+	PhaseValidate = "validate"
+	PhaseEvent    = "event"
+	PhaseProcess  = "process"
+	PhaseRender   = "render"
+)
+
+// LifecycleObserver is notified around each phase of the component request lifecycle
+// executed by HandlerFor. Phases are reported using the Phase* constants, in order:
+// decode, init, validate, event, process, render. Phases that do not apply to a
+// component (e.g. init for a component without Init) are not reported.
+//
+// This allows timing and error metrics to be collected without coupling the package
+// to a specific metrics library.
+//
+// Example with Prometheus:
+//
+//	type metricsObserver struct {
+//	    mu     sync.Mutex
+//	    starts map[string]time.Time
+//	}
+//
+//	func (o *metricsObserver) PhaseStart(ctx context.Context, component, phase string) {
+//	    o.mu.Lock()
+//	    defer o.mu.Unlock()
+//	    o.starts[component+"/"+phase] = time.Now()
+//	}
+//
+//	func (o *metricsObserver) PhaseEnd(ctx context.Context, component, phase string, err error) {
+//	    o.mu.Lock()
+//	    start := o.starts[component+"/"+phase]
+//	    o.mu.Unlock()
+//	    phaseDuration.WithLabelValues(component, phase).Observe(time.Since(start).Seconds())
+//	}
+//
+// Observers are called concurrently from multiple requests and must be safe for concurrent use.
+type LifecycleObserver interface {
+	// PhaseStart is called immediately before a phase runs.
+	PhaseStart(ctx context.Context, component, phase string)
+	// PhaseEnd is called after a phase completes, with the error it returned (if any).
+	PhaseEnd(ctx context.Context, component, phase string, err error)
+}
+
+// observePhase runs fn as the named phase, notifying the observer (if any) before and after.
+func observePhase(ctx context.Context, observer LifecycleObserver, component, phase string, fn func() error) error {
+	if observer == nil {
+		return fn()
+	}
+	observer.PhaseStart(ctx, component, phase)
+	err := fn()
+	observer.PhaseEnd(ctx, component, phase, err)
+	return err
+}
@@ -0,0 +1,118 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingObserver records every phase notification it receives
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []string
+	errs   map[string]error
+}
+
+func newRecordingObserver() *recordingObserver {
+	return &recordingObserver{errs: make(map[string]error)}
+}
+
+func (o *recordingObserver) PhaseStart(ctx context.Context, component, phase string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, fmt.Sprintf("start:%s:%s", component, phase))
+}
+
+func (o *recordingObserver) PhaseEnd(ctx context.Context, component, phase string, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, fmt.Sprintf("end:%s:%s", component, phase))
+	if err != nil {
+		o.errs[phase] = err
+	}
+}
+
+func TestLifecycleObserver(t *testing.T) {
+	t.Run("reports phases in order", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestLifecycleComponent](registry, "lifecycle")
+		observer := newRecordingObserver()
+		registry.SetLifecycleObserver(observer)
+
+		req := httptest.NewRequest(http.MethodPost, "/component/lifecycle", strings.NewReader("value=1&hxc-event=increment"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		registry.HandlerFor("lifecycle")(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		expected := []string{
+			"start:lifecycle:decode", "end:lifecycle:decode",
+			"start:lifecycle:init", "end:lifecycle:init",
+			"start:lifecycle:event", "end:lifecycle:event",
+			"start:lifecycle:process", "end:lifecycle:process",
+			"start:lifecycle:render", "end:lifecycle:render",
+		}
+		assert.Equal(t, expected, observer.events)
+		assert.Empty(t, observer.errs)
+	})
+
+	t.Run("reports validate phase for validators", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestValidatingComponent](registry, "validating")
+		observer := newRecordingObserver()
+		registry.SetLifecycleObserver(observer)
+
+		req := httptest.NewRequest(http.MethodGet, "/component/validating", nil)
+		w := httptest.NewRecorder()
+
+		registry.HandlerFor("validating")(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, observer.events, "start:validating:validate")
+		assert.Contains(t, observer.events, "end:validating:validate")
+	})
+
+	t.Run("reports errors and stops at failing phase", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestLifecycleComponent](registry, "lifecycle")
+		observer := newRecordingObserver()
+		registry.SetLifecycleObserver(observer)
+
+		req := httptest.NewRequest(http.MethodPost, "/component/lifecycle", strings.NewReader("hxc-event=error"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		registry.HandlerFor("lifecycle")(w, req)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Contains(t, observer.errs, components.PhaseEvent)
+		assert.Contains(t, observer.errs[components.PhaseEvent].Error(), "intentional error")
+		assert.NotContains(t, observer.events, "start:lifecycle:process")
+		assert.NotContains(t, observer.events, "start:lifecycle:render")
+	})
+
+	t.Run("reports decode errors", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestLifecycleComponent](registry, "lifecycle")
+		observer := newRecordingObserver()
+		registry.SetLifecycleObserver(observer)
+
+		req := httptest.NewRequest(http.MethodGet, "/component/lifecycle?value=abc", nil)
+		w := httptest.NewRecorder()
+
+		registry.HandlerFor("lifecycle")(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, observer.errs, components.PhaseDecode)
+		assert.Equal(t, []string{"start:lifecycle:decode", "end:lifecycle:decode"}, observer.events)
+	})
+}
@@ -0,0 +1,43 @@
+package components
+
+import (
+	"context"
+	"io"
+
+	"github.com/a-h/templ"
+)
+
+// OutOfBandComponent is an optional interface for components that update other
+// parts of the page in the same response using HTMX out-of-band swaps. The returned
+// components are rendered after the main component, in order, and should each carry
+// an hx-swap-oob attribute on their root element.
+//
+// Example:
+//
+//	func (c *CartItem) OutOfBand(ctx context.Context) []templ.Component {
+//	    return []templ.Component{CartBadge(c.CartCount)} // <span id="cart-badge" hx-swap-oob="true">
+//	}
+type OutOfBandComponent interface {
+	OutOfBand(ctx context.Context) []templ.Component
+}
+
+// renderWithOutOfBand renders main followed by the out-of-band components of
+// instance, if it implements OutOfBandComponent.
+func renderWithOutOfBand(ctx context.Context, w io.Writer, main templ.Component, instance any) error {
+	if err := main.Render(ctx, w); err != nil {
+		return err
+	}
+	oob, ok := instance.(OutOfBandComponent)
+	if !ok {
+		return nil
+	}
+	for _, c := range oob.OutOfBand(ctx) {
+		if c == nil {
+			continue
+		}
+		if err := c.Render(ctx, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
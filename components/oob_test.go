@@ -0,0 +1,54 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCartItemComponent updates a cart badge out of band when an item is added
+type TestCartItemComponent struct {
+	Count int `form:"count"`
+}
+
+func (c *TestCartItemComponent) OnAdd(ctx context.Context) error {
+	c.Count++
+	return nil
+}
+
+func (c *TestCartItemComponent) OutOfBand(ctx context.Context) []templ.Component {
+	return []templ.Component{
+		templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := fmt.Fprintf(w, `<span id="cart-badge" hx-swap-oob="true">%d</span>`, c.Count)
+			return err
+		}),
+	}
+}
+
+func (c *TestCartItemComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, "<div>Added to cart</div>")
+	return err
+}
+
+func TestOutOfBandComponent(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestCartItemComponent](registry, "cart-item")
+
+	req := httptest.NewRequest(http.MethodGet, "/component/cart-item?count=2&hxc-event=add", nil)
+	w := httptest.NewRecorder()
+	registry.HandlerFor("cart-item")(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `<div>Added to cart</div><span id="cart-badge" hx-swap-oob="true">3</span>`, w.Body.String())
+
+	info, err := registry.GetComponentInfo("cart-item")
+	assert.NoError(t, err)
+	assert.Contains(t, info.Interfaces, "OutOfBandComponent")
+}
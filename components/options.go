@@ -0,0 +1,280 @@
+package components
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/go-playground/form/v4"
+)
+
+// RegisterOption configures optional per-component behavior at registration time.
+// Options are passed as trailing arguments to Register:
+//
+//	components.Register[*search.SearchComponent](registry, "search",
+//	    components.WithRateLimit(5, 10),
+//	)
+type RegisterOption func(*componentEntry)
+
+// WithRateLimit limits requests to the component using a token bucket per client.
+// rps is the sustained number of requests per second and burst the maximum number
+// of requests allowed at once. Clients are identified by IP address unless a key
+// function is supplied with WithRateLimitKey.
+//
+// Requests over the limit receive a 429 Too Many Requests error with a Retry-After header.
+func WithRateLimit(rps float64, burst int) RegisterOption {
+	return func(e *componentEntry) {
+		e.rateLimiter = newRateLimiter(rps, burst)
+	}
+}
+
+// WithRateLimitKey sets the function used to identify clients for rate limiting
+// (e.g. by session or API key instead of IP address).
+func WithRateLimitKey(keyFunc func(*http.Request) string) RegisterOption {
+	return func(e *componentEntry) {
+		e.rateLimitKey = keyFunc
+	}
+}
+
+// WithCache caches the component's rendered output and HX-* response headers in
+// memory for ttl. Responses are keyed by keyFunc, or by the request's query
+// parameters when keyFunc is nil, and are kept apart for requests that differ in
+// a header named by the component's Vary method, in HX-Request (for HxRequest
+// components) or in locale (for LocaleAware components). At most 1000 responses
+// are kept per component; the one closest to expiry makes way for a new one.
+//
+// Only GET requests without an hxc-event parameter are cached. A request that
+// dispatches an event clears the component's cache, as does Registry.InvalidateCache.
+// Cached responses are served after decoding and authorization but before Init,
+// so Authorizer checks still apply to every request.
+func WithCache(ttl time.Duration, keyFunc func(*http.Request) string) RegisterOption {
+	return func(e *componentEntry) {
+		e.cache = newResponseCache(ttl, keyFunc)
+	}
+}
+
+// WithETag renders the component into a buffer, sets a strong ETag header computed
+// from the output, and responds with 304 Not Modified when the request's
+// If-None-Match header matches. This reduces bandwidth for frequently polled
+// components at the cost of buffering the whole response instead of streaming it.
+func WithETag() RegisterOption {
+	return func(e *componentEntry) {
+		e.etag = true
+	}
+}
+
+// WithBufferedRender renders the component into a buffer and only writes it to the
+// response once Render succeeds. If Render fails partway, the partial output and any
+// HX-* response headers are discarded and the error handler renders a clean error
+// response. See also Registry.SetBufferedRender to enable this for all components.
+func WithBufferedRender() RegisterOption {
+	return func(e *componentEntry) {
+		e.bufferedRender = true
+	}
+}
+
+// WithIdempotency deduplicates mutating event requests. When a POST event request
+// carries an Idempotency-Key header, the rendered response (status code, headers
+// other than Set-Cookie, and body) is stored for ttl and replayed for later
+// requests from the same client with the same key instead of running the event
+// again. Clients are identified by IP address unless a key function is supplied
+// with WithIdempotencyClientKey. A duplicate that arrives while the first request
+// is still running waits for it. Failed requests are not stored, so they can be
+// retried with the same key.
+func WithIdempotency(ttl time.Duration) RegisterOption {
+	if ttl <= 0 {
+		panic("idempotency ttl must be greater than zero")
+	}
+	return func(e *componentEntry) {
+		e.idempotency = newIdempotencyStore(ttl)
+	}
+}
+
+// WithIdempotencyField also reads the idempotency key for WithIdempotency from the
+// named form field (e.g. a hidden input) when the Idempotency-Key header is absent.
+func WithIdempotencyField(field string) RegisterOption {
+	return func(e *componentEntry) {
+		e.idemField = field
+	}
+}
+
+// WithIdempotencyClientKey sets the function used to identify clients for
+// WithIdempotency (e.g. by session instead of IP address).
+func WithIdempotencyClientKey(keyFunc func(*http.Request) string) RegisterOption {
+	return func(e *componentEntry) {
+		e.idemClientKey = keyFunc
+	}
+}
+
+// WithInitialState sets a factory that produces the seeded instance each request is
+// decoded into, so form values override the defaults while unset fields keep them:
+//
+//	components.Register[*search.SearchComponent](registry, "search",
+//	    components.WithInitialState(func() *search.SearchComponent {
+//	        return &search.SearchComponent{Limit: 10}
+//	    }),
+//	)
+//
+// The factory runs once per request and must return a new instance each time, since
+// the instance is mutated during the request. It panics at registration if T is not
+// the registered component type.
+func WithInitialState[T templ.Component](factory func() T) RegisterOption {
+	return func(e *componentEntry) {
+		var zero T
+		if got, want := reflect.TypeOf(zero), reflect.PointerTo(e.structType); got != want {
+			panic(fmt.Sprintf("WithInitialState factory returns %v, but the component type is %v", got, want))
+		}
+		e.initialState = func() any { return factory() }
+	}
+}
+
+// WithFactory constructs each request's instance with factory instead of
+// reflect.New, so components can receive dependencies such as a repository
+// captured in a closure rather than looking them up in Init:
+//
+//	components.Register[*todo.TodoList](registry, "todos",
+//	    components.WithFactory(func() *todo.TodoList {
+//	        return &todo.TodoList{Repo: repo}
+//	    }),
+//	)
+//
+// Form values are decoded into the constructed instance. The factory runs once per
+// request and must return a new instance each time. It is the struct-typed form of
+// WithInitialState and panics at registration if T is not the component's struct type.
+func WithFactory[T any](factory func() *T) RegisterOption {
+	return func(e *componentEntry) {
+		if got := reflect.TypeOf((*T)(nil)).Elem(); got != e.structType {
+			panic(fmt.Sprintf("WithFactory factory returns *%v, but the component type is *%v", got, e.structType))
+		}
+		e.initialState = func() any { return factory() }
+	}
+}
+
+// WithTimeout bounds the component's lifecycle. The request context passed to
+// Authorize, Init, event handlers, Process and Render is cancelled after d, and a
+// request that runs past it receives a 504 Gateway Timeout. Long-running work such
+// as database calls should honor ctx so it stops promptly. See also
+// Registry.SetDefaultTimeout.
+func WithTimeout(d time.Duration) RegisterOption {
+	return func(e *componentEntry) {
+		e.timeout = d
+	}
+}
+
+// WithDecoderTagName decodes the component's form data using the given struct tag
+// (e.g. "json") instead of "form", without implementing FormDecoder. A decoder
+// supplied by the component's GetFormDecoder method still takes precedence.
+func WithDecoderTagName(tagName string) RegisterOption {
+	return func(e *componentEntry) {
+		e.decoderConfig = append(e.decoderConfig, func(d *form.Decoder) { d.SetTagName(tagName) })
+	}
+}
+
+// WithDecoderMode sets the decoding mode for the component's form data, e.g.
+// form.ModeExplicit to decode only fields that have a tag. A decoder supplied by
+// the component's GetFormDecoder method still takes precedence.
+func WithDecoderMode(mode form.Mode) RegisterOption {
+	return func(e *componentEntry) {
+		e.decoderConfig = append(e.decoderConfig, func(d *form.Decoder) { d.SetMode(mode) })
+	}
+}
+
+// WithValidationTarget redirects the swap of a strict validation failure (see
+// Registry.SetStrictValidation) by setting the HX-Retarget header to selector and,
+// if swap is not empty, HX-Reswap to swap. The headers are only sent when Validate
+// fails, so errors can be shown in a dedicated container without implementing the
+// response header interfaces:
+//
+//	components.Register[*signup.SignupForm](registry, "signup",
+//	    components.WithValidationTarget("#signup-errors", "innerHTML"),
+//	)
+func WithValidationTarget(selector, swap string) RegisterOption {
+	return func(e *componentEntry) {
+		e.validationTarget = selector
+		e.validationSwap = swap
+	}
+}
+
+// WithoutFormParsing leaves the request body unread: the handler skips ParseForm and
+// form decoding for the component, so it can read req.Body itself via RequestAware
+// (e.g. for JSON or another custom encoding). Events can still be requested in the
+// query string, and the body size limit still applies. If CSRF protection is
+// enabled, the token must be sent in the header, since the form field is not read.
+func WithoutFormParsing() RegisterOption {
+	return func(e *componentEntry) {
+		e.skipFormParse = true
+	}
+}
+
+// WithDebounce collapses repeated events from the same client within window: the
+// first request runs the event and its response is replayed for identical events
+// that follow until the window ends. Events are identical when they have the same
+// name and form values; an event with different values always runs. Clients are
+// identified by their IP address and session cookies unless a key function is
+// supplied with WithDebounceKey. Replayed responses carry the stored status and
+// headers but never Set-Cookie. This absorbs key-repeat and fast polling without
+// the 429 errors of WithRateLimit. Requests carrying an idempotency key (see
+// WithIdempotency) use that instead. Stored responses expire after window and the
+// store is bounded.
+func WithDebounce(window time.Duration) RegisterOption {
+	if window <= 0 {
+		panic("debounce window must be greater than zero")
+	}
+	return func(e *componentEntry) {
+		e.debounce = newIdempotencyStore(window)
+	}
+}
+
+// WithDebounceKey sets the function used to identify clients for WithDebounce
+// (e.g. by user ID instead of IP address and session cookies).
+func WithDebounceKey(keyFunc func(*http.Request) string) RegisterOption {
+	return func(e *componentEntry) {
+		e.debounceKey = keyFunc
+	}
+}
+
+// WithStrictFields rejects requests with a 400 when the form data contains fields
+// that do not match a `form`-tagged field of the component, listing them in an
+// *ErrUnknownFields. This catches templates that still send a field after it was
+// renamed or removed. The event parameter, the CSRF field and the trace parameter
+// are always allowed. Nested keys such as "address.city" or "items[0].qty" are
+// matched by their top-level name.
+func WithStrictFields() RegisterOption {
+	return func(e *componentEntry) {
+		e.strictFields = true
+	}
+}
+
+// WithRecover controls whether HandlerFor recovers panics in the component. It
+// defaults to true: the panic is logged and rendered as a 500, or as the view of a
+// RecoverComponent. WithRecover(false) lets panics propagate to the router's own
+// recovery middleware (e.g. chi's middleware.Recoverer) or to a test, with their
+// original stack, which helps while debugging.
+func WithRecover(enabled bool) RegisterOption {
+	return func(e *componentEntry) {
+		e.noRecover = !enabled
+	}
+}
+
+// WithSanitize applies the registry's SanitizePolicy (StripTags unless changed with
+// SetSanitizer) to the component's string and []string fields after decoding, so
+// markup submitted by a client is removed before the component stores or renders
+// it. This is defense in depth for values that end up in attributes, scripts or
+// other systems; it does not replace templ's escaping.
+func WithSanitize() RegisterOption {
+	return func(e *componentEntry) {
+		e.sanitize = true
+	}
+}
+
+// WithGetNoEvents makes GET (and HEAD) requests for the component read-only: they
+// never dispatch events, even if the URL carries an hxc-event parameter, but still
+// run decode, Init, Validate, Process and Render. Use it for display components so
+// state cannot be changed through a crafted link; events must then be sent by POST.
+func WithGetNoEvents() RegisterOption {
+	return func(e *componentEntry) {
+		e.getNoEvents = true
+	}
+}
@@ -0,0 +1,269 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/form/v4"
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPagedComponent renders its query and page size
+type TestPagedComponent struct {
+	Query string `form:"q"`
+	Limit int    `form:"limit"`
+}
+
+func (c *TestPagedComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>%s limit=%d</div>", c.Query, c.Limit)
+	return nil
+}
+
+func TestWithInitialState(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestPagedComponent](registry, "paged",
+		components.WithInitialState(func() *TestPagedComponent {
+			return &TestPagedComponent{Limit: 10}
+		}),
+	)
+
+	get := func(query string) string {
+		req := httptest.NewRequest(http.MethodGet, "/component/paged?"+query, nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("paged")(w, req)
+		return w.Body.String()
+	}
+
+	t.Run("unset fields keep the seeded value", func(t *testing.T) {
+		assert.Equal(t, "<div>go limit=10</div>", get("q=go"))
+	})
+
+	t.Run("form values override the seed", func(t *testing.T) {
+		assert.Equal(t, "<div>go limit=3</div>", get("q=go&limit=3"))
+	})
+
+	t.Run("each request gets a fresh seed", func(t *testing.T) {
+		get("limit=99")
+		assert.Equal(t, "<div> limit=10</div>", get(""))
+	})
+
+	t.Run("panics when the factory type does not match", func(t *testing.T) {
+		assert.Panics(t, func() {
+			components.Register[*TestPagedComponent](components.NewRegistry(), "paged",
+				components.WithInitialState(func() *TestSimpleCounter { return &TestSimpleCounter{} }),
+			)
+		})
+	})
+}
+
+// TestJSONTaggedComponent uses json tags for its form fields
+type TestJSONTaggedComponent struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Visited int
+}
+
+func (c *TestJSONTaggedComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>%s %s %d</div>", c.Name, c.Email, c.Visited)
+	return nil
+}
+
+// TestCustomDecoderComponent supplies its own decoder, which uses form tags
+type TestCustomDecoderComponent struct {
+	Name string `form:"name"`
+}
+
+func (c *TestCustomDecoderComponent) GetFormDecoder() *form.Decoder {
+	return form.NewDecoder()
+}
+
+func (c *TestCustomDecoderComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>%s</div>", c.Name)
+	return nil
+}
+
+func TestDecoderOptions(t *testing.T) {
+	post := func(registry *components.Registry, name string, values url.Values) string {
+		req := httptest.NewRequest(http.MethodPost, "/component/"+name, strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor(name)(w, req)
+		return w.Body.String()
+	}
+	values := url.Values{"name": {"Ada"}, "email": {"ada@example.com"}, "Visited": {"3"}}
+
+	t.Run("WithDecoderTagName decodes json tags", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestJSONTaggedComponent](registry, "profile",
+			components.WithDecoderTagName("json"))
+
+		assert.Equal(t, "<div>Ada ada@example.com 3</div>", post(registry, "profile", values))
+	})
+
+	t.Run("WithDecoderMode explicit skips untagged fields", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestJSONTaggedComponent](registry, "profile",
+			components.WithDecoderTagName("json"),
+			components.WithDecoderMode(form.ModeExplicit))
+
+		assert.Equal(t, "<div>Ada ada@example.com 0</div>", post(registry, "profile", values))
+	})
+
+	t.Run("default decoder ignores json tags", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestJSONTaggedComponent](registry, "profile")
+
+		assert.Equal(t, "<div>  3</div>", post(registry, "profile", values))
+	})
+
+	t.Run("component decoder takes precedence", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestCustomDecoderComponent](registry, "custom",
+			components.WithDecoderTagName("json"))
+
+		assert.Equal(t, "<div>Ada</div>", post(registry, "custom", values))
+	})
+}
+
+// testGreetingRepo is the dependency injected into TestGreetingComponent
+type testGreetingRepo interface {
+	Greeting(name string) (string, error)
+}
+
+// fakeGreetingRepo records the names it was asked about
+type fakeGreetingRepo struct {
+	lookups []string
+}
+
+func (r *fakeGreetingRepo) Greeting(name string) (string, error) {
+	r.lookups = append(r.lookups, name)
+	return "Kia ora, " + name, nil
+}
+
+// TestGreetingComponent loads its message from an injected repository
+type TestGreetingComponent struct {
+	Name    string           `form:"name"`
+	Repo    testGreetingRepo `json:"-"`
+	Message string           `json:"-"`
+}
+
+func (c *TestGreetingComponent) Process(ctx context.Context) error {
+	if c.Repo == nil {
+		return fmt.Errorf("no repository")
+	}
+	message, err := c.Repo.Greeting(c.Name)
+	c.Message = message
+	return err
+}
+
+func (c *TestGreetingComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<p>%s</p>", c.Message)
+	return nil
+}
+
+func TestWithFactory(t *testing.T) {
+	repo := &fakeGreetingRepo{}
+	registry := components.NewRegistry()
+	components.Register[*TestGreetingComponent](registry, "greeting",
+		components.WithFactory(func() *TestGreetingComponent {
+			return &TestGreetingComponent{Repo: repo}
+		}),
+	)
+
+	for _, name := range []string{"Aroha", "Tama"} {
+		req := httptest.NewRequest(http.MethodGet, "/component/greeting?name="+name, nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("greeting")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<p>Kia ora, "+name+"</p>", w.Body.String())
+	}
+	assert.Equal(t, []string{"Aroha", "Tama"}, repo.lookups)
+
+	t.Run("panics when the factory type does not match", func(t *testing.T) {
+		assert.Panics(t, func() {
+			components.Register[*TestPagedComponent](components.NewRegistry(), "paged",
+				components.WithFactory(func() *TestGreetingComponent { return &TestGreetingComponent{} }),
+			)
+		})
+	})
+}
+
+func TestWithGetNoEvents(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestLifecycleComponent](registry, "counter", components.WithGetNoEvents())
+
+	t.Run("GET ignores the event parameter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/counter?value=5&hxc-event=increment", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>5</div>", w.Body.String())
+	})
+
+	t.Run("POST still dispatches events", func(t *testing.T) {
+		body := url.Values{"value": {"5"}, "hxc-event": {"increment"}}
+		req := httptest.NewRequest(http.MethodPost, "/component/counter", strings.NewReader(body.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>6</div>", w.Body.String())
+	})
+}
+
+func TestWithStrictFields(t *testing.T) {
+	post := func(registry *components.Registry, body url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/counter", strings.NewReader(body.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+		return w
+	}
+
+	t.Run("strict component rejects unknown fields", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSimpleCounter](registry, "counter", components.WithStrictFields())
+
+		var reported error
+		registry.SetOnError(func(ctx context.Context, err *components.ComponentError) {
+			reported = err.Err
+		})
+
+		w := post(registry, url.Values{"count": {"1"}, "foo": {"bar"}, "hxc-event": {"increment"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Unexpected form fields: foo")
+
+		var unknown *components.ErrUnknownFields
+		if assert.ErrorAs(t, reported, &unknown) {
+			assert.Equal(t, []string{"foo"}, unknown.Fields)
+		}
+	})
+
+	t.Run("strict component accepts known and framework fields", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSimpleCounter](registry, "counter", components.WithStrictFields())
+
+		w := post(registry, url.Values{"count": {"1"}, "hxc-event": {"increment"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>2</div>", w.Body.String())
+	})
+
+	t.Run("non-strict component tolerates unknown fields", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSimpleCounter](registry, "counter")
+
+		w := post(registry, url.Values{"count": {"1"}, "foo": {"bar"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>1</div>", w.Body.String())
+	})
+}
@@ -0,0 +1,103 @@
+package components
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageSize is the page size of a Paginator when none is submitted.
+const DefaultPageSize = 20
+
+// Paginator holds the paging state of a list component. Embed it in a component
+// and the registry decodes the `page` and `pageSize` form fields into it, falling
+// back to page 1 and DefaultPageSize when they are missing. Set Total once the
+// total number of items is known, e.g. in Process, and render PageLinks:
+//
+//	type SearchComponent struct {
+//	    components.Paginator
+//	    Query   string   `form:"q"`
+//	    Results []Result `form:"-"`
+//	}
+//
+//	func (c *SearchComponent) Process(ctx context.Context) error {
+//	    c.Results, c.Total = search(c.Query, c.Offset(), c.PageSize)
+//	    return nil
+//	}
+//
+// Pages are numbered from 1. The methods treat a page below 1 as page 1 and a
+// page size below 1 as DefaultPageSize, so a zero Paginator is usable as is.
+type Paginator struct {
+	Page     int `form:"page" default:"1"`
+	PageSize int `form:"pageSize" default:"20"`
+	Total    int `form:"-"`
+}
+
+// Offset returns the index of the first item on the current page.
+func (p Paginator) Offset() int {
+	return (p.page() - 1) * p.size()
+}
+
+// TotalPages returns the number of pages needed for Total items, which is 0 when
+// there are no items.
+func (p Paginator) TotalPages() int {
+	if p.Total <= 0 {
+		return 0
+	}
+	return (p.Total + p.size() - 1) / p.size()
+}
+
+// HasNext reports whether there is a page after the current one.
+func (p Paginator) HasNext() bool {
+	return p.page() < p.TotalPages()
+}
+
+// HasPrev reports whether there is a page before the current one.
+func (p Paginator) HasPrev() bool {
+	return p.page() > 1
+}
+
+// PageURL returns base with its `page` and `pageSize` query parameters set for
+// the given page, keeping any other parameters.
+func (p Paginator) PageURL(base string, page int) string {
+	path, rawQuery, _ := strings.Cut(base, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		query = url.Values{}
+	}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("pageSize", strconv.Itoa(p.size()))
+	return path + "?" + query.Encode()
+}
+
+// page returns the current page, at least 1.
+func (p Paginator) page() int {
+	if p.Page < 1 {
+		return 1
+	}
+	return p.Page
+}
+
+// size returns the page size, or DefaultPageSize if none is set.
+func (p Paginator) size() int {
+	if p.PageSize < 1 {
+		return DefaultPageSize
+	}
+	return p.PageSize
+}
+
+// pageLinkWindow is how many pages PageLinks links to on each side of the
+// current page.
+const pageLinkWindow = 2
+
+// linkedPages returns the page numbers PageLinks links to: the current page and
+// up to pageLinkWindow pages on each side of it.
+func (p Paginator) linkedPages() []int {
+	first := max(1, p.page()-pageLinkWindow)
+	last := min(p.TotalPages(), p.page()+pageLinkWindow)
+	pages := make([]int, 0, max(0, last-first+1))
+	for n := first; n <= last; n++ {
+		pages = append(pages, n)
+	}
+	return pages
+}
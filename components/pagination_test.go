@@ -0,0 +1,118 @@
+package components_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginator(t *testing.T) {
+	tests := []struct {
+		name       string
+		paginator  components.Paginator
+		offset     int
+		totalPages int
+		hasNext    bool
+		hasPrev    bool
+	}{
+		{"first page", components.Paginator{Page: 1, PageSize: 10, Total: 25}, 0, 3, true, false},
+		{"middle page", components.Paginator{Page: 2, PageSize: 10, Total: 25}, 10, 3, true, true},
+		{"last partial page", components.Paginator{Page: 3, PageSize: 10, Total: 25}, 20, 3, false, true},
+		{"last full page", components.Paginator{Page: 2, PageSize: 10, Total: 20}, 10, 2, false, true},
+		{"page 0 is the first page", components.Paginator{Page: 0, PageSize: 10, Total: 25}, 0, 3, true, false},
+		{"negative page is the first page", components.Paginator{Page: -3, PageSize: 10, Total: 25}, 0, 3, true, false},
+		{"past the last page", components.Paginator{Page: 5, PageSize: 10, Total: 25}, 40, 3, false, true},
+		{"empty results", components.Paginator{Page: 1, PageSize: 10, Total: 0}, 0, 0, false, false},
+		{"zero page size uses the default", components.Paginator{Page: 2, Total: 45}, components.DefaultPageSize, 3, true, true},
+		{"zero value", components.Paginator{}, 0, 0, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.offset, tt.paginator.Offset())
+			assert.Equal(t, tt.totalPages, tt.paginator.TotalPages())
+			assert.Equal(t, tt.hasNext, tt.paginator.HasNext())
+			assert.Equal(t, tt.hasPrev, tt.paginator.HasPrev())
+		})
+	}
+}
+
+func TestPaginatorPageURL(t *testing.T) {
+	p := components.Paginator{Page: 1, PageSize: 10}
+	assert.Equal(t, "/component/search?page=2&pageSize=10", p.PageURL("/component/search", 2))
+	assert.Equal(t, "/component/search?page=3&pageSize=10&q=go", p.PageURL("/component/search?q=go&page=1", 3))
+}
+
+// TestPagedListComponent embeds a Paginator over a fixed list of items
+type TestPagedListComponent struct {
+	components.Paginator
+	Query string `form:"q"`
+}
+
+func (c *TestPagedListComponent) Process(ctx context.Context) error {
+	c.Total = 95
+	return nil
+}
+
+func (c *TestPagedListComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "page=%d size=%d offset=%d pages=%d", c.Page, c.PageSize, c.Offset(), c.TotalPages())
+	return err
+}
+
+func TestPaginatorDecoding(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestPagedListComponent](registry, "list")
+
+	get := func(query string) string {
+		req := httptest.NewRequest(http.MethodGet, "/component/list"+query, nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("list")(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		return w.Body.String()
+	}
+
+	t.Run("page fields are decoded into the embedded paginator", func(t *testing.T) {
+		assert.Equal(t, "page=3 size=10 offset=20 pages=10", get("?q=go&page=3&pageSize=10"))
+	})
+
+	t.Run("missing page fields use the defaults", func(t *testing.T) {
+		assert.Equal(t, "page=1 size=20 offset=0 pages=5", get("?q=go"))
+	})
+}
+
+func TestPageLinks(t *testing.T) {
+	render := func(p components.Paginator) string {
+		var buf bytes.Buffer
+		require.NoError(t, components.PageLinks(p, "/component/search?q=go").Render(context.Background(), &buf))
+		return buf.String()
+	}
+
+	t.Run("links around the current page", func(t *testing.T) {
+		html := render(components.Paginator{Page: 4, PageSize: 10, Total: 95})
+		assert.Contains(t, html, `rel="prev" hx-get="/component/search?page=3&amp;pageSize=10&amp;q=go"`)
+		assert.Contains(t, html, `rel="next" hx-get="/component/search?page=5&amp;pageSize=10&amp;q=go"`)
+		assert.Contains(t, html, `<span aria-current="page">4</span>`)
+		assert.Contains(t, html, `hx-get="/component/search?page=2&amp;pageSize=10&amp;q=go"`)
+		assert.Contains(t, html, `hx-get="/component/search?page=6&amp;pageSize=10&amp;q=go"`)
+		assert.NotContains(t, html, "page=1&amp;")
+		assert.NotContains(t, html, "page=7&amp;")
+	})
+
+	t.Run("no previous link on the first page or next link on the last", func(t *testing.T) {
+		assert.NotContains(t, render(components.Paginator{Page: 1, PageSize: 10, Total: 95}), `rel="prev"`)
+		assert.NotContains(t, render(components.Paginator{Page: 10, PageSize: 10, Total: 95}), `rel="next"`)
+	})
+
+	t.Run("nothing for a single page", func(t *testing.T) {
+		assert.Empty(t, render(components.Paginator{Page: 1, PageSize: 10, Total: 7}))
+		assert.Empty(t, render(components.Paginator{Page: 1, PageSize: 10}))
+	})
+}
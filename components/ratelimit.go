@@ -0,0 +1,138 @@
+package components
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimitSweepInterval controls how often idle buckets are removed.
+const rateLimitSweepInterval = time.Minute
+
+// rateLimiter is a concurrency-safe token bucket rate limiter keyed by client.
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64 // tokens added per second
+	burst     float64 // bucket capacity
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// tokenBucket holds the state for a single client.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter creates a rate limiter allowing rps requests per second with the given burst.
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	if rps <= 0 {
+		panic("rate limit rps must be greater than zero")
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:    rps,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// allow reports whether a request for key is allowed at now. When it is not,
+// the returned duration is how long until a token becomes available.
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	// Refill tokens for the time elapsed since the last request
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep removes buckets that have refilled completely, bounding memory use.
+// Must be called with l.mu held.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// SetDefaultRateLimit applies a rate limit to every component that was not
+// registered with its own WithRateLimit option. Each component is limited
+// independently. Pass rps <= 0 to remove the default.
+func (r *Registry) SetDefaultRateLimit(rps float64, burst int) {
+	var limiter *rateLimiter
+	if rps > 0 {
+		limiter = newRateLimiter(rps, burst)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultRateLimiter = limiter
+}
+
+// checkRateLimit reports whether the request may proceed. When it may not, the
+// returned duration is the suggested Retry-After delay.
+func (r *Registry) checkRateLimit(entry componentEntry, componentName string, req *http.Request) (bool, time.Duration) {
+	keyFunc := entry.rateLimitKey
+	if keyFunc == nil {
+		keyFunc = clientIP
+	}
+
+	if entry.rateLimiter != nil {
+		return entry.rateLimiter.allow(keyFunc(req), time.Now())
+	}
+
+	r.mu.RLock()
+	limiter := r.defaultRateLimiter
+	r.mu.RUnlock()
+	if limiter == nil {
+		return true, 0
+	}
+	// The default limiter is shared, so scope buckets to the component
+	return limiter.allow(componentName+"|"+keyFunc(req), time.Now())
+}
+
+// clientIP returns the IP address of the client from req.RemoteAddr.
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// retryAfterSeconds converts a wait duration into a whole number of seconds (at least 1)
+// suitable for the Retry-After header.
+func retryAfterSeconds(wait time.Duration) int {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
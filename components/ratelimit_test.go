@@ -0,0 +1,100 @@
+package components_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getFrom(registry *components.Registry, name, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/component/"+name, nil)
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	registry.HandlerFor(name)(w, req)
+	return w
+}
+
+func TestWithRateLimit(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSimpleCounter](registry, "limited", components.WithRateLimit(0.01, 2))
+	components.Register[*TestSimpleCounter](registry, "unlimited")
+
+	t.Run("requests past the burst are rejected with Retry-After", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			w := getFrom(registry, "limited", "10.0.0.1:1234")
+			require.Equal(t, http.StatusOK, w.Code, "request %d should be allowed", i+1)
+		}
+
+		w := getFrom(registry, "limited", "10.0.0.1:1234")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Contains(t, w.Body.String(), "Too Many Requests")
+
+		retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, retryAfter, 1)
+	})
+
+	t.Run("a different client IP is unaffected", func(t *testing.T) {
+		w := getFrom(registry, "limited", "10.0.0.2:1234")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("a different component is unaffected", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			w := getFrom(registry, "unlimited", "10.0.0.1:1234")
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+	})
+}
+
+func TestWithRateLimitKey(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSimpleCounter](registry, "limited",
+		components.WithRateLimit(0.01, 1),
+		components.WithRateLimitKey(func(req *http.Request) string {
+			return req.Header.Get("X-API-Key")
+		}),
+	)
+
+	request := func(apiKey, remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/component/limited", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("limited")(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("key-a", "10.0.0.1:1"))
+	// Same key from another IP shares the bucket
+	assert.Equal(t, http.StatusTooManyRequests, request("key-a", "10.0.0.2:1"))
+	assert.Equal(t, http.StatusOK, request("key-b", "10.0.0.1:1"))
+}
+
+func TestSetDefaultRateLimit(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSimpleCounter](registry, "first")
+	components.Register[*TestSimpleCounter](registry, "second")
+	components.Register[*TestSimpleCounter](registry, "own", components.WithRateLimit(100, 100))
+	registry.SetDefaultRateLimit(0.01, 1)
+
+	assert.Equal(t, http.StatusOK, getFrom(registry, "first", "10.0.0.1:1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, getFrom(registry, "first", "10.0.0.1:1").Code)
+
+	// Each component is limited independently
+	assert.Equal(t, http.StatusOK, getFrom(registry, "second", "10.0.0.1:1").Code)
+
+	// Components with their own limit ignore the default
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, getFrom(registry, "own", "10.0.0.1:1").Code)
+	}
+
+	// Removing the default lifts the limit
+	registry.SetDefaultRateLimit(0, 0)
+	assert.Equal(t, http.StatusOK, getFrom(registry, "first", "10.0.0.1:1").Code)
+}
@@ -0,0 +1,61 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/a-h/templ"
+)
+
+// RecoverComponent is an optional interface that components can implement to
+// render their own fallback view when the handler panics.
+//
+// Recover is called with the value passed to panic and returns the component to
+// render in place of the generic error page. The response is sent with a
+// 500 Internal Server Error status and the stack trace is still logged.
+// Returning nil falls back to the registry's error handler.
+//
+// Note: a panic during an unbuffered Render may occur after output has been
+// written; use WithBufferedRender if the fallback must replace partial output.
+//
+// Example:
+//
+//	func (c *Dashboard) Recover(ctx context.Context, recovered any) templ.Component {
+//	    return DashboardUnavailable(c.UserID)
+//	}
+type RecoverComponent interface {
+	Recover(ctx context.Context, recovered any) templ.Component
+}
+
+// recoversPanics reports whether the handler recovers panics for the named
+// component; see WithRecover.
+func (r *Registry) recoversPanics(componentName string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return !r.components[componentName].noRecover
+}
+
+// handlePanic logs and reports a panic recovered while serving componentName and
+// renders the fallback view of recoverer (if not nil and it returns one) or the
+// panic response (see SetPanicHandler). A panic in a render goroutine is reported
+// with its own value and stack.
+func (r *Registry) handlePanic(w http.ResponseWriter, req *http.Request, componentName string, recovered any, stack []byte, recoverer RecoverComponent) {
+	if rp, ok := recovered.(*renderPanic); ok {
+		recovered, stack = rp.value, rp.stack
+	}
+	r.logger().Error("panic in component handler",
+		"component", componentName,
+		"error", recovered,
+		"stack", string(stack))
+	title, message, code := r.panicResponse(recovered, req)
+	panicErr := fmt.Errorf("panic: %v", recovered)
+	r.reportError(req.Context(), componentName, "panic", panicErr, code)
+	if recoverer != nil {
+		if fallback := recoverer.Recover(req.Context(), recovered); fallback != nil {
+			r.renderFallback(w, req, fallback, componentName)
+			return
+		}
+	}
+	r.renderComponentError(w, req, componentName, panicErr, title, message, code)
+}
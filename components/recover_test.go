@@ -0,0 +1,183 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPanickingComponent panics in Process and renders a custom recovery view
+type TestPanickingComponent struct {
+	Name string `form:"name"`
+}
+
+func (c *TestPanickingComponent) Process(ctx context.Context) error {
+	panic("database connection lost")
+}
+
+func (c *TestPanickingComponent) Recover(ctx context.Context, recovered any) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, "<div class=\"fallback\">%s unavailable: %v</div>", c.Name, recovered)
+		return err
+	})
+}
+
+func (c *TestPanickingComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>%s</div>", c.Name)
+	return nil
+}
+
+// TestPlainPanickingComponent panics in Process without a recovery view
+type TestPlainPanickingComponent struct{}
+
+func (c *TestPlainPanickingComponent) Process(ctx context.Context) error {
+	panic("boom")
+}
+
+func (c *TestPlainPanickingComponent) Render(ctx context.Context, w io.Writer) error {
+	return nil
+}
+
+func TestRecoverComponent(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestPanickingComponent](registry, "panicking")
+	components.Register[*TestPlainPanickingComponent](registry, "plain")
+
+	t.Run("renders the component's recovery view", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/panicking?name=Reports", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("panicking")(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "text/html", w.Header().Get("Content-Type"))
+		assert.Equal(t, `<div class="fallback">Reports unavailable: database connection lost</div>`, w.Body.String())
+	})
+
+	t.Run("falls back to the generic error page", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/plain", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("plain")(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Component encountered an unexpected error")
+	})
+}
+
+// testHTTPError is a panic value that carries its own status code
+type testHTTPError struct {
+	Code    int
+	Message string
+}
+
+// TestHTTPErrorPanickingComponent panics with a testHTTPError
+type TestHTTPErrorPanickingComponent struct{}
+
+func (c *TestHTTPErrorPanickingComponent) Process(ctx context.Context) error {
+	panic(testHTTPError{Code: http.StatusBadRequest, Message: "missing account id"})
+}
+
+func (c *TestHTTPErrorPanickingComponent) Render(ctx context.Context, w io.Writer) error {
+	return nil
+}
+
+func TestSetPanicHandler(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestHTTPErrorPanickingComponent](registry, "http-error")
+	components.Register[*TestPlainPanickingComponent](registry, "plain")
+
+	var reported *components.ComponentError
+	registry.SetOnError(func(ctx context.Context, err *components.ComponentError) {
+		reported = err
+	})
+	registry.SetPanicHandler(func(recovered any, req *http.Request) (string, string, int) {
+		if httpErr, ok := recovered.(testHTTPError); ok {
+			return "Bad Request", httpErr.Message, httpErr.Code
+		}
+		return "Internal Server Error", "Something went wrong", http.StatusInternalServerError
+	})
+
+	t.Run("maps a known panic value to its status and message", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/http-error", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("http-error")(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "missing account id")
+		if assert.NotNil(t, reported) {
+			assert.Equal(t, http.StatusBadRequest, reported.StatusCode)
+		}
+	})
+
+	t.Run("other panics use the handler's default", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/plain", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("plain")(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Something went wrong")
+	})
+
+	t.Run("nil restores the default response", func(t *testing.T) {
+		registry.SetPanicHandler(nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/component/http-error", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("http-error")(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Component encountered an unexpected error")
+	})
+}
+
+func TestWithRecover(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestPlainPanickingComponent](registry, "propagating", components.WithRecover(false))
+	components.Register[*TestPlainPanickingComponent](registry, "recovered")
+
+	t.Run("WithRecover(false) lets the panic propagate", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/propagating", nil)
+		w := httptest.NewRecorder()
+
+		assert.PanicsWithValue(t, "boom", func() {
+			registry.HandlerFor("propagating")(w, req)
+		})
+	})
+
+	t.Run("the router's recovery sees the panic", func(t *testing.T) {
+		var recovered any
+		recoverer := func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				defer func() {
+					if recovered = recover(); recovered != nil {
+						w.WriteHeader(http.StatusTeapot)
+					}
+				}()
+				next.ServeHTTP(w, req)
+			})
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/component/propagating", nil)
+		w := httptest.NewRecorder()
+		recoverer(registry.HandlerFor("propagating")).ServeHTTP(w, req)
+
+		assert.Equal(t, "boom", recovered)
+		assert.Equal(t, http.StatusTeapot, w.Code)
+	})
+
+	t.Run("default components still recover with a 500", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/recovered", nil)
+		w := httptest.NewRecorder()
+
+		assert.NotPanics(t, func() {
+			registry.HandlerFor("recovered")(w, req)
+		})
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
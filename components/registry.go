@@ -12,75 +12,550 @@
 package components
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"html"
+	"io"
 	"log/slog"
 	"net/http"
 	"reflect"
 	"runtime/debug"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
 
 	"github.com/a-h/templ"
 	"github.com/go-playground/form/v4"
 )
 
-var defaultDecoder = form.NewDecoder()
-
-// componentEntry stores the type information for a registered component.
+// componentEntry stores the type information and options for a registered component.
 type componentEntry struct {
-	structType reflect.Type
+	structType     reflect.Type
+	rateLimiter    *rateLimiter
+	rateLimitKey   func(*http.Request) string
+	cache          *responseCache
+	etag           bool
+	bufferedRender bool
+	idempotency    *idempotencyStore
+	idemField      string
+	idemClientKey  func(*http.Request) string
+	debounce       *idempotencyStore
+	debounceKey    func(*http.Request) string
+	initialState   func() any
+	timeout        time.Duration
+	decoder        *form.Decoder
+	decoderConfig  []func(*form.Decoder)
+	capabilities   capabilitySet
+	group          *Group
+	skipFormParse  bool
+	getNoEvents    bool
+	strictFields   bool
+	sanitize       bool
+	requestLogging bool
+	noRecover      bool
+
+	// disabled is set by SetEnabled(name, false); disabled components answer 503
+	disabled bool
+
+	// validationTarget and validationSwap set HX-Retarget and HX-Reswap on strict
+	// validation failures
+	validationTarget string
+	validationSwap   string
+
+	// renderable records that Register verified the type implements templ.Component
+	renderable bool
+}
+
+// newInstance returns a new pointer to the component struct, seeded by the
+// WithInitialState factory if one was registered.
+func (e componentEntry) newInstance() reflect.Value {
+	if e.initialState != nil {
+		if seed := reflect.ValueOf(e.initialState()); seed.IsValid() && !seed.IsNil() {
+			return seed
+		}
+	}
+	return reflect.New(e.structType)
 }
 
 // ErrorHandler is a function that renders error responses
 type ErrorHandler func(w http.ResponseWriter, req *http.Request, title string, message string, code int)
 
+// ErrorContext describes a failed request for a ContextErrorHandler.
+type ErrorContext struct {
+	Title   string
+	Message string
+	Code    int
+	// ComponentName is the requested component, or empty if the name was invalid.
+	ComponentName string
+	// Err is the underlying error, if any. It is nil for errors without a cause,
+	// such as a disallowed method or an exceeded rate limit.
+	Err error
+	// IsHTMX reports whether the request was made by HTMX (HX-Request: true).
+	IsHTMX bool
+}
+
+// ContextErrorHandler is a function that renders error responses with the full
+// ErrorContext, e.g. to show the component name or render a compact fragment
+// for HTMX requests and a full page otherwise.
+type ContextErrorHandler func(w http.ResponseWriter, req *http.Request, ec ErrorContext)
+
+// withContext adapts h to a ContextErrorHandler.
+func (h ErrorHandler) withContext() ContextErrorHandler {
+	return func(w http.ResponseWriter, req *http.Request, ec ErrorContext) {
+		h(w, req, ec.Title, ec.Message, ec.Code)
+	}
+}
+
 // Registry manages component registration and handles HTTP requests for component rendering.
 // It is safe for concurrent use by multiple goroutines.
 type Registry struct {
 	mu           sync.RWMutex
 	components   map[string]componentEntry
-	errorHandler ErrorHandler
+	errorHandler ContextErrorHandler
 	debugMode    bool
+	observer     LifecycleObserver
+	log          *slog.Logger
+	csrf         *CSRFConfig
+	prefix       string
+	eventParam   string
+	eventPrefix  string
+	queryWins    bool
+	strictValid  bool
+	maxBodySize  int64
+	maxFormIndex int
+	maxFormDepth int
+	maxBatchSize int
+	stateStore   StateStore
+	flashStore   FlashStore
+	stateKey     []byte
+	stateMaxAge  time.Duration
+	extractName  func(*http.Request) string
+	foldNames    bool
+	onError      func(ctx context.Context, err *ComponentError)
+	onPanic      PanicHandler
+	wsUpgrader   WebSocketUpgrader
+	locale       LocaleConfig
+	postDecode   func(ctx context.Context, component any) error
+	decoder      *form.Decoder
+	timeFormats  []string
+	sanitizer    SanitizePolicy
+	methodField  string
+
+	bufferedRender bool
+	renderLimit    time.Duration
+	requestLogging bool
+
+	defaultRateLimiter *rateLimiter
+	defaultTimeout     time.Duration
+
+	// stats holds the *componentStats of each component, see Stats
+	stats sync.Map
 }
 
+// DefaultHandlerPrefix is the URL path prefix stripped by Handler to find the component name.
+const DefaultHandlerPrefix = "/component/"
+
+// DefaultEventParamName is the form parameter that names the event to dispatch.
+const DefaultEventParamName = "hxc-event"
+
+// DefaultEventMethodPrefix is the prefix of event handler method names, e.g. OnIncrement.
+const DefaultEventMethodPrefix = "On"
+
+// DefaultMaxBodySize is the default limit on request body size (10 MB).
+const DefaultMaxBodySize = 10 << 20
+
+// DefaultMaxFormIndex is the default limit on slice indexes in form field names.
+const DefaultMaxFormIndex = 1000
+
+// DefaultMaxFormDepth is the default limit on the nesting depth of form field names.
+const DefaultMaxFormDepth = 8
+
 // NewRegistry creates a new component registry with the default error handler.
 func NewRegistry() *Registry {
-	return &Registry{
+	r := &Registry{
 		components:   make(map[string]componentEntry),
-		errorHandler: defaultErrorHandler,
+		prefix:       DefaultHandlerPrefix,
+		eventParam:   DefaultEventParamName,
+		eventPrefix:  DefaultEventMethodPrefix,
+		maxBodySize:  DefaultMaxBodySize,
+		maxFormIndex: DefaultMaxFormIndex,
+		maxFormDepth: DefaultMaxFormDepth,
+		maxBatchSize: DefaultMaxBatchSize,
+		stateStore:   NewMemoryStateStore(),
+		flashStore:   NewMemoryFlashStore(DefaultFlashTTL),
+		stateMaxAge:  DefaultStateMaxAge,
+		decoder:      defaultDecoder,
+		timeFormats:  DefaultTimeFormats,
+		sanitizer:    StripTags,
 	}
+	r.errorHandler = r.defaultErrorHandler
+	return r
 }
 
 // SetErrorHandler sets a custom error handler for the registry.
 // The error handler is responsible for rendering error responses.
+// Use SetContextErrorHandler to also receive the component name and error.
 func (r *Registry) SetErrorHandler(handler ErrorHandler) {
+	r.errorHandler = handler.withContext()
+}
+
+// SetContextErrorHandler sets a custom error handler that receives the full
+// ErrorContext of each failed request. It replaces any handler set with
+// SetErrorHandler.
+//
+//	registry.SetContextErrorHandler(func(w http.ResponseWriter, req *http.Request, ec components.ErrorContext) {
+//	    w.WriteHeader(ec.Code)
+//	    views.ComponentError(ec.ComponentName, ec.Message, middleware.GetReqID(req.Context())).Render(req.Context(), w)
+//	})
+func (r *Registry) SetContextErrorHandler(handler ContextErrorHandler) {
 	r.errorHandler = handler
 }
 
+// SetOnError sets a hook that is called whenever a component request fails, before
+// the error response is rendered. The hook receives a *ComponentError describing the
+// component, the lifecycle operation that failed, the status code, and the underlying
+// error, which can be inspected with errors.As (e.g. for *ErrDecode field details).
+// Use it for error reporting and monitoring. Pass nil to remove the hook.
+//
+//	registry.SetOnError(func(ctx context.Context, err *components.ComponentError) {
+//	    sentry.CaptureException(err)
+//	})
+func (r *Registry) SetOnError(hook func(ctx context.Context, err *ComponentError)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onError = hook
+}
+
+// PanicHandler converts a value recovered from a panic in a component handler into
+// the title, message and HTTP status code of the error response.
+type PanicHandler func(recovered any, req *http.Request) (title, message string, code int)
+
+// SetPanicHandler sets the function that maps recovered panics to error responses,
+// so known panic values can produce a specific status and message. The stack trace
+// is logged regardless, and a component's RecoverComponent fallback still takes
+// precedence. Pass nil to restore the default: a 500 with a generic message.
+//
+//	registry.SetPanicHandler(func(recovered any, req *http.Request) (string, string, int) {
+//	    if httpErr, ok := recovered.(HTTPError); ok {
+//	        return http.StatusText(httpErr.Code), httpErr.Message, httpErr.Code
+//	    }
+//	    return "Internal Server Error", "Component encountered an unexpected error", http.StatusInternalServerError
+//	})
+func (r *Registry) SetPanicHandler(handler PanicHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onPanic = handler
+}
+
+// panicResponse returns the error response for a recovered panic, using the
+// handler set with SetPanicHandler if any.
+func (r *Registry) panicResponse(recovered any, req *http.Request) (title, message string, code int) {
+	r.mu.RLock()
+	handler := r.onPanic
+	r.mu.RUnlock()
+	if handler != nil {
+		return handler(recovered, req)
+	}
+	return "Internal Server Error", "Component encountered an unexpected error", http.StatusInternalServerError
+}
+
+// SetPostDecode sets a hook that runs for every component immediately after form
+// data is decoded, before request headers are applied and before Authorize, Init,
+// Validate, event handlers and Process. Use it to normalize or bound inputs in one
+// place (e.g. clamping a "limit" field). If the hook returns an error, the request
+// fails with a 400 Decode Error. Pass nil to remove the hook.
+//
+//	registry.SetPostDecode(func(ctx context.Context, component any) error {
+//	    if s, ok := component.(*search.SearchComponent); ok && s.Limit > 50 {
+//	        s.Limit = 50
+//	    }
+//	    return nil
+//	})
+func (r *Registry) SetPostDecode(hook func(ctx context.Context, component any) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.postDecode = hook
+}
+
+// reportError passes a failed request's error to the OnError hook, if one is set.
+func (r *Registry) reportError(ctx context.Context, componentName, operation string, err error, code int) {
+	r.mu.RLock()
+	hook := r.onError
+	_, registered := r.components[componentName]
+	r.mu.RUnlock()
+	if registered {
+		incrementCount(&r.componentStats(componentName).errors, operation)
+	}
+	if hook == nil {
+		return
+	}
+	hook(ctx, &ComponentError{
+		ComponentName: componentName,
+		Operation:     operation,
+		Err:           err,
+		StatusCode:    code,
+	})
+}
+
+// SetLogger sets the logger used for all component logging in this registry.
+// This allows component logs to be routed to a separate handler or enriched with
+// consistent attributes. By default (or when nil is passed) the registry logs
+// via slog.Default().
+//
+// Example:
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil)).With("subsystem", "components")
+//	registry.SetLogger(logger)
+func (r *Registry) SetLogger(logger *slog.Logger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.log = logger
+}
+
+// logger returns the registry's logger, falling back to slog.Default().
+func (r *Registry) logger() *slog.Logger {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.log == nil {
+		return slog.Default()
+	}
+	return r.log
+}
+
+// SetHandlerPrefix sets the URL path prefix that Handler strips to find the component
+// name (default "/component/"). Everything after the prefix is used as the name, so
+// "/component/admin/users" routes to the component registered as "admin/users".
+// Paths outside the prefix fall back to the last path segment.
+func (r *Registry) SetHandlerPrefix(prefix string) {
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prefix = prefix
+}
+
+// SetNameExtractor sets the function Handler uses to resolve the component name
+// from a request, e.g. to read a router's route variable instead of the URL path.
+// Pass nil to restore the default path-based extraction (see Handler).
+//
+//	router.Get("/component/{component}", registry.Handler)
+//	registry.SetNameExtractor(func(req *http.Request) string {
+//	    return chi.URLParam(req, "component")
+//	})
+func (r *Registry) SetNameExtractor(extract func(*http.Request) string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.extractName = extract
+}
+
+// componentNameFor resolves the component name for a request handled by Handler.
+func (r *Registry) componentNameFor(req *http.Request) string {
+	r.mu.RLock()
+	extract, prefix := r.extractName, r.prefix
+	r.mu.RUnlock()
+	if extract != nil {
+		return extract(req)
+	}
+	return componentNameFromPath(req.URL.Path, prefix)
+}
+
+// SetCaseInsensitiveNames controls whether Handler and PathValueHandler resolve
+// component names case-insensitively, so "/component/Search" serves the component
+// registered as "search". An exact match always wins. Names are matched exactly by
+// default. Enabling it panics if two registered names differ only by case, and
+// while it is enabled such names are rejected at registration.
+func (r *Registry) SetCaseInsensitiveNames(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if enabled {
+		seen := make(map[string]string, len(r.components))
+		for name := range r.components {
+			if other, ok := seen[strings.ToLower(name)]; ok {
+				panic(fmt.Sprintf("component names '%s' and '%s' differ only by case", other, name))
+			}
+			seen[strings.ToLower(name)] = name
+		}
+	}
+	r.foldNames = enabled
+}
+
+// resolveName returns the registered name matching a name taken from a request:
+// the name itself, or with SetCaseInsensitiveNames the registered name equal to it
+// ignoring case. Unknown names are returned unchanged.
+func (r *Registry) resolveName(name string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, ok := r.components[name]; ok || !r.foldNames {
+		return name
+	}
+	for registered := range r.components {
+		if strings.EqualFold(registered, name) {
+			return registered
+		}
+	}
+	return name
+}
+
+// SetEventParamName sets the form parameter used to name the event to dispatch
+// (default "hxc-event"), e.g. to avoid a conflict with an existing field or to use
+// a namespaced key. It panics if name is empty.
+func (r *Registry) SetEventParamName(name string) {
+	if name == "" {
+		panic("event parameter name cannot be empty")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.eventParam = name
+}
+
+// SetEventMethodPrefix sets the prefix of event handler method names (default
+// "On"), so with "Handle" the "increment" event calls HandleIncrement. It applies
+// to event dispatch, HandlerForEvent, ComponentInfo.Events and
+// Registry.SimulateEvent. It panics if prefix does not start with an upper-case
+// letter (event methods must be exported) or is not a valid Go identifier.
+func (r *Registry) SetEventMethodPrefix(prefix string) {
+	if !isExportedIdentifier(prefix) {
+		panic(fmt.Sprintf("event method prefix %q must be an exported Go identifier", prefix))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.eventPrefix = prefix
+	// Event handlers are found by prefix, so recompute CapabilityEvents
+	for name, entry := range r.components {
+		entry.capabilities = capabilitiesOf(entry.structType, prefix)
+		r.components[name] = entry
+	}
+}
+
+// eventMethodPrefix returns the prefix of event handler method names.
+func (r *Registry) eventMethodPrefix() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.eventPrefix
+}
+
+// isExportedIdentifier reports whether s is a Go identifier starting with an
+// upper-case letter.
+func isExportedIdentifier(s string) bool {
+	for i, c := range s {
+		switch {
+		case i == 0 && !unicode.IsUpper(c):
+			return false
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_':
+			return false
+		}
+	}
+	return s != ""
+}
+
+// SetMaxBodySize limits the size of request bodies to n bytes (default 10 MB).
+// Requests with larger bodies are rejected with 413 Request Entity Too Large.
+// The limit applies to the body for the whole request, including multipart or
+// JSON bodies read by components through RequestAware. Pass n <= 0 to remove it.
+func (r *Registry) SetMaxBodySize(n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxBodySize = n
+}
+
+// SetMaxFormIndex limits the slice index accepted in form field names such as
+// "items[5]" (default 1000). Decoding a huge index allocates a slice of that size,
+// so requests with a larger index are rejected with 400 Bad Request before
+// decoding. Pass n <= 0 to remove the limit.
+func (r *Registry) SetMaxFormIndex(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxFormIndex = n
+}
+
+// SetMaxFormDepth limits how deeply form field names may nest, counting each
+// "[...]" or "." level, e.g. "order.items[0].name" has depth 3 (default 8).
+// Requests with deeper names are rejected with 400 Bad Request before decoding.
+// Pass n <= 0 to remove the limit.
+func (r *Registry) SetMaxFormDepth(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxFormDepth = n
+}
+
+// SetQueryPrecedence controls which value is decoded when a POST request sends the
+// same field in both the body and the query string. By default the body wins;
+// pass true to let query parameters win instead. Fields sent in only one place
+// are always decoded.
+func (r *Registry) SetQueryPrecedence(queryWins bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queryWins = queryWins
+}
+
+// SetStrictValidation controls whether validation errors stop the request. By
+// default they are only stored on the component and processing continues. When
+// strict, a failed Validate skips events and Process and responds with a 422
+// Unprocessable Entity, rendering the component's ValidationErrorRenderer view if
+// it has one and the error handler otherwise.
+func (r *Registry) SetStrictValidation(strict bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.strictValid = strict
+}
+
+// SetBufferedRender enables or disables buffered rendering for all components.
+// When enabled, components are rendered into a buffer and only written to the
+// response once Render succeeds, so a failing Render results in a clean error
+// response instead of partial HTML. Unbuffered streaming is the default, which
+// keeps memory use low for large responses. See also WithBufferedRender.
+func (r *Registry) SetBufferedRender(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bufferedRender = enabled
+}
+
+// SetLifecycleObserver sets an observer that is notified around each lifecycle phase
+// (decode, init, validate, event, process, render) of every component request.
+// Pass nil to remove a previously set observer.
+func (r *Registry) SetLifecycleObserver(obs LifecycleObserver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observer = obs
+}
+
 // EnableDebugMode enables debug mode for the registry.
 // When enabled, additional debugging headers are added to responses:
 //   - X-HxComponent-Name: The component name
 //   - X-HxComponent-FormFields: Number of form fields received
 //   - X-HxComponent-HasEvent: Whether an event was processed
+//   - X-HxComponent-Event: The event name(s), when an event was processed
+//   - X-HxComponent-Fields: Sorted, comma-separated names (not values) of the form fields received
+//   - X-HxComponent-BodySize: Number of request body bytes read
+//   - X-HxComponent-ContentType: The request's Content-Type
+//
+// Requests with ?__trace=1 also receive a JSON ComponentTrace of the lifecycle
+// phases instead of the rendered component.
 //
 // This is useful during development to understand component rendering.
 // WARNING: Do not enable in production as it exposes internal details.
 func (r *Registry) EnableDebugMode() {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 	r.debugMode = true
-	slog.Info("debug mode enabled for component registry")
+	r.mu.Unlock()
+	r.logger().Info("debug mode enabled for component registry")
 }
 
 // DisableDebugMode disables debug mode for the registry.
 func (r *Registry) DisableDebugMode() {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 	r.debugMode = false
-	slog.Info("debug mode disabled for component registry")
+	r.mu.Unlock()
+	r.logger().Info("debug mode disabled for component registry")
 }
 
 // IsDebugMode returns whether debug mode is currently enabled.
@@ -90,12 +565,15 @@ func (r *Registry) IsDebugMode() bool {
 	return r.debugMode
 }
 
-// defaultErrorHandler is the default error handler that renders the ErrorComponent
-func defaultErrorHandler(w http.ResponseWriter, req *http.Request, title string, message string, code int) {
+// defaultErrorHandler is the default error handler that renders the ErrorComponent.
+// For HTMX requests it also sets an HX-Trigger header firing ErrorTriggerEvent.
+func (r *Registry) defaultErrorHandler(w http.ResponseWriter, req *http.Request, ec ErrorContext) {
+	title, message, code := ec.Title, ec.Message, ec.Code
+	TriggerError(w, ec)
 	w.Header().Set("Content-Type", "text/html")
 	w.WriteHeader(code)
 	if err := ErrorComponent(title, message, code).Render(req.Context(), w); err != nil {
-		slog.Error("failed to render error component",
+		r.logger().Error("failed to render error component",
 			"error", err,
 			"title", title,
 			"message", message,
@@ -125,9 +603,12 @@ func defaultErrorHandler(w http.ResponseWriter, req *http.Request, title string,
 // will be called after form decoding and before rendering, allowing you to perform
 // validation, business logic, or set response headers.
 //
+// Optional RegisterOption values configure per-component behavior such as rate limiting.
+//
 // Example:
 //
 //	components.Register[*login.LoginComponent](registry, "login")
+//	components.Register[*search.SearchComponent](registry, "search", components.WithRateLimit(5, 10))
 //
 // Why is this a package-level function instead of a method on Registry?
 //
@@ -142,7 +623,7 @@ func defaultErrorHandler(w http.ResponseWriter, req *http.Request, title string,
 //
 // The package-level generic function is the idiomatic Go approach for this pattern.
 // See: https://go.googlesource.com/proposal/+/refs/heads/master/design/43651-type-parameters.md
-func Register[T templ.Component](r *Registry, name string) {
+func Register[T templ.Component](r *Registry, name string, opts ...RegisterOption) {
 	// Validate component name
 	if name == "" {
 		panic("component name cannot be empty")
@@ -187,19 +668,86 @@ func Register[T templ.Component](r *Registry, name string) {
 			zero, name, structName))
 	}
 
-	// Thread-safe registration
+	r.register(name, componentEntry{
+		structType: structType.Elem(),
+		renderable: true,
+	}, opts)
+}
+
+// register stores a validated component entry under name after applying opts.
+// It panics if the component can't be added; see addComponents.
+func (r *Registry) register(name string, entry componentEntry, opts []RegisterOption) {
+	if _, err := r.addComponents([]pendingComponent{{name: name, entry: entry, opts: opts}}); err != nil {
+		panic(err.Error())
+	}
+}
+
+// pendingComponent is a component to be added by addComponents.
+type pendingComponent struct {
+	name  string
+	entry componentEntry
+	opts  []RegisterOption
+}
+
+// addComponents validates each pending component and adds them all, or none if
+// any is invalid, in which case failed is the index of the first invalid one.
+// Validation and insertion happen under a single lock, so concurrent
+// registrations cannot slip in between.
+func (r *Registry) addComponents(pending []pendingComponent) (failed int, err error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	added := make(map[string]componentEntry, len(pending))
+	for i, c := range pending {
+		entry, err := r.checkComponentLocked(c, added)
+		if err != nil {
+			return i, err
+		}
+		added[c.name] = entry
+	}
+	for name, entry := range added {
+		r.components[name] = entry
+	}
+	return 0, nil
+}
+
+// checkComponentLocked returns the entry for a pending component with its
+// options applied, or an error if its name is taken (by a registered component or
+// one in added), its `default` tags are malformed or its options can't be
+// combined. r.mu must be held.
+func (r *Registry) checkComponentLocked(c pendingComponent, added map[string]componentEntry) (componentEntry, error) {
+	name, entry := c.name, c.entry
+
 	// Check for duplicate registration
-	if _, exists := r.components[name]; exists {
-		panic(fmt.Sprintf("component '%s' already registered", name))
+	for _, components := range []map[string]componentEntry{r.components, added} {
+		if _, exists := components[name]; exists {
+			return entry, fmt.Errorf("component '%s' already registered", name)
+		}
+		if r.foldNames {
+			for registered := range components {
+				if strings.EqualFold(registered, name) {
+					return entry, fmt.Errorf("component '%s' differs only by case from registered component '%s'", name, registered)
+				}
+			}
+		}
+	}
+
+	// Catch malformed `default` tags at startup rather than on the first request
+	if entry.structType.Kind() == reflect.Struct {
+		if err := applyDefaults(reflect.New(entry.structType).Interface()); err != nil {
+			return entry, fmt.Errorf("component '%s': %v", name, err)
+		}
 	}
 
-	structType = structType.Elem()
-	r.components[name] = componentEntry{
-		structType: structType,
+	for _, opt := range c.opts {
+		opt(&entry)
+	}
+	r.buildDecoderLocked(&entry)
+	entry.capabilities = capabilitiesOf(entry.structType, r.eventPrefix)
+	if err := checkChunked(name, entry); err != nil {
+		return entry, err
 	}
+	return entry, nil
 }
 
 // HandlerFor returns an http.HandlerFunc for rendering a specific component.
@@ -294,244 +842,778 @@ func Register[T templ.Component](r *Registry, name string) {
 //
 //	router.HandleFunc("/search", registry.HandlerFor("search"))
 func (r *Registry) HandlerFor(componentName string) http.HandlerFunc {
+	return r.handlerFor(componentName, "")
+}
+
+// HandlerForEvent returns an http.HandlerFunc that behaves like HandlerFor but always
+// dispatches the given event, ignoring any event parameter in the form data. This allows stable
+// URLs per event instead of carrying the event name in the request:
+//
+//	router.Post("/counter/increment", registry.HandlerForEvent("counter", "increment"))
+//
+// It panics if the component is not registered or has no On{Event} method, so
+// misconfigured routes fail at startup.
+func (r *Registry) HandlerForEvent(componentName, eventName string) http.HandlerFunc {
+	r.mu.RLock()
+	entry, exists := r.components[componentName]
+	r.mu.RUnlock()
+
+	if !exists {
+		panic((&ErrComponentNotFound{ComponentName: componentName}).Error())
+	}
+	if _, ok := reflect.PointerTo(entry.structType).MethodByName(r.eventMethodPrefix() + capitalize(eventName)); !ok {
+		panic((&ErrEventNotFound{ComponentName: componentName, EventName: eventName}).Error())
+	}
+
+	return r.handlerFor(componentName, eventName)
+}
+
+// handlerFor builds the component handler, wrapped in the middleware of the group
+// the component was registered in (if any). If fixedEvent is not empty it is
+// dispatched instead of the event parameter in the form data.
+func (r *Registry) handlerFor(componentName, fixedEvent string) http.HandlerFunc {
+	return r.groupMiddleware(componentName, r.componentHandler(componentName, fixedEvent))
+}
+
+// componentHandler runs the component request lifecycle; see handlerFor.
+func (r *Registry) componentHandler(componentName, fixedEvent string) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		// Panic recovery
+		logger := r.logger()
+
+		// With request logging, the summary line is written once the response
+		// is complete, including responses rendered after a panic
+		var summary *requestSummary
+		if r.requestLoggingFor(componentName) {
+			summary = &requestSummary{ResponseWriter: w, start: time.Now(), method: req.Method, event: fixedEvent}
+			w = summary
+			defer summary.log(logger, componentName)
+		}
+
+		// Set once the component is created, so a panic can render its fallback view
+		var recoverer RecoverComponent
+
+		// Panic recovery, unless the component was registered with WithRecover(false)
+		recoverPanics := r.recoversPanics(componentName)
 		defer func() {
+			if !recoverPanics {
+				return
+			}
 			if err := recover(); err != nil {
-				slog.Error("panic in component handler",
-					"component", componentName,
-					"error", err,
-					"stack", string(debug.Stack()))
-				r.renderError(w, req, "Internal Server Error",
-					"Component encountered an unexpected error",
-					http.StatusInternalServerError)
+				r.handlePanic(w, req, componentName, err, debug.Stack(), recoverer)
 			}
 		}()
 
-		if req.Method != http.MethodPost && req.Method != http.MethodGet {
-			slog.Warn("method not allowed",
+		if req.Method != http.MethodPost && req.Method != http.MethodGet && req.Method != http.MethodHead {
+			logger.Warn("method not allowed",
 				"method", req.Method,
 				"path", req.URL.Path,
 				"component", componentName)
-			r.renderError(w, req, "Method Not Allowed", fmt.Sprintf("Method %s is not allowed", req.Method), http.StatusMethodNotAllowed)
+			r.renderComponentError(w, req, componentName, nil, "Method Not Allowed", fmt.Sprintf("Method %s is not allowed", req.Method), http.StatusMethodNotAllowed)
 			return
 		}
 
 		// Thread-safe component lookup
 		r.mu.RLock()
 		entry, exists := r.components[componentName]
+		observer := r.observer
+		decoding := r.decodeSettingsLocked()
+		timeout := r.timeoutFor(entry)
+		queryWins := r.queryWins
+		strictValidation := r.strictValid
+		maxBodySize := r.maxBodySize
+		stateStore := r.stateStore
+		bufferedRender := r.bufferedRender || entry.bufferedRender
+		debugMode := r.debugMode
+		localeConfig := r.locale
+		renderLimit := r.renderLimit
+		methodField := r.methodField
 		r.mu.RUnlock()
 
+		if summary != nil {
+			observer = summary.observe(observer)
+		}
+
 		if !exists {
-			slog.Warn("component not found",
+			logger.Warn("component not found",
 				"component", componentName,
 				"path", req.URL.Path)
-			r.renderError(w, req, "Component Not Found", fmt.Sprintf("Component '%s' not found", componentName), http.StatusNotFound)
+			r.renderComponentError(w, req, componentName, &ErrComponentNotFound{ComponentName: componentName}, "Component Not Found", fmt.Sprintf("Component '%s' not found", componentName), http.StatusNotFound)
+			return
+		}
+		counters := r.componentStats(componentName)
+		counters.requests.Add(1)
+
+		if entry.disabled {
+			logger.Debug("component disabled",
+				"component", componentName)
+			err := &ErrComponentDisabled{ComponentName: componentName}
+			r.renderComponentError(w, req, componentName, err, "Service Unavailable", capitalize(err.Error()), http.StatusServiceUnavailable)
+			return
+		}
+
+		// In debug mode, ?__trace=1 returns the lifecycle trace instead of the component
+		var tracer *traceObserver
+		if debugMode && traceRequested(req) {
+			tracer = newTraceObserver(observer)
+			observer = tracer
+		}
+
+		// Enforce the component's rate limit (or the registry default)
+		if allowed, wait := r.checkRateLimit(entry, componentName, req); !allowed {
+			logger.Warn("rate limit exceeded",
+				"component", componentName,
+				"remote_addr", req.RemoteAddr)
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(wait)))
+			r.renderComponentError(w, req, componentName, nil, "Too Many Requests", "Rate limit exceeded, please try again later", http.StatusTooManyRequests)
 			return
 		}
 
-		slog.Debug("rendering component",
+		// Bound the lifecycle with the component's timeout (or the registry default).
+		// The context is cancelled once the response has been rendered.
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(req.Context(), timeout)
+			defer cancel()
+			req = req.WithContext(ctx)
+		}
+
+		logger.Debug("rendering component",
 			"component", componentName,
 			"method", req.Method,
 			"remote_addr", req.RemoteAddr,
 			"user_agent", req.UserAgent(),
 			"content_type", req.Header.Get("Content-Type"))
 
-		if err := req.ParseForm(); err != nil {
-			slog.Error("form parse error",
+		// Cap the body before anything reads it
+		if maxBodySize > 0 && req.Body != nil {
+			req.Body = http.MaxBytesReader(w, req.Body, maxBodySize)
+		}
+		var body *countingReader
+		if debugMode && req.Body != nil {
+			body = &countingReader{ReadCloser: req.Body}
+			req.Body = body
+		}
+
+		var parseErr error
+		if entry.parseForm() {
+			parseErr = req.ParseForm()
+		}
+		if err := parseErr; err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				logger.Warn("request body too large",
+					"component", componentName,
+					"limit", tooLarge.Limit)
+				r.reportError(req.Context(), componentName, "parse", err, http.StatusRequestEntityTooLarge)
+				r.renderComponentError(w, req, componentName, err, "Request Entity Too Large", fmt.Sprintf("Request body exceeds the %d byte limit", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+				return
+			}
+			logger.Error("form parse error",
+				"component", componentName,
+				"error", err)
+			r.reportError(req.Context(), componentName, "parse", err, http.StatusBadRequest)
+			r.renderComponentError(w, req, componentName, err, "Bad Request", fmt.Sprintf("Failed to parse form data: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		// Reject state-changing requests without a valid CSRF token (if enabled)
+		if err := r.checkCSRF(req, entry.parseForm()); err != nil {
+			logger.Warn("CSRF validation failed",
 				"component", componentName,
+				"method", req.Method,
+				"remote_addr", req.RemoteAddr,
 				"error", err)
-			r.renderError(w, req, "Bad Request", fmt.Sprintf("Failed to parse form data: %v", err), http.StatusBadRequest)
+			r.reportError(req.Context(), componentName, "csrf", err, http.StatusForbidden)
+			r.renderComponentError(w, req, componentName, err, "Forbidden", err.Error(), http.StatusForbidden)
 			return
 		}
 
 		// Create instance and decode form
-		instance := reflect.New(entry.structType)
+		instance := entry.newInstance()
+		recoverer, _ = instance.Interface().(RecoverComponent)
 
-		// For POST, use PostForm; for GET, use Form (which includes query params)
+		// For POST, merge the body and query parameters (body wins unless configured
+		// otherwise); for GET and HEAD, use Form (which includes query params).
+		// Components registered WithoutFormParsing only see the query string.
 		var formData map[string][]string
-		if req.Method == http.MethodPost {
-			formData = req.PostForm
-		} else {
+		switch {
+		case !entry.parseForm():
+			formData = req.URL.Query()
+		case req.Method == http.MethodPost:
+			formData = mergeFormValues(req.PostForm, req.URL.Query(), queryWins)
+		default:
 			formData = req.Form
 		}
 
-		// Use component's custom decoder if provided, otherwise use default
-		decoder := defaultDecoder
-		if customDecoder, ok := instance.Interface().(FormDecoder); ok {
-			decoder = customDecoder.GetFormDecoder()
-			slog.Debug("using custom form decoder",
+		// Treat a POST as the PUT, PATCH or DELETE it asks for (if enabled). The body
+		// has been read as a POST's above; from here on the overridden method is
+		// what the component and the request log see.
+		if method, status := overriddenMethod(req, methodField); status != 0 {
+			logger.Warn("method override not allowed",
+				"method", method,
+				"path", req.URL.Path,
+				"component", componentName)
+			if status == http.StatusBadRequest {
+				r.renderComponentError(w, req, componentName, nil, "Bad Request", fmt.Sprintf("Cannot override POST to %s; send a %s request instead", method, method), status)
+				return
+			}
+			r.renderComponentError(w, req, componentName, nil, "Method Not Allowed", fmt.Sprintf("Method %s is not allowed", method), status)
+			return
+		} else if method != "" {
+			req = req.Clone(req.Context())
+			req.Method = method
+			if summary != nil {
+				summary.method = method
+			}
+		}
+
+		// Events are requested via the event parameter (hxc-event by default)
+		eventNames := formData[decoding.eventParam]
+		if fixedEvent != "" {
+			eventNames = []string{fixedEvent}
+		}
+		if entry.getNoEvents && (req.Method == http.MethodGet || req.Method == http.MethodHead) {
+			eventNames = nil
+		}
+		hasEvent := len(eventNames) > 0
+		if summary != nil && hasEvent {
+			summary.event = eventNames[0]
+		}
+
+		// Expose the request details to lifecycle methods via RequestInfoFromContext,
+		// the registry via RendererFromContext for embedding other components, and
+		// the preferred language via LocaleFromContext
+		info := RequestInfo{ComponentName: componentName, Method: req.Method}
+		if hasEvent {
+			info.EventName = eventNames[0]
+		}
+		locale := resolveLocale(req, localeConfig)
+		req = req.WithContext(withLocale(withRenderer(withRequestInfo(req.Context(), info), r), locale))
+
+		// Use component's custom decoder if provided, then the one configured at
+		// registration, otherwise the default
+		if entry.formDecoder(instance.Interface(), decoding.decoder) != decoding.decoder {
+			logger.Debug("using custom form decoder",
 				"component", componentName)
 		}
 
-		if err := decoder.Decode(instance.Interface(), formData); err != nil {
-			slog.Error("form decode error",
+		var err error
+		if entry.parseForm() {
+			err = observePhase(req.Context(), observer, componentName, PhaseDecode, func() error {
+				return r.decodeComponent(req.Context(), entry, instance.Interface(), formData, decoding, true)
+			})
+		}
+		if err != nil {
+			logger.Error("form decode error",
 				"component", componentName,
 				"error", err)
-			r.renderError(w, req, "Decode Error", fmt.Sprintf("Failed to decode form data: %v", err), http.StatusBadRequest)
+			r.reportError(req.Context(), componentName, "decode", err, http.StatusBadRequest)
+			r.renderComponentError(w, req, componentName, err, "Decode Error", capitalize(err.Error()), http.StatusBadRequest)
 			return
 		}
 
 		// Apply request headers
 		applyHxHeaders(instance.Interface(), req)
+		if v, ok := instance.Interface().(LocaleAware); ok {
+			v.SetLocale(locale)
+		}
 
-		// Initialize component if it implements Initializer interface
-		if initializer, ok := instance.Interface().(Initializer); ok {
-			if err := initializer.Init(req.Context()); err != nil {
-				slog.Error("component init error",
+		// Authorize the request if the component implements Authorizer interface
+		if authorizer, ok := instance.Interface().(Authorizer); ok {
+			if err := authorizer.Authorize(req.Context()); err != nil {
+				logger.Warn("component authorization denied",
 					"component", componentName,
+					"remote_addr", req.RemoteAddr,
 					"error", err)
-				r.renderError(w, req, "Initialization Error", fmt.Sprintf("Component initialization failed: %v", err), http.StatusInternalServerError)
+				title, message, code := authorizeFailure(err)
+				r.reportError(req.Context(), componentName, "authorize", err, code)
+				r.renderComponentError(w, req, componentName, err, title, message, code)
 				return
 			}
 		}
 
-		// Validate if component implements Validator interface
-		if validator, ok := instance.Interface().(Validator); ok {
-			if errs := validator.Validate(req.Context()); len(errs) > 0 {
-				slog.Debug("validation errors",
-					"component", componentName,
-					"errors", errs)
-				// Validation errors don't stop processing - they're stored in the component
-				// and can be rendered in the template. Components can choose to handle
-				// validation errors differently by checking in their Process() method.
+		// Hand a pending flash message to a FlashReceiver
+		r.deliverFlash(w, req, instance.Interface())
+
+		// Replay the stored response for a duplicate idempotency key, or for a
+		// repeated event within the debounce window (if enabled)
+		var idemStore *idempotencyStore
+		var idemKey string
+		var idemResp *idempotentResponse
+		if entry.idempotency != nil && !isSafeMethod(req.Method) && hasEvent {
+			idemStore, idemKey = entry.idempotency, idempotencyKey(req, entry.idemField, entry.idemClientKey)
+		}
+		if idemKey == "" && entry.debounce != nil && hasEvent {
+			idemStore, idemKey = entry.debounce, debounceKey(req, eventNames[0], formData, entry.debounceKey, r.frameworkFields(decoding.eventParam)...)
+		}
+		if idemKey != "" {
+			resp, claimed := idemStore.claim(idemKey, time.Now())
+			if !claimed {
+				select {
+				case <-resp.done:
+				case <-req.Context().Done():
+					return
+				}
+				if resp.ok {
+					logger.Debug("replaying stored event response",
+						"component", componentName,
+						"debounced", idemStore == entry.debounce)
+					for name, values := range resp.headers {
+						w.Header()[name] = append([]string(nil), values...)
+					}
+					w.WriteHeader(resp.status)
+					if _, err := w.Write(resp.body); err != nil {
+						logger.Error("failed to write idempotent response",
+							"component", componentName,
+							"error", err)
+					}
+					return
+				}
+				// The first request failed, so handle this one normally
+				idemKey = ""
+			} else {
+				idemResp = resp
+				defer func() {
+					// Release the key if the request failed before its response was stored
+					if !idemResp.ok {
+						idemStore.release(idemKey, idemResp)
+					}
+				}()
 			}
 		}
 
-		// Handle event-driven processing if hxc-event parameter is present
-		hasEvent := false
-		if eventNames, ok := formData["hxc-event"]; ok && len(eventNames) > 0 {
-			hasEvent = true
-			eventName := eventNames[0]
-			slog.Debug("processing event",
-				"component", componentName,
-				"event", eventName)
-			if err := r.handleEvent(req.Context(), instance.Interface(), eventName, componentName); err != nil {
-				slog.Error("event handler error",
-					"component", componentName,
-					"event", eventName,
-					"error", err,
-					"remote_addr", req.RemoteAddr)
-				r.renderError(w, req, "Event Error", fmt.Sprintf("Event '%s' failed: %v", eventName, err), http.StatusInternalServerError)
+		// Serve read-only requests from the response cache (if enabled)
+		cacheable := entry.cache != nil && req.Method == http.MethodGet && !hasEvent && tracer == nil
+		var cacheKey string
+		if cacheable {
+			cacheKey = entry.cache.keyFunc(req)
+			if variant := cacheVariant(req, instance.Interface(), locale); variant != "" {
+				cacheKey += "\n" + variant
+			}
+			if cached, ok := entry.cache.get(cacheKey, time.Now()); ok {
+				logger.Debug("serving component from cache",
+					"component", componentName)
+				w.Header().Set("Content-Type", "text/html")
+				for name, values := range cached.headers {
+					w.Header()[name] = append([]string(nil), values...)
+				}
+				if err := writeBufferedBody(w, req, cached.body, entry.etag); err != nil {
+					logger.Error("failed to write cached response",
+						"component", componentName,
+						"error", err)
+				}
 				return
 			}
 		}
 
-		// Call Process if the component implements the Processor interface
-		if processor, ok := instance.Interface().(Processor); ok {
-			if err := processor.Process(req.Context()); err != nil {
-				slog.Error("component process error",
+		// Load server-side state for a StatefulComponent and verify client-held
+		// state for a SignedStateComponent
+		if err := r.loadComponentState(req.Context(), stateStore, componentName, instance.Interface(), formData); err != nil {
+			r.renderStateError(w, req, componentName, err)
+			return
+		}
+
+		// Run Init, Validate, the event (if any) and Process. An event handler may
+		// return a replacement component to render instead.
+		var eventName string
+		if hasEvent {
+			eventName = eventNames[0]
+		}
+		lifecycle, err := r.runLifecycle(req.Context(), instance.Interface(), lifecycleOptions{
+			componentName:    componentName,
+			eventName:        eventName,
+			observer:         observer,
+			strictValidation: strictValidation,
+			afterEvent: func() {
+				// Events mutate state, so previously cached renders are stale
+				if entry.cache != nil {
+					entry.cache.clear()
+				}
+			},
+		})
+		var lifecycleErr *ComponentError
+		if errors.As(err, &lifecycleErr) {
+			operation, cause := lifecycleErr.Operation, lifecycleErr.Err
+			switch {
+			case operation == "validate":
+				// Validation errors only stop the request with strict validation;
+				// otherwise they're stored in the component for its template
+				r.renderValidationErrors(w, req, entry, instance.Interface(), componentName, lifecycle.validationErrors)
+			case deadlineExceeded(req.Context()):
+				r.renderTimeout(w, req, componentName, operation)
+			case operation == "init" && errors.Is(cause, ErrRedirect):
+				logger.Debug("component init requested a redirect",
+					"component", componentName)
+				applyHxResponseHeaders(w, instance.Interface())
+				r.setFlash(w, req, instance.Interface(), componentName)
+				w.WriteHeader(http.StatusOK)
+			case operation == "event" && errors.Is(cause, context.Canceled):
+				r.renderCanceled(w, req, componentName, operation, cause)
+			default:
+				logger.Error("component "+operation+" error",
 					"component", componentName,
-					"error", err)
-				r.renderError(w, req, "Processing Error", fmt.Sprintf("Component processing failed: %v", err), http.StatusInternalServerError)
-				return
+					"event", eventName,
+					"error", cause,
+					"remote_addr", req.RemoteAddr)
+				title, message := lifecycleFailure(operation, eventName, cause)
+				r.reportError(req.Context(), componentName, operation, cause, http.StatusInternalServerError)
+				r.renderComponentError(w, req, componentName, cause, title, message, http.StatusInternalServerError)
 			}
+			return
 		}
+		replacement, eventResult := lifecycle.replacement, lifecycle.eventResult
 
-		// Apply response headers (after processing, so we capture any changes made during Process)
-		applyHxResponseHeaders(w, instance.Interface())
+		// Don't render results computed after the timeout expired
+		if deadlineExceeded(req.Context()) {
+			r.renderTimeout(w, req, componentName, "process")
+			return
+		}
+
+		// Save server-side state and sign client-held state now that events and
+		// Process have succeeded
+		if err := r.saveComponentState(req.Context(), stateStore, componentName, instance.Interface()); err != nil {
+			r.renderStateError(w, req, componentName, err)
+			return
+		}
 
-		// Add debug headers if debug mode is enabled
-		if r.IsDebugMode() {
+		// Apply response headers (after processing, so we capture any changes made during Process).
+		// An EventResult returned by the event handler overrides them.
+		applyHxResponseHeaders(w, instance.Interface())
+		eventResult.applyHeaders(w.Header())
+		r.setFlash(w, req, instance.Interface(), componentName)
+
+		// Add debug headers if debug mode is enabled. Only field names are reported,
+		// never their values, which may be secrets such as passwords.
+		if debugMode {
+			fieldNames := sortedKeys(formData)
+			logger.Debug("received form fields",
+				"component", componentName,
+				"fields", fieldNames)
 			w.Header().Set("X-HxComponent-Name", componentName)
 			w.Header().Set("X-HxComponent-FormFields", fmt.Sprintf("%d", len(req.Form)))
+			w.Header().Set("X-HxComponent-Fields", strings.Join(fieldNames, ","))
+			w.Header().Set("X-HxComponent-ContentType", req.Header.Get("Content-Type"))
+			var bodySize int64
+			if body != nil {
+				bodySize = body.n
+			}
+			w.Header().Set("X-HxComponent-BodySize", fmt.Sprintf("%d", bodySize))
 			if hasEvent {
 				w.Header().Set("X-HxComponent-HasEvent", "true")
+				w.Header().Set("X-HxComponent-Event", strings.Join(eventNames, ","))
 			} else {
 				w.Header().Set("X-HxComponent-HasEvent", "false")
 			}
 		}
 
-		// Render component - the instance itself implements templ.Component
-		w.Header().Set("Content-Type", "text/html")
-		component, ok := instance.Interface().(templ.Component)
-		if !ok {
-			slog.Error("component does not implement templ.Component",
+		// Stream Server-Sent Events instead of rendering if requested and supported
+		if streamer, ok := instance.Interface().(StreamComponent); ok && acceptsEventStream(req) && req.Method != http.MethodHead {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Connection", "keep-alive")
+			w.WriteHeader(http.StatusOK)
+			flush := flusherFor(w)
+			flush()
+
+			logger.Debug("streaming component",
+				"component", componentName)
+			if err := streamer.Stream(req.Context(), w, flush); err != nil && req.Context().Err() == nil {
+				// Headers are already sent, so the error can only be logged
+				logger.Error("component stream error",
+					"component", componentName,
+					"error", err)
+			}
+			return
+		}
+
+		// Render component - the instance itself implements templ.Component,
+		// which Register has already verified
+		w.Header().Set("Content-Type", contentTypeOf(instance.Interface()))
+		var component templ.Component
+		if entry.renderable {
+			component, _ = instance.Interface().(templ.Component)
+		}
+		if component == nil {
+			err := &ErrNotRenderable{ComponentName: componentName, Type: instance.Type().String()}
+			logger.Error("component is not renderable",
+				"component", componentName,
+				"error", err)
+			r.reportError(req.Context(), componentName, "render", err, http.StatusInternalServerError)
+			r.renderComponentError(w, req, componentName, err, "Configuration Error", err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if replacement != nil {
+			logger.Debug("rendering replacement component from event handler",
 				"component", componentName)
-			r.renderError(w, req, "Configuration Error", "Component does not implement templ.Component", http.StatusInternalServerError)
+			component = replacement
+		}
+
+		if eventResult != nil && eventResult.StatusCode != 0 {
+			w = &statusCodeWriter{ResponseWriter: w, code: eventResult.StatusCode}
+		}
+
+		// Chunked renderers stream their output progressively instead
+		if chunked, ok := instance.Interface().(ChunkedRenderer); ok && replacement == nil && req.Method != http.MethodHead && tracer == nil && idemResp == nil {
+			r.renderChunked(w, req, chunked, componentName, observer)
 			return
 		}
 
-		if err := component.Render(req.Context(), w); err != nil {
-			slog.Error("component render error",
+		// Buffered, cacheable and ETag responses are rendered into a buffer so they
+		// can be rolled back, stored or hashed before being written. HEAD responses
+		// are buffered to report the Content-Length of the body they omit.
+		var out io.Writer = w
+		var buf *bytes.Buffer
+		if bufferedRender || cacheable || entry.etag || idemResp != nil || tracer != nil || req.Method == http.MethodHead || renderLimit > 0 {
+			buf = new(bytes.Buffer)
+			out = buf
+		}
+		if flushable, ok := instance.Interface().(Flushable); ok {
+			if buf != nil {
+				flushable.SetFlush(noFlush)
+			} else {
+				flushable.SetFlush(responseFlusher(w))
+			}
+		}
+
+		// Render failures are wrapped in an *ErrRender recording how much output
+		// the component produced before failing
+		render := func(ctx context.Context, out io.Writer) error {
+			counted := &countingWriter{Writer: out}
+			var err error
+			if raw, ok := instance.Interface().(RawResponder); ok && replacement == nil {
+				err = raw.WriteResponse(ctx, counted)
+			} else {
+				err = renderWithOutOfBand(ctx, counted, component, instance.Interface())
+			}
+			if err != nil {
+				return &ErrRender{ComponentName: componentName, BytesWritten: counted.n, Err: err}
+			}
+			return nil
+		}
+		err = observePhase(req.Context(), observer, componentName, PhaseRender, func() error {
+			if renderLimit > 0 {
+				return r.renderWithin(req.Context(), out, renderLimit, componentName, recoverPanics, render)
+			}
+			return render(req.Context(), out)
+		})
+		var renderTimedOut *ErrRenderTimeout
+		if errors.As(err, &renderTimedOut) {
+			logger.Error("component render timed out",
+				"component", componentName,
+				"timeout", renderLimit)
+			for name := range hxResponseHeaders(w.Header()) {
+				w.Header().Del(name)
+			}
+			r.reportError(req.Context(), componentName, "render", err, http.StatusGatewayTimeout)
+			r.renderComponentError(w, req, componentName, err, "Gateway Timeout", "Component took too long to render", http.StatusGatewayTimeout)
+			return
+		}
+		// A client that disconnects mid-render is not a render failure
+		if errors.Is(err, context.Canceled) && req.Context().Err() != nil {
+			if buf != nil {
+				for name := range hxResponseHeaders(w.Header()) {
+					w.Header().Del(name)
+				}
+			}
+			r.renderCanceled(w, req, componentName, "render", err)
+			return
+		}
+		if err != nil {
+			message := err.Error()
+			var renderErr *ErrRender
+			if errors.As(err, &renderErr) {
+				message = renderErr.Err.Error()
+			}
+			logger.Error("component render error",
 				"component", componentName,
 				"error", err)
-			r.renderError(w, req, "Render Error", fmt.Sprintf("Component rendering failed: %v", err), http.StatusInternalServerError)
+			if buf != nil {
+				// Nothing has been written yet, so discard the component's response
+				// headers and let the error handler start from a clean slate
+				for name := range hxResponseHeaders(w.Header()) {
+					w.Header().Del(name)
+				}
+			}
+			r.reportError(req.Context(), componentName, "render", err, http.StatusInternalServerError)
+			r.renderComponentError(w, req, componentName, err, "Render Error", fmt.Sprintf("Component rendering failed: %s", message), http.StatusInternalServerError)
+			return
+		}
+		counters.renders.Add(1)
+
+		if tracer != nil {
+			r.writeTrace(w, tracer.trace(componentName, eventName, instance.Interface()))
 			return
 		}
 
-		slog.Debug("component rendered successfully",
+		if buf != nil {
+			if cacheable {
+				entry.cache.set(cacheKey, buf.Bytes(), storedResponseHeaders(w.Header()), time.Now())
+			}
+			if idemResp != nil {
+				status := http.StatusOK
+				if eventResult != nil && eventResult.StatusCode != 0 {
+					status = eventResult.StatusCode
+				}
+				idemStore.complete(idemResp, status, buf.Bytes(), replayableHeaders(w.Header()), time.Now())
+			}
+			if err := writeBufferedBody(w, req, buf.Bytes(), entry.etag); err != nil {
+				logger.Error("failed to write component response",
+					"component", componentName,
+					"error", err)
+			}
+		}
+
+		logger.Debug("component rendered successfully",
 			"component", componentName,
 			"has_event", hasEvent,
 			"form_fields", len(req.Form))
+
+		r.afterRender(req.Context(), instance.Interface(), componentName)
+	}
+}
+
+// afterRender calls AfterRender if instance implements AfterRenderHandler. The
+// response is already sent, so an error can only be logged.
+func (r *Registry) afterRender(ctx context.Context, instance any, componentName string) {
+	if afterRender, ok := instance.(AfterRenderHandler); ok {
+		if err := afterRender.AfterRender(ctx); err != nil {
+			r.logger().Error("AfterRender error",
+				"component", componentName,
+				"error", err)
+		}
 	}
 }
 
 // handleEvent processes event-driven method calls on a component.
-// It implements the lifecycle: BeforeEvent → On{EventName} → AfterEvent
-// Returns an error if any step fails, stopping further processing.
-func (r *Registry) handleEvent(ctx context.Context, instance interface{}, eventName, componentName string) error {
+// It implements the lifecycle: BeforeEvent → On{EventName} → AfterEvent,
+// wrapped in a transaction if the component implements Transactional.
+// Returns an error if any step fails, stopping further processing. The context
+// is checked before each step, so a cancelled or timed-out request returns an
+// error wrapping ctx.Err() without running the remaining steps.
+// If the event handler has the signature On{Event}(ctx) (templ.Component, error),
+// the component it returns is passed back to be rendered instead of the instance;
+// with the signature On{Event}(ctx) (*EventResult, error), the result is passed back
+// to be applied to the response.
+func (r *Registry) handleEvent(ctx context.Context, instance interface{}, eventName, componentName string) (templ.Component, *EventResult, error) {
+	if err := eventContextErr(ctx, eventName, "the event"); err != nil {
+		return nil, nil, err
+	}
+	var replacement templ.Component
+	var result *EventResult
+	err := inTransaction(ctx, instance, func(ctx context.Context) error {
+		var err error
+		replacement, result, err = r.dispatchEvent(ctx, instance, eventName, componentName)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return replacement, result, nil
+}
+
+// dispatchEvent runs BeforeEvent, On{EventName} and AfterEvent for handleEvent.
+func (r *Registry) dispatchEvent(ctx context.Context, instance interface{}, eventName, componentName string) (templ.Component, *EventResult, error) {
+	logger := r.logger()
+
 	// Call BeforeEvent hook if component implements it
 	if beforeHandler, ok := instance.(BeforeEventHandler); ok {
-		slog.Debug("calling BeforeEvent hook",
+		if err := eventContextErr(ctx, eventName, "BeforeEvent"); err != nil {
+			return nil, nil, err
+		}
+		logger.Debug("calling BeforeEvent hook",
 			"component", componentName,
 			"event", eventName)
 		if err := beforeHandler.BeforeEvent(ctx, eventName); err != nil {
-			return fmt.Errorf("BeforeEvent failed: %w", err)
+			return nil, nil, fmt.Errorf("BeforeEvent failed: %w", err)
 		}
 	}
 
 	// Find and call the event handler method: On{EventName}
-	// Convert event name to method name (e.g., "increment" -> "OnIncrement")
-	methodName := "On" + capitalize(eventName)
+	// Convert event name to method name (e.g., "increment" -> "OnIncrement"),
+	// using the prefix set with SetEventMethodPrefix
+	methodName := r.eventMethodPrefix() + capitalize(eventName)
 
 	value := reflect.ValueOf(instance)
 	method := value.MethodByName(methodName)
 
 	if !method.IsValid() {
-		return &ErrEventNotFound{
+		return nil, nil, &ErrEventNotFound{
 			ComponentName: componentName,
 			EventName:     eventName,
 		}
 	}
 
 	// Validate event handler signature: On{Event}(ctx context.Context) error
-	methodType := method.Type()
-	if methodType.NumIn() != 1 {
-		return fmt.Errorf("event handler '%s' must have signature On%s(ctx context.Context) error", methodName, capitalize(eventName))
+	// or On{Event}(ctx context.Context) (templ.Component, error)
+	// or On{Event}(ctx context.Context) (*EventResult, error)
+	if err := checkEventSignature(componentName, methodName, method.Type()); err != nil {
+		return nil, nil, err
 	}
+	incrementCount(&r.componentStats(componentName).events, eventName)
 
-	// Check that first parameter is context.Context
-	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
-	if !methodType.In(0).Implements(ctxType) {
-		return fmt.Errorf("event handler '%s' first parameter must be context.Context", methodName)
+	// Call the BeforeOn{EventName} hook, if the component declares one
+	if err := callEventHook(ctx, value, componentName, eventName, "Before"+methodName); err != nil {
+		return nil, nil, err
 	}
 
-	// Call the event handler method with context
-	slog.Debug("calling event handler",
+	// Call the event handler method with context, unless the request is already
+	// cancelled or timed out
+	if err := eventContextErr(ctx, eventName, methodName); err != nil {
+		return nil, nil, err
+	}
+	logger.Debug("calling event handler",
 		"component", componentName,
 		"event", eventName,
 		"method", methodName)
 
 	results := method.Call([]reflect.Value{reflect.ValueOf(ctx)})
 
-	// Check if method returns an error
+	// Check if method returns an error (always the last result)
 	if len(results) > 0 {
-		if err, ok := results[0].Interface().(error); ok && err != nil {
-			return fmt.Errorf("event handler failed: %w", err)
+		if err, ok := results[len(results)-1].Interface().(error); ok && err != nil {
+			return nil, nil, fmt.Errorf("event handler failed: %w", err)
+		}
+	}
+
+	// On{Event}(ctx) (templ.Component, error) may return a replacement view,
+	// and On{Event}(ctx) (*EventResult, error) changes to the response
+	var replacement templ.Component
+	var result *EventResult
+	if len(results) == 2 {
+		switch first := results[0].Interface().(type) {
+		case templ.Component:
+			replacement = first
+		case *EventResult:
+			result = first
 		}
 	}
 
+	// Call the AfterOn{EventName} hook, if the component declares one
+	if err := callEventHook(ctx, value, componentName, eventName, "After"+methodName); err != nil {
+		return nil, nil, err
+	}
+
 	// Call AfterEvent hook if component implements it
 	if afterHandler, ok := instance.(AfterEventHandler); ok {
-		slog.Debug("calling AfterEvent hook",
+		if err := eventContextErr(ctx, eventName, "AfterEvent"); err != nil {
+			return nil, nil, err
+		}
+		logger.Debug("calling AfterEvent hook",
 			"component", componentName,
 			"event", eventName)
 		if err := afterHandler.AfterEvent(ctx, eventName); err != nil {
-			return fmt.Errorf("AfterEvent failed: %w", err)
+			return nil, nil, fmt.Errorf("AfterEvent failed: %w", err)
 		}
 	}
 
+	return replacement, result, nil
+}
+
+// eventContextErr returns an error wrapping ctx.Err() if the request was cancelled
+// or timed out before the named event phase, so abandoned requests stop early.
+func eventContextErr(ctx context.Context, eventName, phase string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("event '%s' stopped before %s: %w", eventName, phase, err)
+	}
 	return nil
 }
 
@@ -545,8 +1627,9 @@ func capitalize(s string) string {
 }
 
 // Handler extracts the component name from the URL path and renders the component.
-// The component name is extracted from the last segment of the URL path after the last slash.
-// This allows for wildcard routing patterns.
+// The component name is everything after the handler prefix (default "/component/"),
+// which allows namespaced names such as "admin/users". For paths outside the prefix,
+// the name is the last segment of the URL path. This allows for wildcard routing patterns.
 //
 // Example with chi:
 //
@@ -562,44 +1645,47 @@ func capitalize(s string) string {
 //	http.HandleFunc("/component/", registry.Handler)
 //
 // For URL "/component/search", the component name will be "search".
+// For URL "/component/admin/users", the component name will be "admin/users".
 // For URL "/api/components/login", the component name will be "login".
+//
+// Use SetNameExtractor to resolve the name differently (e.g. from a route variable).
 func (r *Registry) Handler(w http.ResponseWriter, req *http.Request) {
-	// Extract component name from URL path (last segment after last slash)
-	path := req.URL.Path
-	lastSlash := len(path)
-	for i := len(path) - 1; i >= 0; i-- {
-		if path[i] == '/' {
-			lastSlash = i
-			break
-		}
-	}
-	componentName := path[lastSlash+1:]
-
-	// Remove trailing slash if present
-	if componentName == "" && lastSlash > 0 {
-		// Path ends with slash, try again
-		for i := lastSlash - 1; i >= 0; i-- {
-			if path[i] == '/' {
-				componentName = path[i+1 : lastSlash]
-				break
-			}
-		}
+	r.serveComponent(w, req, r.componentNameFor(req))
+}
+
+// PathValueHandler returns a handler that resolves the component name from the
+// wildcard paramName of a Go 1.22+ http.ServeMux pattern, via req.PathValue.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	mux.HandleFunc("/component/{name}", registry.PathValueHandler("name"))
+//	// or, to allow namespaced names such as "admin/users":
+//	mux.HandleFunc("/component/{name...}", registry.PathValueHandler("name"))
+func (r *Registry) PathValueHandler(paramName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		r.serveComponent(w, req, req.PathValue(paramName))
 	}
+}
 
+// serveComponent validates a component name resolved from the request, ignoring
+// trailing slashes, and serves the component through HandlerFor.
+func (r *Registry) serveComponent(w http.ResponseWriter, req *http.Request, componentName string) {
+	componentName = strings.TrimRight(componentName, "/")
 	if componentName == "" {
-		slog.Warn("empty component name in URL path",
+		r.logger().Warn("empty component name in URL path",
 			"path", req.URL.Path)
 		r.renderError(w, req, "Bad Request", "Component name cannot be empty", http.StatusBadRequest)
 		return
 	}
 
-	// Validate component name (alphanumeric, dash, underscore only)
+	// Validate component name (alphanumeric, dash, underscore, dots and namespace slashes only)
 	if !isValidComponentName(componentName) {
 		err := &ErrInvalidComponentName{
 			ComponentName: componentName,
-			Reason:        "component names must contain only alphanumeric characters, dashes, and underscores, and be less than 100 characters",
+			Reason:        "component names must contain only alphanumeric characters, dashes, underscores, and dots or slashes between words, and be less than 100 characters",
 		}
-		slog.Warn("invalid component name",
+		r.logger().Warn("invalid component name",
 			"component", componentName,
 			"path", req.URL.Path,
 			"error", err)
@@ -608,22 +1694,57 @@ func (r *Registry) Handler(w http.ResponseWriter, req *http.Request) {
 	}
 
 	// Use HandlerFor to handle the actual request
-	r.HandlerFor(componentName)(w, req)
+	r.HandlerFor(r.resolveName(componentName))(w, req)
 }
 
 // renderError renders error responses using the configured error handler
 func (r *Registry) renderError(w http.ResponseWriter, req *http.Request, title string, message string, code int) {
-	r.errorHandler(w, req, title, message, code)
+	r.renderComponentError(w, req, "", nil, title, message, code)
+}
+
+// renderComponentError renders the error response for a failed component request,
+// passing the component name and underlying error to the configured error handler.
+func (r *Registry) renderComponentError(w http.ResponseWriter, req *http.Request, componentName string, err error, title string, message string, code int) {
+	r.errorHandler(w, req, ErrorContext{
+		Title:         title,
+		Message:       message,
+		Code:          code,
+		ComponentName: componentName,
+		Err:           err,
+		IsHTMX:        req.Header.Get("HX-Request") == "true",
+	})
+}
+
+// renderFallback renders a component's recovery view with a 500 status.
+// The component's HX-* response headers are discarded since its request failed.
+func (r *Registry) renderFallback(w http.ResponseWriter, req *http.Request, fallback templ.Component, componentName string) {
+	for name := range hxResponseHeaders(w.Header()) {
+		w.Header().Del(name)
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusInternalServerError)
+	if err := fallback.Render(req.Context(), w); err != nil {
+		r.logger().Error("failed to render recovery component",
+			"component", componentName,
+			"error", err)
+	}
 }
 
 // ListComponents returns the names of all registered components in alphabetical order.
-func (r *Registry) ListComponents() []string {
+// Pass EnabledComponents or DisabledComponents to list only components in that state.
+func (r *Registry) ListComponents(filter ...ComponentFilter) []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	f := AllComponents
+	if len(filter) > 0 {
+		f = filter[0]
+	}
 	names := make([]string, 0, len(r.components))
-	for name := range r.components {
-		names = append(names, name)
+	for name, entry := range r.components {
+		if f.matches(!entry.disabled) {
+			names = append(names, name)
+		}
 	}
 	sort.Strings(names)
 	return names
@@ -641,6 +1762,15 @@ func (r *Registry) IsRegistered(name string) bool {
 type ComponentInfo struct {
 	Name       string
 	StructType string
+
+	// Events lists the events handled by On{Event}(ctx context.Context) error methods
+	Events []string
+	// Interfaces lists the optional interfaces the component implements (e.g. "Processor")
+	Interfaces []string
+	// FormFields lists the form field names decoded into the component
+	FormFields []string
+	// Enabled is false while the component is disabled with SetEnabled
+	Enabled bool
 }
 
 // GetComponentInfo returns metadata about a registered component.
@@ -653,23 +1783,57 @@ func (r *Registry) GetComponentInfo(name string) (ComponentInfo, error) {
 		return ComponentInfo{}, &ErrComponentNotFound{ComponentName: name}
 	}
 
+	ptrType := reflect.PointerTo(meta.structType)
 	return ComponentInfo{
 		Name:       name,
 		StructType: meta.structType.String(),
+		Events:     discoverEvents(ptrType, r.eventPrefix),
+		Interfaces: discoverInterfaces(ptrType),
+		FormFields: discoverFormFields(meta.structType),
+		Enabled:    !meta.disabled,
 	}, nil
 }
 
-// isValidComponentName validates that a component name contains only
-// alphanumeric characters, dashes, and underscores, and is not too long.
+// componentNameFromPath returns the component name for a URL path: everything after
+// prefix if the path starts with it, otherwise the last path segment. Trailing
+// slashes are ignored, so "/component/search/" resolves to "search".
+func componentNameFromPath(path, prefix string) string {
+	path = strings.TrimRight(path, "/")
+	if path+"/" == prefix {
+		return ""
+	}
+	if name, ok := strings.CutPrefix(path, prefix); ok {
+		return name
+	}
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// isValidComponentName validates a component name against the grammar
+//
+//	name    = segment *( "/" segment )
+//	segment = word *( "." word )
+//	word    = 1*( ALPHA / DIGIT / "-" / "_" )
+//
+// and a maximum length of 100. Slashes separate namespace segments (e.g.
+// "admin/users") and dots separate versions or qualifiers within a segment (e.g.
+// "search.v2"). Because every dot must sit between two words, empty, "." and ".."
+// segments are rejected, which prevents path traversal.
 func isValidComponentName(name string) bool {
 	if name == "" || len(name) > 100 {
 		return false
 	}
+	for _, segment := range strings.Split(name, "/") {
+		for _, word := range strings.Split(segment, ".") {
+			if word == "" {
+				return false
+			}
+		}
+	}
 	for _, r := range name {
 		if !((r >= 'a' && r <= 'z') ||
 			(r >= 'A' && r <= 'Z') ||
 			(r >= '0' && r <= '9') ||
-			r == '-' || r == '_') {
+			r == '-' || r == '_' || r == '/' || r == '.') {
 			return false
 		}
 	}
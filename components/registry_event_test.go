@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/a-h/templ"
 	"github.com/ocomsoft/HxComponents/components"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -391,3 +392,269 @@ func TestGETRequestWithEvents(t *testing.T) {
 	assert.Contains(t, body, "OnIncrement")
 	assert.Contains(t, body, "AfterEvent:increment")
 }
+
+func TestHandlerForEvent(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestEventComponent](registry, "test")
+
+	handler := registry.HandlerForEvent("test", "increment")
+
+	t.Run("dispatches the fixed event without hxc-event", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/test/increment", strings.NewReader("count=5"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Count: 6")
+		assert.Contains(t, w.Body.String(), "OnIncrement")
+	})
+
+	t.Run("ignores the hxc-event form value", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/test/increment", strings.NewReader("count=5&hxc-event=decrement"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		assert.Contains(t, w.Body.String(), "Count: 6")
+		assert.NotContains(t, w.Body.String(), "OnDecrement")
+	})
+
+	t.Run("panics for unknown components and events", func(t *testing.T) {
+		assert.Panics(t, func() { registry.HandlerForEvent("missing", "increment") })
+		assert.Panics(t, func() { registry.HandlerForEvent("test", "explode") })
+	})
+}
+
+func TestSetEventParamName(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestEventComponent](registry, "test")
+	registry.SetEventParamName("_event")
+
+	post := func(body string) string {
+		req := httptest.NewRequest(http.MethodPost, "/component/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("test")(w, req)
+		return w.Body.String()
+	}
+
+	t.Run("dispatches with the new key", func(t *testing.T) {
+		body := post("count=5&_event=increment")
+		assert.Contains(t, body, "Count: 6")
+		assert.Contains(t, body, "OnIncrement")
+	})
+
+	t.Run("ignores the old key", func(t *testing.T) {
+		body := post("count=5&hxc-event=increment")
+		assert.Contains(t, body, "Count: 5")
+		assert.NotContains(t, body, "OnIncrement")
+	})
+
+	t.Run("rejects an empty name", func(t *testing.T) {
+		assert.Panics(t, func() { registry.SetEventParamName("") })
+	})
+}
+
+// TestHandlePrefixComponent names its event handlers with a Handle prefix
+type TestHandlePrefixComponent struct {
+	Count int `form:"count"`
+}
+
+func (t *TestHandlePrefixComponent) HandleIncrement(ctx context.Context) error {
+	t.Count++
+	return nil
+}
+
+func (t *TestHandlePrefixComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>Count: %d</div>", t.Count)
+	return nil
+}
+
+func TestSetEventMethodPrefix(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestHandlePrefixComponent](registry, "counter")
+	registry.SetEventMethodPrefix("Handle")
+
+	t.Run("dispatches to Handle methods", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/component/counter", strings.NewReader("count=5&hxc-event=increment"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>Count: 6</div>", w.Body.String())
+	})
+
+	t.Run("HandlerForEvent and ComponentInfo use the prefix", func(t *testing.T) {
+		assert.NotPanics(t, func() { registry.HandlerForEvent("counter", "increment") })
+
+		info, err := registry.GetComponentInfo("counter")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"increment"}, info.Events)
+	})
+
+	t.Run("SimulateEvent uses the prefix", func(t *testing.T) {
+		counter := &TestHandlePrefixComponent{Count: 1}
+		require.NoError(t, registry.SimulateEvent(context.Background(), counter, "increment"))
+		assert.Equal(t, 2, counter.Count)
+
+		assert.Error(t, components.SimulateEvent(context.Background(), counter, "increment"))
+	})
+
+	t.Run("rejects invalid prefixes", func(t *testing.T) {
+		assert.Panics(t, func() { registry.SetEventMethodPrefix("") })
+		assert.Panics(t, func() { registry.SetEventMethodPrefix("handle") })
+		assert.Panics(t, func() { registry.SetEventMethodPrefix("On-") })
+	})
+}
+
+// TestLoginSwapComponent swaps in a dashboard view after a successful login
+type TestLoginSwapComponent struct {
+	Username string `form:"username"`
+}
+
+func (t *TestLoginSwapComponent) OnLogin(ctx context.Context) (templ.Component, error) {
+	if t.Username == "" {
+		return nil, fmt.Errorf("username is required")
+	}
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, "<div>Dashboard for %s</div>", t.Username)
+		return err
+	}), nil
+}
+
+func (t *TestLoginSwapComponent) OnRetry(ctx context.Context) (templ.Component, error) {
+	return nil, nil
+}
+
+func (t *TestLoginSwapComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprint(w, "<form>Login</form>")
+	return nil
+}
+
+func TestEventReplacementComponent(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestLoginSwapComponent](registry, "login")
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("login")(w, req)
+		return w
+	}
+
+	t.Run("renders the returned component", func(t *testing.T) {
+		w := post("username=demo&hxc-event=login")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>Dashboard for demo</div>", w.Body.String())
+	})
+
+	t.Run("renders the instance when nil is returned", func(t *testing.T) {
+		w := post("hxc-event=retry")
+		assert.Equal(t, "<form>Login</form>", w.Body.String())
+	})
+
+	t.Run("returned error renders the error page", func(t *testing.T) {
+		w := post("hxc-event=login")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "username is required")
+	})
+
+	t.Run("SimulateEvent surfaces the error", func(t *testing.T) {
+		err := components.SimulateEvent(context.Background(), &TestLoginSwapComponent{}, "login")
+		assert.ErrorContains(t, err, "username is required")
+	})
+
+	t.Run("events are discovered", func(t *testing.T) {
+		info, err := registry.GetComponentInfo("login")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"login", "retry"}, info.Events)
+	})
+}
+
+// TestEventHookComponent declares per-event hooks for its increment event only
+type TestEventHookComponent struct {
+	TestEventComponent
+	FailBefore bool `form:"failBefore"`
+}
+
+func (t *TestEventHookComponent) BeforeOnIncrement(ctx context.Context) error {
+	t.EventsHistory = append(t.EventsHistory, "BeforeOnIncrement")
+	if t.FailBefore {
+		return fmt.Errorf("out of stock")
+	}
+	return nil
+}
+
+func (t *TestEventHookComponent) AfterOnIncrement(ctx context.Context) error {
+	t.EventsHistory = append(t.EventsHistory, "AfterOnIncrement")
+	return nil
+}
+
+// TestMisSignedHookComponent has a per-event hook with an invalid signature
+type TestMisSignedHookComponent struct {
+	TestEventComponent
+}
+
+func (t *TestMisSignedHookComponent) AfterOnDecrement(ctx context.Context, step int) error {
+	return nil
+}
+
+func TestPerEventHooks(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestEventHookComponent](registry, "hooks")
+
+	post := func(values url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/hooks", strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("hooks")(w, req)
+		return w
+	}
+
+	t.Run("hooks run around their event", func(t *testing.T) {
+		w := post(url.Values{"count": {"1"}, "hxc-event": {"increment"}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "<div>Count: 2, History: [BeforeEvent:increment BeforeOnIncrement OnIncrement AfterOnIncrement AfterEvent:increment Process Render]</div>", w.Body.String())
+	})
+
+	t.Run("hooks don't run for other events", func(t *testing.T) {
+		w := post(url.Values{"count": {"1"}, "hxc-event": {"decrement"}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "<div>Count: 0, History: [BeforeEvent:decrement OnDecrement AfterEvent:decrement Process Render]</div>", w.Body.String())
+	})
+
+	t.Run("an error in the before hook skips the event", func(t *testing.T) {
+		w := post(url.Values{"count": {"1"}, "failBefore": {"true"}, "hxc-event": {"increment"}})
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "BeforeOnIncrement failed: out of stock")
+	})
+
+	t.Run("a hook with the wrong signature is reported", func(t *testing.T) {
+		components.Register[*TestMisSignedHookComponent](registry, "mis-signed-hook")
+
+		var reported error
+		registry.SetOnError(func(ctx context.Context, err *components.ComponentError) {
+			reported = err.Err
+		})
+		defer registry.SetOnError(nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/component/mis-signed-hook?hxc-event=decrement", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("mis-signed-hook")(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var sigErr *components.ErrEventSignature
+		require.ErrorAs(t, reported, &sigErr)
+		assert.Equal(t, "AfterOnDecrement", sigErr.MethodName)
+		assert.Equal(t, "func(context.Context) error", sigErr.Expected)
+	})
+
+	t.Run("SimulateEvent runs the hooks in the same order", func(t *testing.T) {
+		component := &TestEventHookComponent{}
+		require.NoError(t, components.SimulateEvent(context.Background(), component, "increment"))
+		assert.Equal(t, []string{"BeforeEvent:increment", "BeforeOnIncrement", "OnIncrement", "AfterOnIncrement", "AfterEvent:increment", "Process"}, component.EventsHistory)
+	})
+}
@@ -2,13 +2,19 @@ package components
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/a-h/templ"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -234,3 +240,631 @@ func TestHandlerURLExtraction(t *testing.T) {
 		})
 	}
 }
+
+// captureHandler is a slog.Handler that records every log record it receives
+type captureHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *captureHandler) Enabled(ctx context.Context, level slog.Level) bool { return true }
+
+func (h *captureHandler) Handle(ctx context.Context, rec slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, rec)
+	return nil
+}
+
+func (h *captureHandler) WithAttrs(attrs []slog.Attr) slog.Handler { return h }
+func (h *captureHandler) WithGroup(name string) slog.Handler       { return h }
+
+// attrs returns the attributes of the first record with the given message
+func (h *captureHandler) attrs(msg string) (map[string]string, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, rec := range h.records {
+		if rec.Message != msg {
+			continue
+		}
+		attrs := make(map[string]string)
+		rec.Attrs(func(a slog.Attr) bool {
+			attrs[a.Key] = a.Value.String()
+			return true
+		})
+		return attrs, true
+	}
+	return nil, false
+}
+
+func TestDebugModeFieldNames(t *testing.T) {
+	registry := NewRegistry()
+	Register[*TestLoginForm](registry, "login")
+
+	handler := &captureHandler{}
+	registry.SetLogger(slog.New(handler))
+
+	post := func() *httptest.ResponseRecorder {
+		form := url.Values{"username": {"demo"}, "password": {"s3cret-value"}}
+		req := httptest.NewRequest(http.MethodPost, "/component/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("login")(w, req)
+		return w
+	}
+
+	t.Run("omitted outside debug mode", func(t *testing.T) {
+		w := post()
+		if got := w.Header().Get("X-HxComponent-Fields"); got != "" {
+			t.Errorf("expected no X-HxComponent-Fields header, got '%s'", got)
+		}
+	})
+
+	t.Run("lists field names without values", func(t *testing.T) {
+		registry.EnableDebugMode()
+		w := post()
+
+		if got := w.Header().Get("X-HxComponent-Fields"); got != "password,username" {
+			t.Errorf("expected X-HxComponent-Fields 'password,username', got '%s'", got)
+		}
+
+		attrs, ok := handler.attrs("received form fields")
+		if !ok {
+			t.Fatal("expected 'received form fields' to be logged")
+		}
+		if attrs["fields"] != "[password username]" {
+			t.Errorf("expected fields attribute '[password username]', got '%s'", attrs["fields"])
+		}
+
+		handler.mu.Lock()
+		defer handler.mu.Unlock()
+		for _, rec := range handler.records {
+			rec.Attrs(func(a slog.Attr) bool {
+				if strings.Contains(a.Value.String(), "s3cret-value") {
+					t.Errorf("form value leaked into log record '%s'", rec.Message)
+				}
+				return true
+			})
+		}
+	})
+}
+
+// TestDebugCounter has an event handler, for the debug event header
+type TestDebugCounter struct {
+	Count int `form:"count"`
+}
+
+func (c *TestDebugCounter) OnIncrement(ctx context.Context) error {
+	c.Count++
+	return nil
+}
+
+func (c *TestDebugCounter) Render(ctx context.Context, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "<div>%d</div>", c.Count)
+	return err
+}
+
+func TestDebugModeRequestHeaders(t *testing.T) {
+	registry := NewRegistry()
+	Register[*TestDebugCounter](registry, "counter")
+
+	body := "count=41&hxc-event=increment"
+	post := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/counter", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+		return w
+	}
+
+	t.Run("omitted outside debug mode", func(t *testing.T) {
+		w := post()
+		for _, name := range []string{"X-HxComponent-BodySize", "X-HxComponent-ContentType", "X-HxComponent-Event"} {
+			if got := w.Header().Get(name); got != "" {
+				t.Errorf("expected no %s header, got '%s'", name, got)
+			}
+		}
+	})
+
+	t.Run("reports body size, content type and event", func(t *testing.T) {
+		registry.EnableDebugMode()
+		defer registry.DisableDebugMode()
+		w := post()
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		if got, want := w.Header().Get("X-HxComponent-BodySize"), fmt.Sprintf("%d", len(body)); got != want {
+			t.Errorf("expected X-HxComponent-BodySize '%s', got '%s'", want, got)
+		}
+		if got := w.Header().Get("X-HxComponent-ContentType"); got != "application/x-www-form-urlencoded" {
+			t.Errorf("expected X-HxComponent-ContentType 'application/x-www-form-urlencoded', got '%s'", got)
+		}
+		if got := w.Header().Get("X-HxComponent-Event"); got != "increment" {
+			t.Errorf("expected X-HxComponent-Event 'increment', got '%s'", got)
+		}
+	})
+
+	t.Run("no event header without an event", func(t *testing.T) {
+		registry.EnableDebugMode()
+		defer registry.DisableDebugMode()
+		req := httptest.NewRequest(http.MethodGet, "/component/counter", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+
+		if got := w.Header().Get("X-HxComponent-Event"); got != "" {
+			t.Errorf("expected no X-HxComponent-Event header, got '%s'", got)
+		}
+		if got := w.Header().Get("X-HxComponent-BodySize"); got != "0" {
+			t.Errorf("expected X-HxComponent-BodySize '0', got '%s'", got)
+		}
+	})
+}
+
+func TestSetLogger(t *testing.T) {
+	registry := NewRegistry()
+	Register[*TestLoginForm](registry, "login")
+
+	handler := &captureHandler{}
+	registry.SetLogger(slog.New(handler))
+
+	t.Run("logs rendering with component name", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/login", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("login")(w, req)
+
+		attrs, ok := handler.attrs("component rendered successfully")
+		if !ok {
+			t.Fatal("expected 'component rendered successfully' to be logged to the custom logger")
+		}
+		if attrs["component"] != "login" {
+			t.Errorf("expected component attribute 'login', got '%s'", attrs["component"])
+		}
+	})
+
+	t.Run("logs missing components with component name", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/missing", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("missing")(w, req)
+
+		attrs, ok := handler.attrs("component not found")
+		if !ok {
+			t.Fatal("expected 'component not found' to be logged to the custom logger")
+		}
+		if attrs["component"] != "missing" {
+			t.Errorf("expected component attribute 'missing', got '%s'", attrs["component"])
+		}
+	})
+
+	t.Run("falls back to slog default when unset", func(t *testing.T) {
+		registry.SetLogger(nil)
+		if registry.logger() != slog.Default() {
+			t.Error("expected logger to fall back to slog.Default()")
+		}
+	})
+}
+
+// testRecoveringPanel panics in Process and renders a fallback view
+type testRecoveringPanel struct{}
+
+func (p *testRecoveringPanel) Process(ctx context.Context) error {
+	panic("boom")
+}
+
+func (p *testRecoveringPanel) Recover(ctx context.Context, recovered any) templ.Component {
+	return templ.Raw("<div>fallback</div>")
+}
+
+func (p *testRecoveringPanel) Render(ctx context.Context, w io.Writer) error {
+	return nil
+}
+
+func TestRecoverLogsStack(t *testing.T) {
+	registry := NewRegistry()
+	Register[*testRecoveringPanel](registry, "panel")
+
+	handler := &captureHandler{}
+	registry.SetLogger(slog.New(handler))
+
+	req := httptest.NewRequest(http.MethodGet, "/component/panel", nil)
+	w := httptest.NewRecorder()
+	registry.HandlerFor("panel")(w, req)
+
+	if w.Body.String() != "<div>fallback</div>" {
+		t.Errorf("expected fallback view, got: %s", w.Body.String())
+	}
+	attrs, ok := handler.attrs("panic in component handler")
+	if !ok {
+		t.Fatal("expected the panic to be logged")
+	}
+	if !strings.Contains(attrs["stack"], "goroutine") {
+		t.Errorf("expected a stack trace to be logged, got '%s'", attrs["stack"])
+	}
+}
+
+func TestNamespacedComponentNames(t *testing.T) {
+	registry := NewRegistry()
+	Register[*TestMethodForm](registry, "admin/users")
+
+	t.Run("routes nested URL to namespaced component", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/admin/users?q=test", nil)
+		w := httptest.NewRecorder()
+		registry.Handler(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "Method: GET") {
+			t.Errorf("expected component output, got: %s", w.Body.String())
+		}
+	})
+
+	t.Run("uses a custom prefix", func(t *testing.T) {
+		registry.SetHandlerPrefix("/ui")
+		defer registry.SetHandlerPrefix(DefaultHandlerPrefix)
+
+		req := httptest.NewRequest(http.MethodGet, "/ui/admin/users/", nil)
+		w := httptest.NewRecorder()
+		registry.Handler(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("rejects path traversal", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/admin/../users", nil)
+		w := httptest.NewRecorder()
+		registry.Handler(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestIsValidComponentName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"search", true},
+		{"user-profile_2", true},
+		{"admin/users", true},
+		{"admin/reports/daily", true},
+		{"", false},
+		{"admin/../users", false},
+		{"../secrets", false},
+		{"admin//users", false},
+		{"/admin", false},
+		{"admin/./users", false},
+		{"admin.users", true},
+		{"search.v2", true},
+		{"admin/search.v2", true},
+		{"../etc", false},
+		{"search..v2", false},
+		{"search.", false},
+		{".search", false},
+		{"search v2", false},
+		{"search\x00", false},
+	}
+
+	for _, tt := range tests {
+		if got := isValidComponentName(tt.name); got != tt.valid {
+			t.Errorf("isValidComponentName(%q) = %v, want %v", tt.name, got, tt.valid)
+		}
+	}
+}
+
+func TestVersionedComponentName(t *testing.T) {
+	registry := NewRegistry()
+	Register[*TestMethodForm](registry, "search.v2")
+
+	tests := []struct {
+		name         string
+		url          string
+		expectedCode int
+	}{
+		{"serves dotted name", "/component/search.v2?q=test", http.StatusOK},
+		{"serves dotted name without prefix", "/api/search.v2?q=test", http.StatusOK},
+		{"rejects traversal", "/component/..%2Fetc", http.StatusBadRequest},
+		{"rejects spaces", "/component/search%20v2", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+			registry.Handler(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCaseInsensitiveNames(t *testing.T) {
+	registry := NewRegistry()
+	Register[*TestMethodForm](registry, "search")
+
+	serve := func(url string) int {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		registry.Handler(w, req)
+		return w.Code
+	}
+
+	t.Run("trailing slashes are ignored", func(t *testing.T) {
+		if code := serve("/component/search/"); code != http.StatusOK {
+			t.Errorf("expected 200 for a trailing slash, got %d", code)
+		}
+		if code := serve("/component/search//"); code != http.StatusOK {
+			t.Errorf("expected 200 for repeated trailing slashes, got %d", code)
+		}
+	})
+
+	t.Run("names are case-sensitive by default", func(t *testing.T) {
+		if code := serve("/component/Search/"); code != http.StatusNotFound {
+			t.Errorf("expected 404 for a mixed-case name, got %d", code)
+		}
+	})
+
+	t.Run("mixed case resolves when enabled", func(t *testing.T) {
+		registry.SetCaseInsensitiveNames(true)
+		defer registry.SetCaseInsensitiveNames(false)
+
+		if code := serve("/component/Search/"); code != http.StatusOK {
+			t.Errorf("expected 200 for a mixed-case name, got %d", code)
+		}
+		if code := serve("/component/SEARCH"); code != http.StatusOK {
+			t.Errorf("expected 200 for an upper-case name, got %d", code)
+		}
+	})
+
+	t.Run("names differing by case are rejected at registration", func(t *testing.T) {
+		registry.SetCaseInsensitiveNames(true)
+		defer registry.SetCaseInsensitiveNames(false)
+
+		defer func() {
+			if recover() == nil {
+				t.Error("expected registering 'Search' to panic")
+			}
+		}()
+		Register[*TestMethodForm](registry, "Search")
+	})
+
+	t.Run("enabling rejects existing names differing by case", func(t *testing.T) {
+		registry := NewRegistry()
+		Register[*TestMethodForm](registry, "search")
+		Register[*TestMethodForm](registry, "Search")
+
+		defer func() {
+			if recover() == nil {
+				t.Error("expected SetCaseInsensitiveNames to panic")
+			}
+		}()
+		registry.SetCaseInsensitiveNames(true)
+	})
+}
+
+// testMetricsPanel fails in AfterRender
+type testMetricsPanel struct{}
+
+func (p *testMetricsPanel) Render(ctx context.Context, w io.Writer) error {
+	_, err := w.Write([]byte("<div>panel</div>"))
+	return err
+}
+
+func (p *testMetricsPanel) AfterRender(ctx context.Context) error {
+	return errors.New("metrics unavailable")
+}
+
+func TestAfterRenderErrorIsLogged(t *testing.T) {
+	registry := NewRegistry()
+	Register[*testMetricsPanel](registry, "panel")
+
+	handler := &captureHandler{}
+	registry.SetLogger(slog.New(handler))
+
+	req := httptest.NewRequest(http.MethodGet, "/component/panel", nil)
+	w := httptest.NewRecorder()
+	registry.HandlerFor("panel")(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != "<div>panel</div>" {
+		t.Errorf("expected the rendered panel, got %d: %s", w.Code, w.Body.String())
+	}
+	attrs, ok := handler.attrs("AfterRender error")
+	if !ok {
+		t.Fatal("expected the AfterRender error to be logged")
+	}
+	if attrs["error"] != "metrics unavailable" {
+		t.Errorf("expected error attribute 'metrics unavailable', got '%s'", attrs["error"])
+	}
+}
+
+func TestSetNameExtractor(t *testing.T) {
+	registry := NewRegistry()
+	Register[*TestMethodForm](registry, "search")
+	registry.SetNameExtractor(func(req *http.Request) string {
+		return chi.URLParam(req, "component")
+	})
+
+	router := chi.NewRouter()
+	router.Get("/widgets/{component}/view", registry.Handler)
+
+	t.Run("serves the component named by the route param", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/widgets/search/view?q=test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "Method: GET") {
+			t.Errorf("expected search component output, got: %s", w.Body.String())
+		}
+	})
+
+	t.Run("unknown route param is not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/widgets/missing/view", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("nil restores path-based extraction", func(t *testing.T) {
+		registry.SetNameExtractor(nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/component/search", nil)
+		w := httptest.NewRecorder()
+		registry.Handler(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+}
+
+func TestPathValueHandler(t *testing.T) {
+	registry := NewRegistry()
+	Register[*TestMethodForm](registry, "search")
+	Register[*TestMethodForm](registry, "admin/search")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/component/{name}", registry.PathValueHandler("name"))
+	mux.HandleFunc("/namespaced/{name...}", registry.PathValueHandler("name"))
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+	}{
+		{"dispatches by path value", "/component/search", http.StatusOK},
+		{"unknown name is not found", "/component/missing", http.StatusNotFound},
+		{"namespaced name via remainder wildcard", "/namespaced/admin/search", http.StatusOK},
+		{"invalid name is rejected", "/namespaced/bad%20name", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK && !strings.Contains(w.Body.String(), "Method: GET") {
+				t.Errorf("expected search component output, got: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequestLogging(t *testing.T) {
+	post := func(registry *Registry, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/counter", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+		return w
+	}
+	summaries := func(handler *captureHandler) int {
+		handler.mu.Lock()
+		defer handler.mu.Unlock()
+		count := 0
+		for _, rec := range handler.records {
+			if rec.Message == "component request" {
+				count++
+				if rec.Level != slog.LevelInfo {
+					t.Errorf("expected summary at info level, got %s", rec.Level)
+				}
+			}
+		}
+		return count
+	}
+
+	t.Run("one summary line for a counter increment", func(t *testing.T) {
+		registry := NewRegistry()
+		Register[*TestDebugCounter](registry, "counter", WithRequestLogging())
+		handler := &captureHandler{}
+		registry.SetLogger(slog.New(handler))
+
+		w := post(registry, "count=41&hxc-event=increment")
+
+		if got := summaries(handler); got != 1 {
+			t.Fatalf("expected 1 summary line, got %d", got)
+		}
+		attrs, _ := handler.attrs("component request")
+		want := map[string]string{
+			"component": "counter",
+			"method":    "POST",
+			"event":     "increment",
+			"status":    "200",
+			"bytes":     fmt.Sprint(w.Body.Len()),
+		}
+		for key, value := range want {
+			if attrs[key] != value {
+				t.Errorf("expected %s=%s, got '%s'", key, value, attrs[key])
+			}
+		}
+		if _, ok := attrs["duration"]; !ok {
+			t.Error("expected a duration attribute")
+		}
+		if _, ok := attrs["failed_phase"]; ok {
+			t.Error("expected no failed_phase attribute for a successful request")
+		}
+	})
+
+	t.Run("failed phase and error status are logged", func(t *testing.T) {
+		registry := NewRegistry()
+		Register[*TestDebugCounter](registry, "counter", WithRequestLogging())
+		handler := &captureHandler{}
+		registry.SetLogger(slog.New(handler))
+
+		post(registry, "count=abc")
+
+		attrs, ok := handler.attrs("component request")
+		if !ok {
+			t.Fatal("expected a summary line")
+		}
+		if attrs["status"] != "400" || attrs["failed_phase"] != PhaseDecode || attrs["event"] != "" {
+			t.Errorf("expected status=400 failed_phase=decode and no event, got %v", attrs)
+		}
+	})
+
+	t.Run("registry default", func(t *testing.T) {
+		registry := NewRegistry()
+		Register[*TestDebugCounter](registry, "counter")
+		handler := &captureHandler{}
+		registry.SetLogger(slog.New(handler))
+
+		post(registry, "count=1")
+		if got := summaries(handler); got != 0 {
+			t.Errorf("expected no summary line by default, got %d", got)
+		}
+
+		registry.SetRequestLogging(true)
+		post(registry, "count=1")
+		if got := summaries(handler); got != 1 {
+			t.Errorf("expected 1 summary line with the registry default, got %d", got)
+		}
+	})
+}
+
+func TestResponseCacheIsBounded(t *testing.T) {
+	cache := newResponseCache(time.Minute, nil)
+	now := time.Now()
+	for i := 0; i <= maxCacheEntries; i++ {
+		cache.set(fmt.Sprintf("q=%d", i), []byte("<div></div>"), nil, now.Add(time.Duration(i)*time.Millisecond))
+	}
+	if len(cache.entries) != maxCacheEntries {
+		t.Fatalf("expected %d entries, got %d", maxCacheEntries, len(cache.entries))
+	}
+	if _, ok := cache.entries["q=0"]; ok {
+		t.Fatal("expected the entry closest to expiry to be evicted")
+	}
+}
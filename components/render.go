@@ -0,0 +1,90 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/a-h/templ"
+)
+
+// RenderComponent builds a registered component outside an HTTP request, so pages
+// can embed it consistently with the HTTP path. It creates a new instance, decodes
+// values into it, runs the post-decode hook, Init, Validate and Process (but no
+// events), and returns the instance ready to render. Lifecycle methods can reach
+// the registry through RendererFromContext to build nested components.
+//
+// Example in a templ page:
+//
+//	templ IndexPage(search templ.Component) {
+//	    @search
+//	}
+//
+//	search, err := registry.RenderComponent(ctx, "search", url.Values{"q": {"go"}})
+//	if err != nil { ... }
+//	pages.IndexPage(search).Render(ctx, w)
+func (r *Registry) RenderComponent(ctx context.Context, name string, values url.Values) (templ.Component, error) {
+	return r.buildComponent(ctx, name, values, nil)
+}
+
+// buildComponent implements RenderComponent. When req is not nil the values come
+// from a client, so as in HandlerFor the form key limits and WithStrictFields are
+// enforced, request headers and the locale from ctx are applied, Authorize runs
+// after decoding, and component state is loaded before Init and saved after
+// Process.
+func (r *Registry) buildComponent(ctx context.Context, name string, values url.Values, req *http.Request) (templ.Component, error) {
+	r.mu.RLock()
+	entry, exists := r.components[name]
+	decoding := r.decodeSettingsLocked()
+	stateStore := r.stateStore
+	r.mu.RUnlock()
+
+	if !exists {
+		return nil, &ErrComponentNotFound{ComponentName: name}
+	}
+	if entry.disabled {
+		return nil, &ErrComponentDisabled{ComponentName: name}
+	}
+
+	instance := entry.newInstance().Interface()
+	ctx = withRenderer(ctx, r)
+
+	if err := r.decodeComponent(ctx, entry, instance, values, decoding, req != nil); err != nil {
+		return nil, &ComponentError{ComponentName: name, Operation: "decode", Err: err}
+	}
+
+	if req != nil {
+		applyHxHeaders(instance, req)
+		if v, ok := instance.(LocaleAware); ok {
+			if locale, ok := LocaleFromContext(ctx); ok {
+				v.SetLocale(locale)
+			}
+		}
+		if authorizer, ok := instance.(Authorizer); ok {
+			if err := authorizer.Authorize(ctx); err != nil {
+				return nil, &ComponentError{ComponentName: name, Operation: "authorize", Err: err}
+			}
+		}
+		if err := r.loadComponentState(ctx, stateStore, name, instance, values); err != nil {
+			return nil, &ComponentError{ComponentName: name, Operation: "state", Err: err}
+		}
+	}
+
+	// Validation errors are stored in the component for rendering, as in HandlerFor
+	if _, err := r.runLifecycle(ctx, instance, lifecycleOptions{componentName: name}); err != nil {
+		return nil, err
+	}
+
+	if req != nil {
+		if err := r.saveComponentState(ctx, stateStore, name, instance); err != nil {
+			return nil, &ComponentError{ComponentName: name, Operation: "state", Err: err}
+		}
+	}
+
+	component, ok := instance.(templ.Component)
+	if !ok {
+		return nil, fmt.Errorf("component '%s' does not implement templ.Component", name)
+	}
+	return component, nil
+}
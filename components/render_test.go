@@ -0,0 +1,152 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPartialRenderComponent writes some HTML and then fails
+type TestPartialRenderComponent struct{}
+
+func (c *TestPartialRenderComponent) GetHxTrigger() string {
+	return "partialRendered"
+}
+
+func (c *TestPartialRenderComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprint(w, "<div>partial output")
+	return fmt.Errorf("template exploded")
+}
+
+func TestBufferedRender(t *testing.T) {
+	get := func(registry *components.Registry) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/component/partial", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("partial")(w, req)
+		return w
+	}
+
+	t.Run("unbuffered render leaks partial output", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestPartialRenderComponent](registry, "partial")
+
+		w := get(registry)
+		assert.Contains(t, w.Body.String(), "partial output")
+	})
+
+	t.Run("WithBufferedRender shows only the error page", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestPartialRenderComponent](registry, "partial", components.WithBufferedRender())
+
+		w := get(registry)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Render Error")
+		assert.Contains(t, w.Body.String(), "template exploded")
+		assert.NotContains(t, w.Body.String(), "partial output")
+		assert.Empty(t, w.Header().Get("HX-Trigger"))
+	})
+
+	t.Run("SetBufferedRender applies to all components", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestPartialRenderComponent](registry, "partial")
+		registry.SetBufferedRender(true)
+
+		w := get(registry)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotContains(t, w.Body.String(), "partial output")
+	})
+
+	t.Run("successful buffered render writes output", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSimpleCounter](registry, "partial", components.WithBufferedRender())
+
+		w := get(registry)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>0</div>", w.Body.String())
+	})
+}
+
+// TestAfterRenderComponent records the order of Render and AfterRender
+type TestAfterRenderComponent struct {
+	Fail bool     `form:"fail"`
+	Log  []string `json:"-"`
+}
+
+func (c *TestAfterRenderComponent) Render(ctx context.Context, w io.Writer) error {
+	c.Log = append(c.Log, "Render")
+	fmt.Fprint(w, "<div>rendered</div>")
+	return nil
+}
+
+func (c *TestAfterRenderComponent) OnRefresh(ctx context.Context) error {
+	c.Log = append(c.Log, "OnRefresh")
+	return nil
+}
+
+func (c *TestAfterRenderComponent) AfterRender(ctx context.Context) error {
+	c.Log = append(c.Log, "AfterRender")
+	if c.Fail {
+		return fmt.Errorf("metrics unavailable")
+	}
+	return nil
+}
+
+func TestAfterRender(t *testing.T) {
+	t.Run("runs after Render", func(t *testing.T) {
+		component := &TestAfterRenderComponent{}
+		html, err := components.SimulateAndRender(context.Background(), component, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "<div>rendered</div>", html)
+		assert.Equal(t, []string{"Render", "AfterRender"}, component.Log)
+	})
+
+	t.Run("error is not propagated to the client", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestAfterRenderComponent](registry, "after")
+
+		req := httptest.NewRequest(http.MethodGet, "/component/after?fail=true", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("after")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>rendered</div>", w.Body.String())
+	})
+
+	t.Run("SimulateAndRender returns the error", func(t *testing.T) {
+		component := &TestAfterRenderComponent{Fail: true}
+		html, err := components.SimulateAndRender(context.Background(), component, "")
+		assert.ErrorContains(t, err, "AfterRender failed: metrics unavailable")
+		assert.Equal(t, "<div>rendered</div>", html)
+	})
+
+	t.Run("SimulateAndRender runs it once after an event", func(t *testing.T) {
+		component := &TestAfterRenderComponent{}
+		_, err := components.SimulateAndRender(context.Background(), component, "refresh")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"OnRefresh", "Render", "AfterRender"}, component.Log)
+	})
+
+	t.Run("SimulateEvent runs it last", func(t *testing.T) {
+		component := &TestAfterRenderComponent{}
+		assert.NoError(t, components.SimulateEvent(context.Background(), component, "refresh"))
+		assert.Equal(t, []string{"OnRefresh", "AfterRender"}, component.Log)
+
+		failing := &TestAfterRenderComponent{Fail: true}
+		assert.ErrorContains(t, components.SimulateEvent(context.Background(), failing, "refresh"), "AfterRender failed: metrics unavailable")
+	})
+
+	t.Run("SimulateProcess runs it", func(t *testing.T) {
+		component := &TestAfterRenderComponent{}
+		assert.NoError(t, components.SimulateProcess(context.Background(), component))
+		assert.Equal(t, []string{"AfterRender"}, component.Log)
+
+		failing := &TestAfterRenderComponent{Fail: true}
+		assert.ErrorContains(t, components.SimulateProcess(context.Background(), failing), "AfterRender failed: metrics unavailable")
+	})
+}
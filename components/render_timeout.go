@@ -0,0 +1,106 @@
+package components
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"time"
+)
+
+// SetRenderTimeout bounds how long Render may take for every component. When d > 0,
+// components are rendered into a buffer on a separate goroutine; if rendering does
+// not finish within d, the handler stops waiting and the error handler renders a
+// 504 Gateway Timeout instead. Pass d <= 0 to remove the limit (the default).
+//
+// templ rendering cannot be interrupted mid-write, so a Render that ignores its
+// context keeps running in the background until it returns, and its output is
+// discarded. The context passed to Render is cancelled at the deadline, so
+// renders that do I/O through ctx stop early. Enabling the timeout also buffers
+// every response, trading streaming for a clean error response.
+func (r *Registry) SetRenderTimeout(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.renderLimit = d
+}
+
+// renderWithin calls render with a private buffer on a separate goroutine and
+// copies its output to w if it completes within timeout. Otherwise it returns an
+// *ErrRenderTimeout and abandons the render; that includes an earlier deadline on
+// ctx, such as the component's WithTimeout. If the client cancels ctx first, it
+// returns ctx.Err().
+//
+// A panic in render is re-raised on the calling goroutine. With recoverPanics it
+// is raised as a *renderPanic carrying the render goroutine's stack for the
+// handler's panic recovery to report; otherwise the stack is logged and the
+// original value is raised, as it would be without a render timeout, for
+// recovery middleware further out.
+func (r *Registry) renderWithin(ctx context.Context, w io.Writer, timeout time.Duration, componentName string, recoverPanics bool, render func(ctx context.Context, w io.Writer) error) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+	renderCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		out       []byte
+		err       error
+		recovered *renderPanic
+	}
+	// Buffered so an abandoned render can finish without blocking forever
+	done := make(chan result, 1)
+	go func() {
+		var buf bytes.Buffer
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- result{recovered: &renderPanic{value: recovered, stack: debug.Stack()}}
+			}
+		}()
+		err := render(renderCtx, &buf)
+		done <- result{out: buf.Bytes(), err: err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.recovered != nil {
+			if recoverPanics {
+				panic(res.recovered)
+			}
+			r.logger().Error("panic in component render",
+				"component", componentName,
+				"error", res.recovered.value,
+				"stack", string(res.recovered.stack))
+			panic(res.recovered.value)
+		}
+		if res.err != nil {
+			return res.err
+		}
+		_, err := w.Write(res.out)
+		return err
+	case <-renderCtx.Done():
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return ctx.Err()
+		}
+		return &ErrRenderTimeout{ComponentName: componentName, Timeout: timeout}
+	}
+}
+
+// renderPanic is a panic raised while rendering on another goroutine, re-raised
+// by renderWithin with the stack of the goroutine that panicked, which the stack
+// of the re-panic would lose.
+type renderPanic struct {
+	value any
+	stack []byte
+}
+
+func (p *renderPanic) Error() string {
+	return fmt.Sprintf("%v\n\n%s", p.value, p.stack)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (p *renderPanic) Unwrap() error {
+	err, _ := p.value.(error)
+	return err
+}
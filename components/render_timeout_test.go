@@ -0,0 +1,139 @@
+package components_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSlowRenderComponent sleeps in Render for the requested number of milliseconds,
+// ignoring its context
+type TestSlowRenderComponent struct {
+	DelayMS int `form:"delay"`
+}
+
+func (c *TestSlowRenderComponent) GetHxTrigger() string {
+	return "rendered"
+}
+
+func (c *TestSlowRenderComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprint(w, "<div>partial")
+	time.Sleep(time.Duration(c.DelayMS) * time.Millisecond)
+	fmt.Fprint(w, "</div>")
+	return nil
+}
+
+func TestSetRenderTimeout(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSlowRenderComponent](registry, "slow")
+	registry.SetRenderTimeout(50 * time.Millisecond)
+
+	var reported *components.ComponentError
+	registry.SetOnError(func(ctx context.Context, err *components.ComponentError) {
+		reported = err
+	})
+
+	get := func(delay int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/component/slow?delay=%d", delay), nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("slow")(w, req)
+		return w
+	}
+
+	t.Run("fast render succeeds", func(t *testing.T) {
+		w := get(0)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>partial</div>", w.Body.String())
+		assert.Equal(t, "rendered", w.Header().Get("HX-Trigger"))
+	})
+
+	t.Run("slow render is abandoned with a 504", func(t *testing.T) {
+		start := time.Now()
+		w := get(500)
+		assert.Less(t, time.Since(start), 400*time.Millisecond)
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.NotContains(t, w.Body.String(), "partial")
+		assert.NotEqual(t, "rendered", w.Header().Get("HX-Trigger"))
+
+		require.NotNil(t, reported)
+		assert.Equal(t, "render", reported.Operation)
+		var timeoutErr *components.ErrRenderTimeout
+		require.True(t, errors.As(reported, &timeoutErr))
+		assert.Equal(t, "slow", timeoutErr.ComponentName)
+	})
+}
+
+// TestPanickingRenderComponent panics in Render
+type TestPanickingRenderComponent struct{}
+
+func (c *TestPanickingRenderComponent) Render(ctx context.Context, w io.Writer) error {
+	panic("render exploded")
+}
+
+func TestRenderTimeoutWithComponentTimeout(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSlowRenderComponent](registry, "slow", components.WithTimeout(30*time.Millisecond))
+	registry.SetRenderTimeout(time.Second)
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	registry.HandlerFor("slow")(w, httptest.NewRequest(http.MethodGet, "/component/slow?delay=500", nil))
+	assert.Less(t, time.Since(start), 400*time.Millisecond)
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.NotContains(t, w.Body.String(), "partial")
+}
+
+func TestRenderTimeoutPanics(t *testing.T) {
+	t.Run("recovered with the original value", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestPanickingRenderComponent](registry, "boom")
+		registry.SetRenderTimeout(time.Second)
+		var recovered any
+		registry.SetPanicHandler(func(value any, req *http.Request) (string, string, int) {
+			recovered = value
+			return "Boom", "render failed", http.StatusInternalServerError
+		})
+
+		w := httptest.NewRecorder()
+		registry.HandlerFor("boom")(w, httptest.NewRequest(http.MethodGet, "/component/boom", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "render exploded", recovered)
+	})
+
+	t.Run("re-panics with the original value", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestPanickingRenderComponent](registry, "boom", components.WithRecover(false))
+		registry.SetRenderTimeout(time.Second)
+
+		defer func() {
+			assert.Equal(t, "render exploded", recover())
+		}()
+		registry.HandlerFor("boom")(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/component/boom", nil))
+	})
+}
+
+func TestRenderTimeoutClientCancel(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSlowRenderComponent](registry, "slow")
+	registry.SetRenderTimeout(time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(30*time.Millisecond, cancel)
+	req := httptest.NewRequest(http.MethodGet, "/component/slow?delay=500", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	start := time.Now()
+	registry.HandlerFor("slow")(w, req)
+
+	assert.Less(t, time.Since(start), 400*time.Millisecond)
+	assert.Equal(t, components.StatusClientClosedRequest, w.Code)
+	assert.NotContains(t, w.Body.String(), "partial")
+}
@@ -0,0 +1,58 @@
+package components
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/a-h/templ"
+)
+
+// Renderer builds registered components by name. HandlerFor stores one backed by
+// the registry in the request context, so a component can embed other components
+// chosen at runtime, e.g. the widgets of a user-configurable dashboard.
+type Renderer interface {
+	// Render builds the named component from values, as RenderComponent does.
+	Render(ctx context.Context, name string, values url.Values) (templ.Component, error)
+}
+
+type rendererKey struct{}
+
+// registryRenderer adapts Registry.RenderComponent to the Renderer interface.
+type registryRenderer struct {
+	registry *Registry
+}
+
+func (r registryRenderer) Render(ctx context.Context, name string, values url.Values) (templ.Component, error) {
+	return r.registry.RenderComponent(ctx, name, values)
+}
+
+// withRenderer returns a copy of ctx carrying a Renderer backed by r.
+func withRenderer(ctx context.Context, r *Registry) context.Context {
+	return context.WithValue(ctx, rendererKey{}, Renderer(registryRenderer{registry: r}))
+}
+
+// RendererFromContext returns the Renderer stored by HandlerFor (and by
+// RenderComponent, for nested components) and whether one was present.
+//
+// Example:
+//
+//	func (d *Dashboard) Render(ctx context.Context, w io.Writer) error {
+//	    renderer, ok := components.RendererFromContext(ctx)
+//	    if !ok {
+//	        return errors.New("dashboard must be rendered by the registry")
+//	    }
+//	    for _, widget := range d.Widgets {
+//	        child, err := renderer.Render(ctx, widget.Component, widget.Values)
+//	        if err != nil {
+//	            return err
+//	        }
+//	        if err := child.Render(ctx, w); err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return nil
+//	}
+func RendererFromContext(ctx context.Context) (Renderer, bool) {
+	renderer, ok := ctx.Value(rendererKey{}).(Renderer)
+	return renderer, ok
+}
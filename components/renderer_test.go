@@ -0,0 +1,71 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDashboardComponent embeds the counters named in its form data
+type TestDashboardComponent struct {
+	Counters []int `form:"counters"`
+}
+
+func (d *TestDashboardComponent) Render(ctx context.Context, w io.Writer) error {
+	renderer, ok := components.RendererFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("no renderer in context")
+	}
+	fmt.Fprint(w, "<section>")
+	for _, count := range d.Counters {
+		child, err := renderer.Render(ctx, "counter", url.Values{"count": {fmt.Sprint(count)}})
+		if err != nil {
+			return err
+		}
+		if err := child.Render(ctx, w); err != nil {
+			return err
+		}
+	}
+	fmt.Fprint(w, "</section>")
+	return nil
+}
+
+func TestRendererFromContext(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSimpleCounter](registry, "counter")
+	components.Register[*TestDashboardComponent](registry, "dashboard")
+
+	t.Run("dashboard renders embedded counters", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/dashboard?counters=1&counters=2", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("dashboard")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<section><div>1</div><div>2</div></section>", w.Body.String())
+	})
+
+	t.Run("unknown child component fails the render", func(t *testing.T) {
+		other := components.NewRegistry()
+		components.Register[*TestDashboardComponent](other, "dashboard")
+
+		req := httptest.NewRequest(http.MethodGet, "/component/dashboard?counters=1", nil)
+		w := httptest.NewRecorder()
+		other.HandlerFor("dashboard")(w, req)
+
+		assert.Contains(t, w.Body.String(), "Render Error")
+		assert.Contains(t, w.Body.String(), "not found")
+	})
+
+	t.Run("no renderer outside a handler", func(t *testing.T) {
+		renderer, ok := components.RendererFromContext(context.Background())
+		assert.False(t, ok)
+		assert.Nil(t, renderer)
+	})
+}
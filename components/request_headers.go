@@ -1,5 +1,10 @@
 package components
 
+import (
+	"net/http"
+	"net/url"
+)
+
 // HxBoosted is implemented by structs that want to receive the HX-Boosted header value.
 // This header indicates whether the request was made via an element with hx-boost="true".
 type HxBoosted interface {
@@ -36,14 +41,52 @@ type HxTrigger interface {
 	SetHxTrigger(string)
 }
 
+// HxTriggerJSON is implemented by structs that want a structured HX-Trigger header
+// value. When the header holds a JSON object it is unmarshalled and passed to
+// SetHxTriggerJSON; otherwise, e.g. for the usual element id, SetHxTriggerJSON is
+// not called. HxTrigger still receives the raw value either way.
+type HxTriggerJSON interface {
+	SetHxTriggerJSON(map[string]any)
+}
+
 // HxTriggerName is implemented by structs that want to receive the HX-Trigger-Name header value.
 // This is the name of the element that triggered the request, if it exists.
 type HxTriggerName interface {
 	SetHxTriggerName(string)
 }
 
-// HttpMethod is implemented by structs that want to receive the HTTP method (GET or POST).
+// TriggerInfo identifies the element that triggered a request.
+type TriggerInfo struct {
+	ID   string // From the HX-Trigger header: the id of the triggering element
+	Name string // From the HX-Trigger-Name header: the name of the triggering element
+}
+
+// HxTriggerInfo is implemented by structs that want the HX-Trigger and HX-Trigger-Name
+// header values together, e.g. to check which button submitted a form.
+type HxTriggerInfo interface {
+	SetHxTriggerInfo(TriggerInfo)
+}
+
+// HttpMethod is implemented by structs that want to receive the HTTP method (GET or POST,
+// or the PUT, PATCH or DELETE a POST asks for when EnableMethodOverride is used).
 // This allows components to vary behavior based on whether they were loaded via GET or submitted via POST.
 type HttpMethod interface {
 	SetHttpMethod(string)
 }
+
+// RequestAware is implemented by structs that want a reference to the raw request.
+// Use it to read cookies, the remote address, or custom headers that aren't covered
+// by the HX-* interfaces. The request should be treated as read-only; its body has
+// usually already been consumed by form parsing, so use the decoded fields instead.
+type RequestAware interface {
+	SetRequest(*http.Request)
+}
+
+// RawParamsAware is implemented by structs that need to tell query string
+// parameters apart from body parameters, e.g. pagination in the URL and a form in
+// the body. Decoding merges both, so SetRawParams receives them separately:
+// query holds the URL's query parameters and body the parsed form body, which is
+// empty for GET requests and for components registered WithoutFormParsing.
+type RawParamsAware interface {
+	SetRawParams(query, body url.Values)
+}
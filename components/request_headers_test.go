@@ -0,0 +1,158 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRequestAwareComponent reflects a cookie and a custom header from the raw request
+type TestRequestAwareComponent struct {
+	req *http.Request
+}
+
+func (c *TestRequestAwareComponent) SetRequest(req *http.Request) {
+	c.req = req
+}
+
+func (c *TestRequestAwareComponent) Render(ctx context.Context, w io.Writer) error {
+	theme := "light"
+	if cookie, err := c.req.Cookie("theme"); err == nil {
+		theme = cookie.Value
+	}
+	fmt.Fprintf(w, "<div>theme=%s tenant=%s</div>", theme, c.req.Header.Get("X-Tenant"))
+	return nil
+}
+
+func TestRequestAware(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestRequestAwareComponent](registry, "prefs")
+
+	req := httptest.NewRequest(http.MethodGet, "/component/prefs", nil)
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	req.Header.Set("X-Tenant", "acme")
+	w := httptest.NewRecorder()
+	registry.HandlerFor("prefs")(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "<div>theme=dark tenant=acme</div>", w.Body.String())
+}
+
+// TestTriggerInfoComponent reports which element triggered the request
+type TestTriggerInfoComponent struct {
+	Trigger components.TriggerInfo
+}
+
+func (c *TestTriggerInfoComponent) SetHxTriggerInfo(info components.TriggerInfo) {
+	c.Trigger = info
+}
+
+func (c *TestTriggerInfoComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>id=%s name=%s</div>", c.Trigger.ID, c.Trigger.Name)
+	return nil
+}
+
+func TestHxTriggerInfo(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestTriggerInfoComponent](registry, "trigger")
+
+	req := httptest.NewRequest(http.MethodPost, "/component/trigger", nil)
+	req.Header.Set("HX-Trigger", "save-btn")
+	req.Header.Set("HX-Trigger-Name", "save")
+	w := httptest.NewRecorder()
+	registry.HandlerFor("trigger")(w, req)
+
+	assert.Equal(t, "<div>id=save-btn name=save</div>", w.Body.String())
+}
+
+// TestTriggerJSONComponent reports the raw and the parsed HX-Trigger header
+type TestTriggerJSONComponent struct {
+	Trigger string
+	Detail  map[string]any
+}
+
+func (c *TestTriggerJSONComponent) SetHxTrigger(trigger string) {
+	c.Trigger = trigger
+}
+
+func (c *TestTriggerJSONComponent) SetHxTriggerJSON(detail map[string]any) {
+	c.Detail = detail
+}
+
+func (c *TestTriggerJSONComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>trigger=%s detail=%v</div>", c.Trigger, c.Detail)
+	return nil
+}
+
+func TestHxTriggerJSON(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestTriggerJSONComponent](registry, "trigger-json")
+
+	render := func(trigger string) string {
+		req := httptest.NewRequest(http.MethodPost, "/component/trigger-json", nil)
+		req.Header.Set("HX-Trigger", trigger)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("trigger-json")(w, req)
+		return w.Body.String()
+	}
+
+	t.Run("JSON trigger populates the map", func(t *testing.T) {
+		assert.Equal(t, `<div>trigger={"row":3,"action":"edit"} detail=map[action:edit row:3]</div>`,
+			render(`{"row":3,"action":"edit"}`))
+	})
+
+	t.Run("plain id leaves the map empty", func(t *testing.T) {
+		assert.Equal(t, "<div>trigger=save-btn detail=map[]</div>", render("save-btn"))
+	})
+
+	t.Run("JSON that isn't an object leaves the map empty", func(t *testing.T) {
+		assert.Equal(t, `<div>trigger=["a"] detail=map[]</div>`, render(`["a"]`))
+		assert.Equal(t, "<div>trigger=null detail=map[]</div>", render("null"))
+	})
+}
+
+// TestRawParamsComponent reports its query and body parameters separately
+type TestRawParamsComponent struct {
+	Query url.Values
+	Body  url.Values
+}
+
+func (c *TestRawParamsComponent) SetRawParams(query, body url.Values) {
+	c.Query = query
+	c.Body = body
+}
+
+func (c *TestRawParamsComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>query=%s body=%s</div>", c.Query.Encode(), c.Body.Encode())
+	return nil
+}
+
+func TestRawParamsAware(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestRawParamsComponent](registry, "params")
+
+	t.Run("POST separates query and body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/component/params?page=2", strings.NewReader("name=widget&page=5"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("params")(w, req)
+
+		assert.Equal(t, "<div>query=page=2 body=name=widget&page=5</div>", w.Body.String())
+	})
+
+	t.Run("GET has an empty body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/params?page=2", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("params")(w, req)
+
+		assert.Equal(t, "<div>query=page=2 body=</div>", w.Body.String())
+	})
+}
@@ -0,0 +1,36 @@
+package components
+
+import "context"
+
+// RequestInfo describes the component request being handled. HandlerFor stores it
+// in the request context before any lifecycle method runs, so Process, event
+// handlers and hooks can tell which event triggered them.
+type RequestInfo struct {
+	ComponentName string
+	// EventName is the requested event, or empty if the request has none.
+	EventName string
+	Method    string
+}
+
+type requestInfoKey struct{}
+
+// withRequestInfo returns a copy of ctx carrying info.
+func withRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the RequestInfo stored by HandlerFor and whether
+// one was present (it is not when a component is used outside a handler).
+//
+// Example:
+//
+//	func (c *Counter) Process(ctx context.Context) error {
+//	    if info, ok := components.RequestInfoFromContext(ctx); ok && info.EventName == "reset" {
+//	        c.audit("reset")
+//	    }
+//	    return nil
+//	}
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
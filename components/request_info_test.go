@@ -0,0 +1,63 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInfoComponent reports the RequestInfo seen by Process
+type TestInfoComponent struct {
+	Info  components.RequestInfo `json:"-"`
+	Found bool                   `json:"-"`
+}
+
+func (c *TestInfoComponent) OnSave(ctx context.Context) error {
+	return nil
+}
+
+func (c *TestInfoComponent) Process(ctx context.Context) error {
+	c.Info, c.Found = components.RequestInfoFromContext(ctx)
+	return nil
+}
+
+func (c *TestInfoComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "%t %s %s %s", c.Found, c.Info.ComponentName, c.Info.Method, c.Info.EventName)
+	return nil
+}
+
+func TestRequestInfoFromContext(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestInfoComponent](registry, "info")
+
+	t.Run("event request", func(t *testing.T) {
+		form := url.Values{"hxc-event": {"save"}}
+		req := httptest.NewRequest(http.MethodPost, "/component/info", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("info")(w, req)
+
+		assert.Equal(t, "true info POST save", w.Body.String())
+	})
+
+	t.Run("plain GET", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/component/info", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("info")(w, req)
+
+		assert.Equal(t, "true info GET ", w.Body.String())
+	})
+
+	t.Run("absent outside a handler", func(t *testing.T) {
+		_, ok := components.RequestInfoFromContext(context.Background())
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,117 @@
+package components
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// WithRequestLogging logs one info-level summary line per request for the
+// component, using the registry's logger: the component, method, event, status,
+// duration and bytes written, plus the lifecycle phase that failed, if any:
+//
+//	level=INFO msg="component request" component=counter method=POST event=increment status=200 duration=1.2ms bytes=48
+//
+// SetRequestLogging enables it for every component. The other request details
+// stay at debug level.
+func WithRequestLogging() RegisterOption {
+	return func(e *componentEntry) {
+		e.requestLogging = true
+	}
+}
+
+// SetRequestLogging enables or disables the request summary line of
+// WithRequestLogging for every component of the registry. Components registered
+// with WithRequestLogging are logged either way.
+func (r *Registry) SetRequestLogging(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requestLogging = enabled
+}
+
+// requestLoggingFor reports whether requests for the named component are logged.
+func (r *Registry) requestLoggingFor(componentName string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.requestLogging || r.components[componentName].requestLogging
+}
+
+// requestSummary records what is logged about a request: it wraps the
+// ResponseWriter to capture the status and the bytes written, and the lifecycle
+// observer to capture the phase that failed.
+type requestSummary struct {
+	http.ResponseWriter
+	start       time.Time
+	method      string
+	event       string
+	status      int
+	bytes       int
+	failedPhase string
+	observer    LifecycleObserver
+}
+
+func (s *requestSummary) WriteHeader(code int) {
+	if s.status == 0 {
+		s.status = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *requestSummary) Write(p []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(p)
+	s.bytes += n
+	return n, err
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (s *requestSummary) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// observe returns a LifecycleObserver that records the first failed phase and
+// passes every notification on to next, if any.
+func (s *requestSummary) observe(next LifecycleObserver) LifecycleObserver {
+	s.observer = next
+	return s
+}
+
+// PhaseStart implements LifecycleObserver.
+func (s *requestSummary) PhaseStart(ctx context.Context, component, phase string) {
+	if s.observer != nil {
+		s.observer.PhaseStart(ctx, component, phase)
+	}
+}
+
+// PhaseEnd implements LifecycleObserver.
+func (s *requestSummary) PhaseEnd(ctx context.Context, component, phase string, err error) {
+	if err != nil && s.failedPhase == "" {
+		s.failedPhase = phase
+	}
+	if s.observer != nil {
+		s.observer.PhaseEnd(ctx, component, phase, err)
+	}
+}
+
+// log writes the summary line for a finished request.
+func (s *requestSummary) log(logger *slog.Logger, componentName string) {
+	status := s.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	attrs := []any{
+		"component", componentName,
+		"method", s.method,
+		"event", s.event,
+		"status", status,
+		"duration", time.Since(s.start),
+		"bytes", s.bytes,
+	}
+	if s.failedPhase != "" {
+		attrs = append(attrs, "failed_phase", s.failedPhase)
+	}
+	logger.Info("component request", attrs...)
+}
@@ -0,0 +1,52 @@
+package components
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Resettable is an optional interface for components that need custom logic to
+// return to their initial state, e.g. to keep an injected dependency while
+// clearing request data. ResetComponent calls Reset when it is implemented.
+//
+// HandlerFor builds a new instance for every request (via WithInitialState or
+// WithFactory, if registered), so components never carry state from one request to
+// the next and stateless components don't need Reset. It matters when a test
+// reuses one instance across several SimulateEvent calls.
+//
+// Example:
+//
+//	func (c *TodoList) Reset() {
+//	    *c = TodoList{Repo: c.Repo}
+//	}
+type Resettable interface {
+	Reset()
+}
+
+// ResetComponent returns a component to its initial state so it can be reused,
+// for instance between SimulateEvent calls in a table-driven test. It calls Reset
+// if the component implements Resettable, and otherwise sets every field to its
+// zero value via reflection. component must be a non-nil pointer to a struct.
+//
+// Example usage:
+//
+//	counter := &CounterComponent{}
+//	for _, tt := range tests {
+//	    components.ResetComponent(counter)
+//	    counter.Count = tt.start
+//	    require.NoError(t, components.SimulateEvent(ctx, counter, "increment"))
+//	    assert.Equal(t, tt.want, counter.Count)
+//	}
+func ResetComponent(component interface{}) error {
+	if resettable, ok := component.(Resettable); ok {
+		resettable.Reset()
+		return nil
+	}
+
+	v := reflect.ValueOf(component)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("component must be a non-nil pointer to a struct, got %T", component)
+	}
+	v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	return nil
+}
@@ -0,0 +1,79 @@
+package components_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResettableComponent keeps its Owner across resets
+type TestResettableComponent struct {
+	Owner string
+	Items []string
+}
+
+func (c *TestResettableComponent) Reset() {
+	*c = TestResettableComponent{Owner: c.Owner}
+}
+
+func (c *TestResettableComponent) OnAdd(ctx context.Context) error {
+	c.Items = append(c.Items, "item")
+	return nil
+}
+
+func (c *TestResettableComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, strings.Join(c.Items, ","))
+	return err
+}
+
+func TestResetComponent(t *testing.T) {
+	t.Run("zeroes a reused component without Reset", func(t *testing.T) {
+		component := &TestEventComponent{}
+		require.NoError(t, components.SimulateEvent(context.Background(), component, "increment"))
+		require.NotEmpty(t, component.EventsHistory)
+
+		require.NoError(t, components.ResetComponent(component))
+		assert.Equal(t, TestEventComponent{}, *component)
+
+		require.NoError(t, components.SimulateEvent(context.Background(), component, "increment"))
+		assert.Equal(t, []string{"BeforeEvent:increment", "OnIncrement", "AfterEvent:increment", "Process"}, component.EventsHistory)
+	})
+
+	t.Run("runs a custom Reset", func(t *testing.T) {
+		component := &TestResettableComponent{Owner: "alice"}
+		require.NoError(t, components.SimulateEvent(context.Background(), component, "add"))
+
+		require.NoError(t, components.ResetComponent(component))
+		assert.Equal(t, "alice", component.Owner)
+		assert.Empty(t, component.Items)
+	})
+
+	t.Run("rejects non-struct pointers", func(t *testing.T) {
+		assert.Error(t, components.ResetComponent(nil))
+		assert.Error(t, components.ResetComponent(TestEventComponent{}))
+		assert.Error(t, components.ResetComponent((*TestEventComponent)(nil)))
+	})
+
+	t.Run("registry never carries slice state between requests", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestEventComponent](registry, "counter")
+
+		for i := 0; i < 2; i++ {
+			body := url.Values{"hxc-event": {"increment"}}
+			req := httptest.NewRequest(http.MethodPost, "/component/counter", strings.NewReader(body.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+			registry.HandlerFor("counter")(w, req)
+
+			assert.Equal(t, "<div>Count: 1, History: [BeforeEvent:increment OnIncrement AfterEvent:increment Process Render]</div>", w.Body.String())
+		}
+	})
+}
@@ -1,5 +1,7 @@
 package components
 
+import "net/http"
+
 // HxLocationResponse is implemented by structs that want to set the HX-Location response header.
 // This allows you to do a client-side redirect that doesn't do a full page reload.
 type HxLocationResponse interface {
@@ -12,6 +14,14 @@ type HxPushUrlResponse interface {
 	GetHxPushUrl() string
 }
 
+// HxPushUrlOptResponse is like HxPushUrlResponse, but can also send the literal
+// "false" to prevent a history update. The header is set to the returned value
+// whenever ok is true (use "false" to disable pushing) and omitted otherwise.
+// It takes precedence over HxPushUrlResponse.
+type HxPushUrlOptResponse interface {
+	GetHxPushUrlOpt() (value string, ok bool)
+}
+
 // HxRedirectResponse is implemented by structs that want to set the HX-Redirect response header.
 // This does a client-side redirect to a new location.
 type HxRedirectResponse interface {
@@ -30,6 +40,14 @@ type HxReplaceUrlResponse interface {
 	GetHxReplaceUrl() string
 }
 
+// HxReplaceUrlOptResponse is like HxReplaceUrlResponse, but can also send the literal
+// "false" to prevent a history update. The header is set to the returned value
+// whenever ok is true (use "false" to disable replacing) and omitted otherwise.
+// It takes precedence over HxReplaceUrlResponse.
+type HxReplaceUrlOptResponse interface {
+	GetHxReplaceUrlOpt() (value string, ok bool)
+}
+
 // HxReswapResponse is implemented by structs that want to set the HX-Reswap response header.
 // This allows you to specify how the response will be swapped (innerHTML, outerHTML, etc.).
 type HxReswapResponse interface {
@@ -65,3 +83,17 @@ type HxTriggerAfterSettleResponse interface {
 type HxTriggerAfterSwapResponse interface {
 	GetHxTriggerAfterSwap() string
 }
+
+// CookieResponse is implemented by structs that want to set response cookies.
+// Each returned cookie is added with a Set-Cookie header (e.g. to establish a session after login).
+type CookieResponse interface {
+	ResponseCookies() []*http.Cookie
+}
+
+// VaryProvider is implemented by structs whose output depends on request headers
+// other than the form data, e.g. Accept-Language or Cookie. The returned header
+// names are added to the Vary response header so HTTP caches keep separate copies.
+// HX-Request is added automatically for components that implement HxRequest.
+type VaryProvider interface {
+	Vary() []string
+}
@@ -0,0 +1,335 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSessionLogin establishes a session cookie after a successful login
+type TestSessionLogin struct {
+	Username  string `form:"username"`
+	Password  string `form:"password"`
+	SessionID string `json:"-"`
+}
+
+func (c *TestSessionLogin) Process(ctx context.Context) error {
+	if c.Username == "demo" && c.Password == "password" {
+		c.SessionID = "session-123"
+	}
+	return nil
+}
+
+func (c *TestSessionLogin) ResponseCookies() []*http.Cookie {
+	if c.SessionID == "" {
+		return nil
+	}
+	return []*http.Cookie{{
+		Name:     "session",
+		Value:    c.SessionID,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	}}
+}
+
+func (c *TestSessionLogin) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>%s</div>", c.Username)
+	return nil
+}
+
+func TestCookieResponse(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSessionLogin](registry, "login")
+
+	post := func(username, password string) *httptest.ResponseRecorder {
+		form := url.Values{"username": {username}, "password": {password}}
+		req := httptest.NewRequest(http.MethodPost, "/component/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("login")(w, req)
+		return w
+	}
+
+	t.Run("sets the session cookie", func(t *testing.T) {
+		w := post("demo", "password")
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, "session", cookies[0].Name)
+		assert.Equal(t, "session-123", cookies[0].Value)
+		assert.Equal(t, "/", cookies[0].Path)
+		assert.True(t, cookies[0].HttpOnly)
+		assert.True(t, cookies[0].Secure)
+		assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+	})
+
+	t.Run("sets no cookie when none are returned", func(t *testing.T) {
+		w := post("demo", "wrong")
+		assert.Empty(t, w.Header().Values("Set-Cookie"))
+	})
+}
+
+// TestStructuredTriggerComponent sets HX-Trigger from a *Trigger chosen by its mode
+type TestStructuredTriggerComponent struct {
+	Mode string `form:"mode"`
+}
+
+func (c *TestStructuredTriggerComponent) GetHxTrigger() string {
+	return "plainTrigger"
+}
+
+func (c *TestStructuredTriggerComponent) GetHxStructuredTrigger() *components.Trigger {
+	switch c.Mode {
+	case "detail":
+		return components.NewTrigger("itemAdded").WithDetail(map[string]int{"id": 7})
+	case "name":
+		return components.NewTrigger("refresh")
+	}
+	return nil
+}
+
+func (c *TestStructuredTriggerComponent) Render(ctx context.Context, w io.Writer) error {
+	return nil
+}
+
+func TestTriggerHeaderValue(t *testing.T) {
+	t.Run("without detail", func(t *testing.T) {
+		value, err := components.NewTrigger("itemAdded").HeaderValue()
+		require.NoError(t, err)
+		assert.Equal(t, "itemAdded", value)
+	})
+
+	t.Run("with detail", func(t *testing.T) {
+		value, err := components.NewTrigger("itemAdded").WithDetail(map[string]any{"id": 7, "text": "milk"}).HeaderValue()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"itemAdded":{"id":7,"text":"milk"}}`, value)
+	})
+
+	t.Run("with scalar detail", func(t *testing.T) {
+		value, err := components.NewTrigger("showMessage").WithDetail("Saved!").HeaderValue()
+		require.NoError(t, err)
+		assert.Equal(t, `{"showMessage":"Saved!"}`, value)
+	})
+
+	t.Run("unencodable detail", func(t *testing.T) {
+		_, err := components.NewTrigger("bad").WithDetail(make(chan int)).HeaderValue()
+		assert.Error(t, err)
+	})
+}
+
+func TestHxStructuredTriggerResponse(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestStructuredTriggerComponent](registry, "trigger")
+
+	tests := []struct {
+		name     string
+		mode     string
+		expected string
+	}{
+		{"detail payload takes precedence", "detail", `{"itemAdded":{"id":7}}`},
+		{"plain name", "name", "refresh"},
+		{"nil falls back to HxTriggerResponse", "", "plainTrigger"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/component/trigger?mode="+tt.mode, nil)
+			w := httptest.NewRecorder()
+			registry.HandlerFor("trigger")(w, req)
+
+			assert.Equal(t, tt.expected, w.Header().Get("HX-Trigger"))
+		})
+	}
+}
+
+func TestTriggerEventsHeaderValue(t *testing.T) {
+	t.Run("names only", func(t *testing.T) {
+		value, err := components.TriggerEvents{
+			components.NewTrigger("itemAdded"),
+			nil,
+			components.NewTrigger("refreshTotals"),
+		}.HeaderValue()
+		require.NoError(t, err)
+		assert.Equal(t, "itemAdded, refreshTotals", value)
+	})
+
+	t.Run("with details", func(t *testing.T) {
+		value, err := components.TriggerEvents{
+			components.NewTrigger("itemAdded").WithDetail(map[string]any{"id": 7}),
+			components.NewTrigger("refreshTotals"),
+		}.HeaderValue()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"itemAdded":{"id":7},"refreshTotals":null}`, value)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		value, err := components.TriggerEvents{}.HeaderValue()
+		require.NoError(t, err)
+		assert.Empty(t, value)
+	})
+
+	t.Run("unencodable detail", func(t *testing.T) {
+		_, err := components.TriggerEvents{components.NewTrigger("bad").WithDetail(make(chan int))}.HeaderValue()
+		assert.Error(t, err)
+	})
+}
+
+// TestTriggerEventsComponent sends structured events at all three timings
+type TestTriggerEventsComponent struct{}
+
+func (c *TestTriggerEventsComponent) GetHxTriggerEvents() components.TriggerEvents {
+	return components.TriggerEvents{components.NewTrigger("itemAdded").WithDetail(map[string]any{"id": 7})}
+}
+
+func (c *TestTriggerEventsComponent) GetHxTriggerAfterSettle() string {
+	return "plainSettle"
+}
+
+func (c *TestTriggerEventsComponent) GetHxTriggerAfterSettleEvents() components.TriggerEvents {
+	return components.TriggerEvents{
+		components.NewTrigger("highlight").WithDetail("#row-7"),
+		components.NewTrigger("focusInput"),
+	}
+}
+
+func (c *TestTriggerEventsComponent) GetHxTriggerAfterSwapEvents() components.TriggerEvents {
+	return components.TriggerEvents{components.NewTrigger("scrollToBottom")}
+}
+
+func (c *TestTriggerEventsComponent) Render(ctx context.Context, w io.Writer) error {
+	return nil
+}
+
+func TestHxTriggerEventsResponses(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestTriggerEventsComponent](registry, "events")
+
+	req := httptest.NewRequest(http.MethodGet, "/component/events", nil)
+	w := httptest.NewRecorder()
+	registry.HandlerFor("events")(w, req)
+
+	assert.JSONEq(t, `{"itemAdded":{"id":7}}`, w.Header().Get("HX-Trigger"))
+	assert.JSONEq(t, `{"highlight":"#row-7","focusInput":null}`, w.Header().Get("HX-Trigger-After-Settle"))
+	assert.Equal(t, "scrollToBottom", w.Header().Get("HX-Trigger-After-Swap"))
+}
+
+// TestHistoryComponent controls history updates with the opt-in header interfaces
+type TestHistoryComponent struct {
+	Push    string `form:"push"`
+	Replace string `form:"replace"`
+}
+
+func (c *TestHistoryComponent) GetHxPushUrlOpt() (string, bool) {
+	return c.Push, c.Push != ""
+}
+
+func (c *TestHistoryComponent) GetHxReplaceUrlOpt() (string, bool) {
+	return c.Replace, c.Replace != ""
+}
+
+func (c *TestHistoryComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, "<div>history</div>")
+	return err
+}
+
+func TestHistoryOptHeaders(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestHistoryComponent](registry, "history")
+
+	get := func(query string) http.Header {
+		req := httptest.NewRequest(http.MethodGet, "/component/history?"+query, nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("history")(w, req)
+		return w.Header()
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		wantPush    []string
+		wantReplace []string
+	}{
+		{"omitted when not set", "", nil, nil},
+		{"set to a URL", "push=/items/2&replace=/items", []string{"/items/2"}, []string{"/items"}},
+		{"set to false", "push=false&replace=false", []string{"false"}, []string{"false"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := get(tt.query)
+			assert.Equal(t, tt.wantPush, headers.Values("HX-Push-Url"))
+			assert.Equal(t, tt.wantReplace, headers.Values("HX-Replace-Url"))
+		})
+	}
+}
+
+// TestVaryComponent renders differently per language and HTMX request
+type TestVaryComponent struct {
+	IsHTMX bool `json:"-"`
+}
+
+func (c *TestVaryComponent) SetHxRequest(v bool) {
+	c.IsHTMX = v
+}
+
+func (c *TestVaryComponent) Vary() []string {
+	return []string{"Accept-Language", "hx-request", "Cookie"}
+}
+
+func (c *TestVaryComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "<div>%t</div>", c.IsHTMX)
+	return err
+}
+
+func TestVaryProvider(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestVaryComponent](registry, "vary")
+	components.Register[*TestSimpleCounter](registry, "counter")
+
+	t.Run("declared and automatic headers are listed once", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		registry.HandlerFor("vary")(w, httptest.NewRequest(http.MethodGet, "/component/vary", nil))
+
+		assert.Equal(t, []string{"HX-Request", "Accept-Language", "Cookie"}, w.Header().Values("Vary"))
+	})
+
+	t.Run("HX-Request is added for HxRequest components", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestHxRequestOnly](registry, "only")
+
+		w := httptest.NewRecorder()
+		registry.HandlerFor("only")(w, httptest.NewRequest(http.MethodGet, "/component/only", nil))
+
+		assert.Equal(t, []string{"HX-Request"}, w.Header().Values("Vary"))
+	})
+
+	t.Run("no Vary header otherwise", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, httptest.NewRequest(http.MethodGet, "/component/counter", nil))
+
+		assert.Empty(t, w.Header().Values("Vary"))
+	})
+}
+
+// TestHxRequestOnly receives HX-Request without declaring Vary
+type TestHxRequestOnly struct {
+	IsHTMX bool `json:"-"`
+}
+
+func (c *TestHxRequestOnly) SetHxRequest(v bool) {
+	c.IsHTMX = v
+}
+
+func (c *TestHxRequestOnly) Render(ctx context.Context, w io.Writer) error {
+	return nil
+}
@@ -0,0 +1,124 @@
+package components
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SanitizePolicy rewrites a submitted string value, e.g. to remove markup.
+// The Sanitize method of a bluemonday policy is a SanitizePolicy:
+//
+//	registry.SetSanitizer(bluemonday.StrictPolicy().Sanitize)
+type SanitizePolicy func(string) string
+
+// SetSanitizer sets the policy applied to the string fields of components
+// registered with WithSanitize. Passing nil restores StripTags.
+func (r *Registry) SetSanitizer(policy SanitizePolicy) {
+	if policy == nil {
+		policy = StripTags
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sanitizer = policy
+}
+
+// StripTags is the default SanitizePolicy. It removes every HTML tag, comment and
+// the contents of script and style elements, keeping the remaining text as is.
+// It is a strict, text-only policy; use a dedicated HTML sanitizer such as
+// bluemonday when some markup must be kept.
+func StripTags(s string) string {
+	if !strings.Contains(s, "<") {
+		return s
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(s, '<')
+		if start < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		if start+1 >= len(s) || !isTagStart(s[start+1]) {
+			b.WriteString(s[:start+1])
+			s = s[start+1:]
+			continue
+		}
+		b.WriteString(s[:start])
+		s = s[start:]
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				return b.String()
+			}
+			s = s[end+len("-->"):]
+			continue
+		}
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			return b.String()
+		}
+		tag := strings.ToLower(s[1:end])
+		s = s[end+1:]
+		for _, raw := range []string{"script", "style"} {
+			if tagName(tag) == raw {
+				closing := strings.Index(strings.ToLower(s), "</"+raw)
+				if closing < 0 {
+					return b.String()
+				}
+				s = s[closing:]
+			}
+		}
+	}
+}
+
+// isTagStart reports whether c can follow '<' at the start of a tag, comment or
+// declaration.
+func isTagStart(c byte) bool {
+	return c == '/' || c == '!' || c == '?' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// tagName returns the element name of an opening tag's contents, e.g. "script"
+// for `script type="module"`.
+func tagName(tag string) string {
+	if i := strings.IndexAny(tag, " \t\n\r\f/"); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+// sanitizeFields applies policy to every string and []string field of the struct
+// that instance points to, walking embedded and nested structs, pointers to
+// structs and slices of structs. Fields tagged `form:"-"` are not decoded from the
+// form and are left alone.
+func sanitizeFields(instance any, policy SanitizePolicy) {
+	v := reflect.ValueOf(instance)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return
+	}
+	sanitizeValue(v.Elem(), policy)
+}
+
+func sanitizeValue(v reflect.Value, policy SanitizePolicy) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(policy(v.String()))
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			sanitizeValue(v.Elem(), policy)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			sanitizeValue(v.Index(i), policy)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("form") == "-" {
+				continue
+			}
+			sanitizeValue(v.Field(i), policy)
+		}
+	}
+}
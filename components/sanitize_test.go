@@ -0,0 +1,92 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCommentComponent stores user input that is echoed back unescaped
+type TestCommentComponent struct {
+	Author  string   `form:"author"`
+	Body    string   `form:"body"`
+	Tags    []string `form:"tags"`
+	Address struct {
+		City string `form:"city"`
+	} `form:"address"`
+}
+
+func (c *TestCommentComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s|%s|%s|%s", c.Author, c.Body, strings.Join(c.Tags, ","), c.Address.City)
+	return err
+}
+
+func TestSanitize(t *testing.T) {
+	values := url.Values{
+		"author":       {"<b>alice</b>"},
+		"body":         {`hello<script>alert("x")</script> world`},
+		"tags":         {"<img src=x onerror=alert(1)>go", "1 < 2"},
+		"address.city": {"<!-- c -->Paris"},
+	}
+	post := func(registry *components.Registry, name string) string {
+		req := httptest.NewRequest(http.MethodPost, "/component/"+name, strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor(name)(w, req)
+		return w.Body.String()
+	}
+
+	t.Run("markup is stripped with the option on", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestCommentComponent](registry, "comment", components.WithSanitize())
+
+		body := post(registry, "comment")
+		assert.Equal(t, "alice|hello world|go,1 < 2|Paris", body)
+		assert.NotContains(t, body, "<script>")
+	})
+
+	t.Run("values are preserved with the option off", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestCommentComponent](registry, "comment")
+
+		assert.Contains(t, post(registry, "comment"), `hello<script>alert("x")</script> world`)
+	})
+
+	t.Run("custom policy", func(t *testing.T) {
+		registry := components.NewRegistry()
+		registry.SetSanitizer(strings.ToUpper)
+		components.Register[*TestCommentComponent](registry, "comment", components.WithSanitize())
+
+		assert.Contains(t, post(registry, "comment"), "<B>ALICE</B>")
+	})
+}
+
+func TestStripTags(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"plain text", "plain text"},
+		{"<p>Hello <em>there</em></p>", "Hello there"},
+		{`a<script type="module">document.cookie</script>b`, "ab"},
+		{"a<STYLE>body{}</STYLE>b", "ab"},
+		{"x<!-- hidden -->y", "xy"},
+		{"1 < 2 and 3 > 2", "1 < 2 and 3 > 2"},
+		{"unterminated <script>alert(1)", "unterminated "},
+		{"broken <a href=", "broken "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, components.StripTags(tt.input))
+		})
+	}
+}
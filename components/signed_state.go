@@ -0,0 +1,138 @@
+package components
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoStateKey is returned by SignState and VerifyState when no key has been set
+// with SetStateKey.
+var ErrNoStateKey = errors.New("no state key set")
+
+// SignedStateComponent is an optional interface for components that keep their
+// state on the client, in a hidden form field, without letting the client change
+// it. The registry verifies the signed value of SignedStateField and decodes it
+// into SignedState after form decoding and before Init, and signs SignedState
+// again after events and Process succeed, passing the result to
+// SetSignedStateToken to be rendered back into the hidden field:
+//
+//	type TodoList struct {
+//	    Items []TodoItem `form:"-"`
+//	    Token string     `form:"-"`
+//	}
+//
+//	func (t *TodoList) SignedStateField() string        { return "state" }
+//	func (t *TodoList) SignedState() any                { return &t.Items }
+//	func (t *TodoList) SetSignedStateToken(token string) { t.Token = token }
+//
+//	<input type="hidden" name="state" value={ t.Token }/>
+//
+// A missing field leaves the state at its zero value; a field whose signature does
+// not match, that was signed for another component or that is older than the
+// state max age (see SetStateMaxAge) fails the request with a 400. Requires a key
+// set with SetStateKey. Signing prevents tampering but not reading: the state is
+// only encoded, so keep secrets out of it.
+type SignedStateComponent interface {
+	// SignedStateField returns the form field carrying the signed state.
+	SignedStateField() string
+	// SignedState returns a pointer to the state to verify into and sign.
+	SignedState() any
+	// SetSignedStateToken receives the signed state before Render.
+	SetSignedStateToken(token string)
+}
+
+// DefaultStateMaxAge is how long a signed state token stays valid.
+const DefaultStateMaxAge = 24 * time.Hour
+
+// SetStateKey sets the HMAC-SHA256 key used by SignState, VerifyState and
+// SignedStateComponent. Use a random key of at least 32 bytes shared by all
+// server instances; rotating it invalidates state already sent to clients.
+func (r *Registry) SetStateKey(key []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stateKey = append([]byte(nil), key...)
+}
+
+// SetStateMaxAge sets how long a signed state token is accepted after it was
+// signed (DefaultStateMaxAge unless set). Older tokens fail VerifyState, so a
+// client cannot replay state it captured long ago. Passing 0 disables the check.
+func (r *Registry) SetStateMaxAge(maxAge time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stateMaxAge = maxAge
+}
+
+// SignState JSON-encodes v and signs it with the registry's state key for the
+// named component, returning a URL-safe token for a hidden form field. The
+// signature covers the component name and the signing time, so the token is only
+// accepted by VerifyState for the same component and within the state max age.
+func (r *Registry) SignState(componentName string, v any) (string, error) {
+	key, _ := r.stateSigning()
+	if len(key) == 0 {
+		return "", ErrNoStateKey
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	issued := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	sig := signState(key, componentName, issued, payload)
+	return payload + "." + issued + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// VerifyState checks the signature of a token produced by SignState for the named
+// component and decodes its state into v. It returns an *ErrInvalidState if the
+// token is malformed, was changed, was signed for another component or has
+// expired.
+func (r *Registry) VerifyState(componentName, signed string, v any) error {
+	key, maxAge := r.stateSigning()
+	if len(key) == 0 {
+		return ErrNoStateKey
+	}
+	parts := strings.Split(signed, ".")
+	if len(parts) != 3 {
+		return &ErrInvalidState{Reason: "malformed token"}
+	}
+	payload, issued, sig := parts[0], parts[1], parts[2]
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, signState(key, componentName, issued, payload)) {
+		return &ErrInvalidState{Reason: "signature mismatch"}
+	}
+	issuedAt, err := strconv.ParseInt(issued, 10, 64)
+	if err != nil {
+		return &ErrInvalidState{Reason: "malformed timestamp"}
+	}
+	if maxAge > 0 && time.Since(time.UnixMilli(issuedAt)) > maxAge {
+		return &ErrInvalidState{Reason: "token expired"}
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return &ErrInvalidState{Reason: "malformed payload"}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return &ErrInvalidState{Reason: err.Error()}
+	}
+	return nil
+}
+
+// stateSigning returns the registry's state key and max age.
+func (r *Registry) stateSigning() ([]byte, time.Duration) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.stateKey, r.stateMaxAge
+}
+
+// signState returns the HMAC-SHA256 under key of the component name, the signing
+// time and the payload. The name is length-prefixed so no two inputs collide.
+func signState(key []byte, componentName, issued, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strconv.Itoa(len(componentName)) + ":" + componentName + "." + issued + "." + payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,151 @@
+package components_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCartState struct {
+	Items []string `json:"items"`
+	Total int      `json:"total"`
+}
+
+// TestSignedCartComponent keeps its cart in a signed hidden field
+type TestSignedCartComponent struct {
+	Item  string        `form:"item"`
+	Cart  testCartState `form:"-"`
+	Token string        `form:"-"`
+}
+
+func (c *TestSignedCartComponent) SignedStateField() string         { return "state" }
+func (c *TestSignedCartComponent) SignedState() any                 { return &c.Cart }
+func (c *TestSignedCartComponent) SetSignedStateToken(token string) { c.Token = token }
+
+func (c *TestSignedCartComponent) OnAdd(ctx context.Context) error {
+	c.Cart.Items = append(c.Cart.Items, c.Item)
+	c.Cart.Total++
+	return nil
+}
+
+func (c *TestSignedCartComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "%d %s|%s", c.Cart.Total, strings.Join(c.Cart.Items, ","), c.Token)
+	return nil
+}
+
+func TestSignState(t *testing.T) {
+	registry := components.NewRegistry()
+
+	t.Run("requires a key", func(t *testing.T) {
+		_, err := registry.SignState("cart", testCartState{})
+		assert.ErrorIs(t, err, components.ErrNoStateKey)
+	})
+
+	registry.SetStateKey([]byte("0123456789abcdef0123456789abcdef"))
+
+	t.Run("round-trips state", func(t *testing.T) {
+		signed, err := registry.SignState("cart", testCartState{Items: []string{"apple"}, Total: 1})
+		require.NoError(t, err)
+
+		var got testCartState
+		require.NoError(t, registry.VerifyState("cart", signed, &got))
+		assert.Equal(t, testCartState{Items: []string{"apple"}, Total: 1}, got)
+	})
+
+	t.Run("rejects a tampered payload", func(t *testing.T) {
+		signed, err := registry.SignState("cart", testCartState{Total: 1})
+		require.NoError(t, err)
+		_, sig, _ := strings.Cut(signed, ".")
+
+		other := components.NewRegistry()
+		other.SetStateKey([]byte("another key"))
+		forged, err := other.SignState("cart", testCartState{Total: 1000})
+		require.NoError(t, err)
+		payload, _, _ := strings.Cut(forged, ".")
+
+		var got testCartState
+		err = registry.VerifyState("cart", payload+"."+sig, &got)
+		var invalid *components.ErrInvalidState
+		assert.True(t, errors.As(err, &invalid))
+		assert.Error(t, registry.VerifyState("cart", forged, &got))
+		assert.Error(t, registry.VerifyState("cart", "not-a-token", &got))
+		assert.Equal(t, testCartState{}, got)
+	})
+
+	t.Run("rejects a token signed for another component", func(t *testing.T) {
+		signed, err := registry.SignState("wishlist", testCartState{Total: 1})
+		require.NoError(t, err)
+
+		var got testCartState
+		assert.EqualError(t, registry.VerifyState("cart", signed, &got), "invalid signed state: signature mismatch")
+	})
+
+	t.Run("rejects an expired token", func(t *testing.T) {
+		expiring := components.NewRegistry()
+		expiring.SetStateKey([]byte("0123456789abcdef0123456789abcdef"))
+		expiring.SetStateMaxAge(10 * time.Millisecond)
+		signed, err := expiring.SignState("cart", testCartState{Total: 1})
+		require.NoError(t, err)
+
+		var got testCartState
+		require.NoError(t, expiring.VerifyState("cart", signed, &got))
+		time.Sleep(20 * time.Millisecond)
+		assert.EqualError(t, expiring.VerifyState("cart", signed, &got), "invalid signed state: token expired")
+
+		expiring.SetStateMaxAge(0)
+		assert.NoError(t, expiring.VerifyState("cart", signed, &got))
+	})
+}
+
+func TestSignedStateComponent(t *testing.T) {
+	registry := components.NewRegistry()
+	registry.SetStateKey([]byte("0123456789abcdef0123456789abcdef"))
+	components.Register[*TestSignedCartComponent](registry, "cart")
+
+	post := func(values url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/cart", strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("cart")(w, req)
+		return w
+	}
+	token := func(body string) string {
+		_, token, _ := strings.Cut(body, "|")
+		return token
+	}
+
+	first := post(url.Values{"item": {"apple"}, "hxc-event": {"add"}})
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.True(t, strings.HasPrefix(first.Body.String(), "1 apple|"))
+
+	t.Run("carries verified state between requests", func(t *testing.T) {
+		w := post(url.Values{"item": {"pear"}, "hxc-event": {"add"}, "state": {token(first.Body.String())}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, strings.HasPrefix(w.Body.String(), "2 apple,pear|"))
+	})
+
+	t.Run("rejects tampered state", func(t *testing.T) {
+		signed := token(first.Body.String())
+		_, sig, _ := strings.Cut(signed, ".")
+		other := components.NewRegistry()
+		other.SetStateKey([]byte("attacker"))
+		forged, err := other.SignState("cart", map[string]any{"items": []string{"gold"}, "total": 99})
+		require.NoError(t, err)
+		payload, _, _ := strings.Cut(forged, ".")
+
+		w := post(url.Values{"item": {"pear"}, "hxc-event": {"add"}, "state": {payload + "." + sig}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.NotContains(t, w.Body.String(), "gold")
+	})
+}
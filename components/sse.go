@@ -0,0 +1,73 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// StreamComponent is an optional interface for components that push live updates
+// using Server-Sent Events (e.g. with the HTMX SSE extension).
+//
+// When a request's Accept header includes text/event-stream, the registry runs the
+// usual lifecycle (decode, Init, events, Process), sets the SSE response headers, and
+// calls Stream instead of rendering the component. Stream should write frames (see
+// WriteSSE), call flush after each one, and return when ctx is done. Returning
+// ctx.Err() after the client disconnects is treated as a normal end of stream.
+//
+// Example:
+//
+//	func (c *ClockComponent) Stream(ctx context.Context, w http.ResponseWriter, flush func()) error {
+//	    ticker := time.NewTicker(time.Second)
+//	    defer ticker.Stop()
+//	    for {
+//	        select {
+//	        case <-ctx.Done():
+//	            return ctx.Err()
+//	        case t := <-ticker.C:
+//	            if err := components.WriteSSE(w, "tick", t.Format(time.TimeOnly)); err != nil {
+//	                return err
+//	            }
+//	            flush()
+//	        }
+//	    }
+//	}
+//
+// Usage in HTMX:
+//
+//	<div hx-ext="sse" sse-connect="/component/clock" sse-swap="tick"></div>
+type StreamComponent interface {
+	Stream(ctx context.Context, w http.ResponseWriter, flush func()) error
+}
+
+// WriteSSE writes a single Server-Sent Events frame with the given event name and data.
+// The event line is omitted when event is empty. Multi-line data is split into
+// multiple data lines as required by the SSE format.
+func WriteSSE(w io.Writer, event, data string) error {
+	var sb strings.Builder
+	if event != "" {
+		fmt.Fprintf(&sb, "event: %s\n", event)
+	}
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(&sb, "data: %s\n", line)
+	}
+	sb.WriteString("\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// acceptsEventStream reports whether the request asks for a Server-Sent Events response.
+func acceptsEventStream(req *http.Request) bool {
+	return strings.Contains(req.Header.Get("Accept"), "text/event-stream")
+}
+
+// flusherFor returns a function that flushes w, or a no-op if w does not support
+// flushing. Wrapped writers are unwrapped via http.ResponseController.
+func flusherFor(w http.ResponseWriter) func() {
+	rc := http.NewResponseController(w)
+	return func() {
+		_ = rc.Flush()
+	}
+}
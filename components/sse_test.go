@@ -0,0 +1,108 @@
+package components_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamDone is closed when TestTickerComponent's stream ends
+var streamDone chan struct{}
+
+// TestTickerComponent streams numbered ticks until the client disconnects
+type TestTickerComponent struct {
+	Prefix string `form:"prefix"`
+}
+
+func (c *TestTickerComponent) Stream(ctx context.Context, w http.ResponseWriter, flush func()) error {
+	defer close(streamDone)
+	for i := 1; ; i++ {
+		if err := components.WriteSSE(w, "tick", fmt.Sprintf("%s%d", c.Prefix, i)); err != nil {
+			return err
+		}
+		flush()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func (c *TestTickerComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>%s ticker</div>", c.Prefix)
+	return nil
+}
+
+func TestStreamComponent(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestTickerComponent](registry, "ticker")
+	server := httptest.NewServer(registry.HandlerFor("ticker"))
+	defer server.Close()
+
+	t.Run("streams frames until the client cancels", func(t *testing.T) {
+		streamDone = make(chan struct{})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?prefix=n", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "text/event-stream")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+		reader := bufio.NewReader(resp.Body)
+		var lines []string
+		for len(lines) < 6 {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			lines = append(lines, strings.TrimRight(line, "\n"))
+		}
+		assert.Equal(t, []string{"event: tick", "data: n1", "", "event: tick", "data: n2", ""}, lines)
+
+		cancel()
+		select {
+		case <-streamDone:
+		case <-time.After(time.Second):
+			t.Fatal("stream did not end after the client cancelled")
+		}
+	})
+
+	t.Run("renders normally without event-stream Accept header", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "?prefix=n")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "text/html", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "<div>n ticker</div>", string(body))
+	})
+}
+
+func TestWriteSSE(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, components.WriteSSE(&buf, "update", "<p>one</p>\n<p>two</p>"))
+	assert.Equal(t, "event: update\ndata: <p>one</p>\ndata: <p>two</p>\n\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, components.WriteSSE(&buf, "", "hello"))
+	assert.Equal(t, "data: hello\n\n", buf.String())
+}
@@ -0,0 +1,190 @@
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// StatefulComponent is an optional interface for components whose state is kept on
+// the server instead of round-tripping through hidden form fields.
+//
+// StateKey identifies the stored state (e.g. per user, session or list). The registry
+// JSON-encodes the component and saves it in the registry's StateStore after events
+// and Process succeed, and loads it back into the next request's instance after form
+// decoding and before Init. Only fields that are JSON-encoded are stored, so tag
+// per-request inputs with `json:"-"` to keep stored state from overwriting them:
+//
+//	type TodoList struct {
+//	    ListID  string `form:"list" json:"-"`
+//	    NewText string `form:"text" json:"-"`
+//	    Items   []Item `json:"items"` // stored server-side
+//	}
+//
+//	func (t *TodoList) StateKey() string {
+//	    return "todos:" + t.ListID
+//	}
+//
+// Returning an empty key disables state handling for that request.
+type StatefulComponent interface {
+	StateKey() string
+}
+
+// StateStore persists encoded component state by key. Implementations must be safe
+// for concurrent use. Use Registry.SetStateStore to back state with a database,
+// cache or session store instead of the in-memory default.
+type StateStore interface {
+	// Load returns the state stored under key, or ok=false if there is none.
+	Load(ctx context.Context, key string) (data []byte, ok bool, err error)
+	// Save stores state under key, replacing any previous value.
+	Save(ctx context.Context, key string, data []byte) error
+}
+
+// MemoryStateStore is an in-memory StateStore. State lives for the life of the
+// process and is not shared between server instances.
+type MemoryStateStore struct {
+	mu    sync.RWMutex
+	state map[string][]byte
+}
+
+// NewMemoryStateStore creates an empty in-memory state store.
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{state: make(map[string][]byte)}
+}
+
+// Load returns the state stored under key.
+func (s *MemoryStateStore) Load(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.state[key]
+	return data, ok, nil
+}
+
+// Save stores state under key.
+func (s *MemoryStateStore) Save(ctx context.Context, key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state[key] = data
+	return nil
+}
+
+// SetStateStore sets the store used for StatefulComponent state.
+// Passing nil restores the in-memory default.
+func (r *Registry) SetStateStore(store StateStore) {
+	if store == nil {
+		store = NewMemoryStateStore()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stateStore = store
+}
+
+// loadState decodes the stored state for a StatefulComponent into it, if any.
+func loadState(ctx context.Context, store StateStore, component StatefulComponent) error {
+	key := component.StateKey()
+	if key == "" {
+		return nil
+	}
+	data, ok, err := store.Load(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to load state '%s': %w", key, err)
+	}
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(data, component); err != nil {
+		return fmt.Errorf("failed to decode state '%s': %w", key, err)
+	}
+	return nil
+}
+
+// saveState encodes a StatefulComponent and stores it under its state key.
+func saveState(ctx context.Context, store StateStore, component StatefulComponent) error {
+	key := component.StateKey()
+	if key == "" {
+		return nil
+	}
+	data, err := json.Marshal(component)
+	if err != nil {
+		return fmt.Errorf("failed to encode state '%s': %w", key, err)
+	}
+	if err := store.Save(ctx, key, data); err != nil {
+		return fmt.Errorf("failed to save state '%s': %w", key, err)
+	}
+	return nil
+}
+
+// loadComponentState runs the state phases that precede Init: it loads the
+// server-side state of a StatefulComponent from store and verifies the client-held
+// state of a SignedStateComponent from its field in values. See stateFailure for
+// the response to an error.
+func (r *Registry) loadComponentState(ctx context.Context, store StateStore, componentName string, instance any, values url.Values) error {
+	if stateful, ok := instance.(StatefulComponent); ok {
+		if err := loadState(ctx, store, stateful); err != nil {
+			return fmt.Errorf("component state could not be loaded: %w", err)
+		}
+	}
+	if signed, ok := instance.(SignedStateComponent); ok {
+		if token := values.Get(signed.SignedStateField()); token != "" {
+			if err := r.VerifyState(componentName, token, signed.SignedState()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// saveComponentState runs the state phases that follow Process: it saves the
+// server-side state of a StatefulComponent to store and signs the state of a
+// SignedStateComponent for rendering back to the client.
+func (r *Registry) saveComponentState(ctx context.Context, store StateStore, componentName string, instance any) error {
+	if stateful, ok := instance.(StatefulComponent); ok {
+		if err := saveState(ctx, store, stateful); err != nil {
+			return fmt.Errorf("component state could not be saved: %w", err)
+		}
+	}
+	if signed, ok := instance.(SignedStateComponent); ok {
+		token, err := r.SignState(componentName, signed.SignedState())
+		if err != nil {
+			return fmt.Errorf("component state could not be signed: %w", err)
+		}
+		signed.SetSignedStateToken(token)
+	}
+	return nil
+}
+
+// stateFailure returns the response title, message and status code for an error
+// from loadComponentState or saveComponentState: 400 Bad Request for signed state
+// the client changed or let expire, otherwise 500.
+func stateFailure(err error) (title, message string, code int) {
+	var invalid *ErrInvalidState
+	switch {
+	case errors.Is(err, ErrNoStateKey):
+		return "State Error", "Signed state requires SetStateKey", http.StatusInternalServerError
+	case errors.As(err, &invalid):
+		return "Bad Request", capitalize(err.Error()), http.StatusBadRequest
+	}
+	return "State Error", capitalize(err.Error()), http.StatusInternalServerError
+}
+
+// renderStateError logs and reports an error from loadComponentState or
+// saveComponentState and renders its response.
+func (r *Registry) renderStateError(w http.ResponseWriter, req *http.Request, componentName string, err error) {
+	title, message, code := stateFailure(err)
+	if code == http.StatusBadRequest {
+		r.logger().Warn("signed state rejected",
+			"component", componentName,
+			"remote_addr", req.RemoteAddr,
+			"error", err)
+	} else {
+		r.logger().Error("component state error",
+			"component", componentName,
+			"error", err)
+	}
+	r.reportError(req.Context(), componentName, "state", err, code)
+	r.renderComponentError(w, req, componentName, err, title, message, code)
+}
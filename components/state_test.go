@@ -0,0 +1,136 @@
+package components_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTodo struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// TestStatefulTodoList keeps its items server-side, keyed by list
+type TestStatefulTodoList struct {
+	ListID string     `form:"list" json:"-"`
+	Text   string     `form:"text" json:"-"`
+	ItemID int        `form:"id" json:"-"`
+	NextID int        `json:"nextId"`
+	Items  []testTodo `json:"items"`
+}
+
+func (c *TestStatefulTodoList) StateKey() string {
+	if c.ListID == "" {
+		return ""
+	}
+	return "todos:" + c.ListID
+}
+
+func (c *TestStatefulTodoList) OnAdd(ctx context.Context) error {
+	c.NextID++
+	c.Items = append(c.Items, testTodo{ID: c.NextID, Text: c.Text})
+	return nil
+}
+
+func (c *TestStatefulTodoList) OnToggle(ctx context.Context) error {
+	for i := range c.Items {
+		if c.Items[i].ID == c.ItemID {
+			c.Items[i].Done = !c.Items[i].Done
+		}
+	}
+	return nil
+}
+
+func (c *TestStatefulTodoList) OnDelete(ctx context.Context) error {
+	items := c.Items[:0]
+	for _, item := range c.Items {
+		if item.ID != c.ItemID {
+			items = append(items, item)
+		}
+	}
+	c.Items = items
+	return nil
+}
+
+func (c *TestStatefulTodoList) Render(ctx context.Context, w io.Writer) error {
+	var parts []string
+	for _, item := range c.Items {
+		parts = append(parts, fmt.Sprintf("%d:%s:%v", item.ID, item.Text, item.Done))
+	}
+	fmt.Fprintf(w, "<ul>%s</ul>", strings.Join(parts, ","))
+	return nil
+}
+
+func TestStatefulComponent(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestStatefulTodoList](registry, "todos")
+
+	post := func(values url.Values) string {
+		req := httptest.NewRequest(http.MethodPost, "/component/todos", strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("todos")(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		return w.Body.String()
+	}
+
+	t.Run("add, toggle and delete persist across requests", func(t *testing.T) {
+		assert.Equal(t, "<ul>1:milk:false</ul>", post(url.Values{"list": {"a"}, "hxc-event": {"add"}, "text": {"milk"}}))
+		assert.Equal(t, "<ul>1:milk:false,2:eggs:false</ul>", post(url.Values{"list": {"a"}, "hxc-event": {"add"}, "text": {"eggs"}}))
+		assert.Equal(t, "<ul>1:milk:true,2:eggs:false</ul>", post(url.Values{"list": {"a"}, "hxc-event": {"toggle"}, "id": {"1"}}))
+		assert.Equal(t, "<ul>1:milk:true</ul>", post(url.Values{"list": {"a"}, "hxc-event": {"delete"}, "id": {"2"}}))
+		assert.Equal(t, "<ul>1:milk:true</ul>", post(url.Values{"list": {"a"}}))
+	})
+
+	t.Run("state is keyed per list", func(t *testing.T) {
+		assert.Equal(t, "<ul></ul>", post(url.Values{"list": {"b"}}))
+	})
+
+	t.Run("uses a custom store", func(t *testing.T) {
+		store := components.NewMemoryStateStore()
+		registry.SetStateStore(store)
+		defer registry.SetStateStore(nil)
+
+		post(url.Values{"list": {"c"}, "hxc-event": {"add"}, "text": {"bread"}})
+
+		data, ok, err := store.Load(context.Background(), "todos:c")
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.JSONEq(t, `{"nextId":1,"items":[{"id":1,"text":"bread","done":false}]}`, string(data))
+	})
+
+	t.Run("store errors render the error page", func(t *testing.T) {
+		registry.SetStateStore(failingStateStore{})
+		defer registry.SetStateStore(nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/component/todos?list=a", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("todos")(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "State Error")
+	})
+}
+
+// failingStateStore is a StateStore whose backend is unavailable
+type failingStateStore struct{}
+
+func (failingStateStore) Load(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("store unavailable")
+}
+
+func (failingStateStore) Save(ctx context.Context, key string, data []byte) error {
+	return errors.New("store unavailable")
+}
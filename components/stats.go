@@ -0,0 +1,87 @@
+package components
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ComponentStats holds the counters of one component, as reported by Stats.
+type ComponentStats struct {
+	// Requests counts the requests served by HandlerFor.
+	Requests int64 `json:"requests"`
+	// Renders counts the requests whose component rendered successfully.
+	Renders int64 `json:"renders"`
+	// Errors counts failures by the operation reported to SetOnError, such as
+	// "decode", "event", "process", "render" or "panic".
+	Errors map[string]int64 `json:"errors"`
+	// Events counts dispatched events by name, whether or not they succeeded.
+	Events map[string]int64 `json:"events"`
+}
+
+// RegistryStats is a snapshot of the counters of every component that has
+// received a request, keyed by component name.
+type RegistryStats struct {
+	Components map[string]ComponentStats `json:"components"`
+}
+
+// componentStats holds the live counters of one component.
+type componentStats struct {
+	requests atomic.Int64
+	renders  atomic.Int64
+	errors   sync.Map // operation -> *atomic.Int64
+	events   sync.Map // event name -> *atomic.Int64
+}
+
+// Stats returns a snapshot of the registry's request, render, error and event
+// counters, a lightweight alternative to a LifecycleObserver or the SetOnError
+// hook for basic monitoring. Counters are maintained atomically while requests
+// are served, and are only kept for registered components and events, so clients
+// cannot grow them with arbitrary names.
+func (r *Registry) Stats() RegistryStats {
+	stats := RegistryStats{Components: make(map[string]ComponentStats)}
+	r.stats.Range(func(key, value any) bool {
+		counters := value.(*componentStats)
+		stats.Components[key.(string)] = ComponentStats{
+			Requests: counters.requests.Load(),
+			Renders:  counters.renders.Load(),
+			Errors:   snapshotCounts(&counters.errors),
+			Events:   snapshotCounts(&counters.events),
+		}
+		return true
+	})
+	return stats
+}
+
+// ResetStats clears every counter reported by Stats, e.g. between tests.
+func (r *Registry) ResetStats() {
+	r.stats.Clear()
+}
+
+// componentStats returns the live counters of the named component, creating them
+// on first use.
+func (r *Registry) componentStats(componentName string) *componentStats {
+	if value, ok := r.stats.Load(componentName); ok {
+		return value.(*componentStats)
+	}
+	value, _ := r.stats.LoadOrStore(componentName, &componentStats{})
+	return value.(*componentStats)
+}
+
+// incrementCount adds one to the counter stored under key in counts.
+func incrementCount(counts *sync.Map, key string) {
+	value, ok := counts.Load(key)
+	if !ok {
+		value, _ = counts.LoadOrStore(key, new(atomic.Int64))
+	}
+	value.(*atomic.Int64).Add(1)
+}
+
+// snapshotCounts copies the counters in counts into a plain map.
+func snapshotCounts(counts *sync.Map) map[string]int64 {
+	snapshot := make(map[string]int64)
+	counts.Range(func(key, value any) bool {
+		snapshot[key.(string)] = value.(*atomic.Int64).Load()
+		return true
+	})
+	return snapshot
+}
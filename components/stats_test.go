@@ -0,0 +1,56 @@
+package components_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestEventComponent](registry, "events")
+	components.Register[*TestFailingRenderComponent](registry, "failing")
+
+	get := func(target string) {
+		registry.Handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	fire := func() {
+		get("/component/events?count=1&hxc-event=increment")
+		get("/component/events?count=2&hxc-event=increment")
+		get("/component/events?count=1&hxc-event=decrement")
+		get("/component/events?count=1&hxc-event=error")
+		get("/component/events?count=1&hxc-event=unknown")
+		get("/component/events?count=abc")
+		get("/component/failing")
+		get("/component/missing")
+	}
+
+	t.Run("counts requests, renders, errors by phase and events", func(t *testing.T) {
+		fire()
+
+		stats := registry.Stats()
+		assert.Equal(t, components.ComponentStats{
+			Requests: 6,
+			Renders:  3,
+			Errors:   map[string]int64{"event": 2, "decode": 1},
+			Events:   map[string]int64{"increment": 2, "decrement": 1, "error": 1},
+		}, stats.Components["events"])
+		assert.Equal(t, components.ComponentStats{
+			Requests: 1,
+			Errors:   map[string]int64{"render": 1},
+			Events:   map[string]int64{},
+		}, stats.Components["failing"])
+		assert.NotContains(t, stats.Components, "missing")
+	})
+
+	t.Run("ResetStats clears the counters", func(t *testing.T) {
+		registry.ResetStats()
+		assert.Empty(t, registry.Stats().Components)
+
+		get("/component/events?count=1&hxc-event=increment")
+		assert.Equal(t, int64(1), registry.Stats().Components["events"].Requests)
+	})
+}
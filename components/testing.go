@@ -2,20 +2,36 @@ package components
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
+	"strings"
+
+	"github.com/a-h/templ"
 )
 
 // SimulateEvent is a helper function for testing that simulates the complete
 // component lifecycle when handling an event. This simulates what happens during
 // a POST request with an hxc-event parameter.
 //
-// The function executes the following lifecycle steps in order:
+// The function executes the following lifecycle steps in order, dispatching the
+// event as HandlerFor does (including its context cancellation checks):
 //  1. Init - if component implements Initializer
-//  2. BeforeEvent - if component implements BeforeEventHandler
-//  3. On{EventName} - the event handler method
-//  4. AfterEvent - if component implements AfterEventHandler
-//  5. Process - if component implements Processor
+//  2. Validate - if component implements Validator (its errors do not stop the
+//     lifecycle, as in HandlerFor without strict validation)
+//  3. BeforeEvent - if component implements BeforeEventHandler
+//  4. BeforeOn{EventName} - if component declares it
+//  5. On{EventName} - the event handler method
+//  6. AfterOn{EventName} - if component declares it
+//  7. AfterEvent - if component implements AfterEventHandler
+//  8. Process - if component implements Processor
+//  9. AfterRender - if component implements AfterRenderHandler, as HandlerFor
+//     calls it once the response is written (Render itself is not called; use
+//     SimulateAndRender to check the HTML)
 //
 // Parameters:
 //   - ctx: The context to pass to all lifecycle methods
@@ -59,78 +75,84 @@ import (
 //	    assert.Equal(t, expected, component.Log)
 //	}
 func SimulateEvent(ctx context.Context, component interface{}, eventName string) error {
+	if _, err := simulateLifecycle(ctx, component, eventName, DefaultEventMethodPrefix); err != nil {
+		return err
+	}
+	return simulateAfterRender(ctx, component)
+}
+
+// SimulateEvent is like the package-level SimulateEvent but resolves the event
+// handler method with the registry's event method prefix (see
+// SetEventMethodPrefix), so components written for a registry with a custom
+// prefix can be tested the same way:
+//
+//	registry.SetEventMethodPrefix("Handle")
+//	err := registry.SimulateEvent(ctx, counter, "increment") // calls HandleIncrement
+func (r *Registry) SimulateEvent(ctx context.Context, component interface{}, eventName string) error {
+	if _, err := simulateLifecycle(ctx, component, eventName, r.eventMethodPrefix()); err != nil {
+		return err
+	}
+	return simulateAfterRender(ctx, component)
+}
+
+// simulateLifecycle runs the lifecycle HandlerFor runs after decoding: Init,
+// Validate, the event (if eventName is not empty), calling {prefix}{EventName}
+// as its handler, and Process. It uses a registry of its own, so simulations are
+// not counted in a real registry's Stats. Failures are returned with the phase
+// that failed, e.g. "Init failed: ...".
+func simulateLifecycle(ctx context.Context, component interface{}, eventName, prefix string) (lifecycleResult, error) {
 	if component == nil {
-		return fmt.Errorf("component cannot be nil")
+		return lifecycleResult{}, fmt.Errorf("component cannot be nil")
 	}
 
 	// Verify component is a pointer to a struct
 	v := reflect.ValueOf(component)
 	if v.Kind() != reflect.Ptr {
-		return fmt.Errorf("component must be a pointer to a struct, got %T", component)
+		return lifecycleResult{}, fmt.Errorf("component must be a pointer to a struct, got %T", component)
 	}
 	if v.Elem().Kind() != reflect.Struct {
-		return fmt.Errorf("component must be a pointer to a struct, got %T", component)
+		return lifecycleResult{}, fmt.Errorf("component must be a pointer to a struct, got %T", component)
 	}
 
-	// Step 1: Call Init if component implements Initializer
-	if initializer, ok := component.(Initializer); ok {
-		if err := initializer.Init(ctx); err != nil {
-			return fmt.Errorf("Init failed: %w", err)
-		}
+	sim := &Registry{eventPrefix: prefix}
+	result, err := sim.runLifecycle(ctx, component, lifecycleOptions{
+		componentName: fmt.Sprintf("%T", component),
+		eventName:     eventName,
+	})
+	var lifecycleErr *ComponentError
+	if !errors.As(err, &lifecycleErr) {
+		return result, err
 	}
-
-	// Step 2: Call BeforeEvent if component implements BeforeEventHandler
-	if beforeHandler, ok := component.(BeforeEventHandler); ok {
-		if err := beforeHandler.BeforeEvent(ctx, eventName); err != nil {
-			return fmt.Errorf("BeforeEvent failed: %w", err)
-		}
-	}
-
-	// Step 3: Call the event handler method On{EventName}
-	methodName := "On" + capitalize(eventName)
-	method := v.MethodByName(methodName)
-
-	if !method.IsValid() {
-		return fmt.Errorf("event handler method '%s' not found on component %T", methodName, component)
-	}
-
-	// Validate event handler signature: On{Event}(ctx context.Context) error
-	methodType := method.Type()
-	if methodType.NumIn() != 1 {
-		return fmt.Errorf("event handler '%s' must have signature %s(ctx context.Context) error", methodName, methodName)
-	}
-
-	// Check that first parameter is context.Context
-	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
-	if !methodType.In(0).Implements(ctxType) {
-		return fmt.Errorf("event handler '%s' first parameter must be context.Context", methodName)
+	cause := lifecycleErr.Err
+	switch lifecycleErr.Operation {
+	case "init":
+		return result, fmt.Errorf("Init failed: %w", cause)
+	case "process":
+		return result, fmt.Errorf("Process failed: %w", cause)
 	}
-
-	// Call the event handler method with context
-	results := method.Call([]reflect.Value{reflect.ValueOf(ctx)})
-
-	// Check if method returns an error
-	if len(results) > 0 {
-		if err, ok := results[0].Interface().(error); ok && err != nil {
-			return fmt.Errorf("event handler failed: %w", err)
-		}
-	}
-
-	// Step 4: Call AfterEvent if component implements AfterEventHandler
-	if afterHandler, ok := component.(AfterEventHandler); ok {
-		if err := afterHandler.AfterEvent(ctx, eventName); err != nil {
-			return fmt.Errorf("AfterEvent failed: %w", err)
-		}
+	var notFound *ErrEventNotFound
+	if errors.As(cause, &notFound) {
+		return result, fmt.Errorf("%w: no method %s", notFound, prefix+capitalize(eventName))
 	}
+	return result, cause
+}
 
-	// Step 5: Call Process if component implements Processor
-	if processor, ok := component.(Processor); ok {
-		if err := processor.Process(ctx); err != nil {
-			return fmt.Errorf("Process failed: %w", err)
-		}
+// SimulateEventWithContext is like SimulateEvent but first applies each decorator to
+// ctx, in order. Use it to inject the values that middleware adds to the request
+// context in production, such as a database handle or the current user, so that
+// lifecycle methods see the same context in tests.
+//
+// Example:
+//
+//	withUser := func(ctx context.Context) context.Context {
+//	    return context.WithValue(ctx, userKey, "alice")
+//	}
+//	err := components.SimulateEventWithContext(ctx, counter, "increment", withUser)
+func SimulateEventWithContext(ctx context.Context, component interface{}, eventName string, decorators ...func(context.Context) context.Context) error {
+	for _, decorate := range decorators {
+		ctx = decorate(ctx)
 	}
-
-	return nil
+	return SimulateEvent(ctx, component, eventName)
 }
 
 // SimulateProcess is a helper function for testing that simulates the component
@@ -138,7 +160,10 @@ func SimulateEvent(ctx context.Context, component interface{}, eventName string)
 //
 // The function executes the following lifecycle steps in order:
 //  1. Init - if component implements Initializer
-//  2. Process - if component implements Processor
+//  2. Validate - if component implements Validator
+//  3. Process - if component implements Processor
+//  4. AfterRender - if component implements AfterRenderHandler (Render itself is
+//     not called)
 //
 // Parameters:
 //   - ctx: The context to pass to all lifecycle methods
@@ -161,6 +186,43 @@ func SimulateEvent(ctx context.Context, component interface{}, eventName string)
 //	    assert.Equal(t, "/dashboard", form.RedirectTo)
 //	}
 func SimulateProcess(ctx context.Context, component interface{}) error {
+	if _, err := simulateLifecycle(ctx, component, "", DefaultEventMethodPrefix); err != nil {
+		return err
+	}
+	return simulateAfterRender(ctx, component)
+}
+
+// SimulateRequest is a helper function for testing that runs the same pipeline
+// as HandlerFor without starting an HTTP server.
+//
+// The function executes the following steps in order:
+//  1. Decode values into the component (using its FormDecoder if implemented)
+//     and apply `default` struct tags
+//  2. Apply HX-* request headers and the HTTP method
+//  3. Init, Validate, the event named by hxc-event (if present) and Process,
+//     run by the same lifecycle as HandlerFor, then AfterRender
+//
+// Parameters:
+//   - ctx: The context to pass to all lifecycle methods
+//   - component: The component instance to test (must be a pointer to a struct)
+//   - method: The HTTP method to report to the component (e.g., "GET", "POST")
+//   - values: The form values to decode into the component
+//   - headers: The request headers to apply (may be nil)
+//
+// Returns an error if decoding or any step in the lifecycle fails.
+//
+// Example usage:
+//
+//	func TestCounterRequest(t *testing.T) {
+//	    counter := &CounterComponent{}
+//	    values := url.Values{"count": {"5"}, "hxc-event": {"increment"}}
+//
+//	    err := components.SimulateRequest(ctx, counter, http.MethodPost, values, nil)
+//	    require.NoError(t, err)
+//
+//	    assert.Equal(t, 6, counter.Count)
+//	}
+func SimulateRequest(ctx context.Context, component interface{}, method string, values url.Values, headers http.Header) error {
 	if component == nil {
 		return fmt.Errorf("component cannot be nil")
 	}
@@ -174,19 +236,194 @@ func SimulateProcess(ctx context.Context, component interface{}) error {
 		return fmt.Errorf("component must be a pointer to a struct, got %T", component)
 	}
 
-	// Step 1: Call Init if component implements Initializer
-	if initializer, ok := component.(Initializer); ok {
-		if err := initializer.Init(ctx); err != nil {
-			return fmt.Errorf("Init failed: %w", err)
-		}
+	// Step 1: Decode form values using the component's decoder or the default
+	decoder := defaultDecoder
+	if customDecoder, ok := component.(FormDecoder); ok {
+		decoder = customDecoder.GetFormDecoder()
+	}
+	if err := decodeForm(decoder, component, values); err != nil {
+		return fmt.Errorf("decode failed: %w", err)
+	}
+	if err := applyDefaults(component); err != nil {
+		return fmt.Errorf("decode failed: %w", err)
+	}
+	if err := afterDecode(ctx, component); err != nil {
+		return fmt.Errorf("decode failed: %w", err)
+	}
+
+	// Step 2: Apply request headers
+	req, err := http.NewRequestWithContext(ctx, method, "/", nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	if headers != nil {
+		req.Header = headers.Clone()
 	}
+	applyHxHeaders(component, req)
 
-	// Step 2: Call Process if component implements Processor
-	if processor, ok := component.(Processor); ok {
-		if err := processor.Process(ctx); err != nil {
-			return fmt.Errorf("Process failed: %w", err)
+	// Step 3: Run the lifecycle, dispatching an event if one was supplied
+	if _, err := simulateLifecycle(ctx, component, values.Get(DefaultEventParamName), DefaultEventMethodPrefix); err != nil {
+		return err
+	}
+	return simulateAfterRender(ctx, component)
+}
+
+// SimulateHandler is a helper function for testing that sends a request for the
+// named component through r.HandlerFor and returns the recorded response, so tests
+// can assert on the status code, body and HX-* headers without building requests
+// by hand. Unlike SimulateRequest it exercises the full HTTP path, including
+// registration options, error handlers and response headers.
+//
+// For GET and HEAD requests values are sent as the query string; for other
+// methods they are form-encoded in the request body.
+//
+// Parameters:
+//   - r: The registry the component is registered with
+//   - name: The registered component name
+//   - method: The HTTP method of the request (e.g., "GET", "POST")
+//   - values: The form values to send (may be nil)
+//   - headers: The request headers to send (may be nil)
+//
+// Example usage:
+//
+//	func TestLoginHandler(t *testing.T) {
+//	    values := url.Values{"username": {"demo"}, "password": {"password"}}
+//
+//	    w := components.SimulateHandler(registry, "login", http.MethodPost, values, nil)
+//
+//	    assert.Equal(t, http.StatusOK, w.Code)
+//	    assert.Equal(t, "/dashboard", w.Header().Get("HX-Redirect"))
+//	}
+func SimulateHandler(r *Registry, name, method string, values url.Values, headers http.Header) *httptest.ResponseRecorder {
+	target := DefaultHandlerPrefix + url.PathEscape(name)
+	var body io.Reader
+	if method == http.MethodGet || method == http.MethodHead {
+		if len(values) > 0 {
+			target += "?" + values.Encode()
 		}
+	} else {
+		body = strings.NewReader(values.Encode())
+	}
+
+	req := httptest.NewRequest(method, target, body)
+	for key, vals := range headers {
+		req.Header[key] = append([]string(nil), vals...)
+	}
+	if body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	}
 
+	w := httptest.NewRecorder()
+	r.HandlerFor(name)(w, req)
+	return w
+}
+
+// RenderToString is a helper function for testing that renders a templ.Component
+// into a string and returns the resulting HTML.
+//
+// Example usage:
+//
+//	html, err := components.RenderToString(ctx, &CounterComponent{Count: 3})
+//	require.NoError(t, err)
+//	assert.Contains(t, html, "3")
+func RenderToString(ctx context.Context, c templ.Component) (string, error) {
+	if c == nil {
+		return "", fmt.Errorf("component cannot be nil")
+	}
+
+	var sb strings.Builder
+	if err := c.Render(ctx, &sb); err != nil {
+		return "", fmt.Errorf("Render failed: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// SimulateAndRender is a helper function for testing that runs the event lifecycle
+// (see SimulateEvent) and then renders the component, returning the HTML.
+// If eventName is empty, the non-event lifecycle (see SimulateProcess) is run instead.
+// If the event handler returns a replacement component, that is rendered instead,
+// as in HandlerFor.
+// AfterRender is called after a successful render if the component implements
+// AfterRenderHandler; unlike HandlerFor, its error is returned (with the HTML) so
+// tests can assert on it.
+//
+// Example usage:
+//
+//	func TestCounterIncrementRender(t *testing.T) {
+//	    counter := &CounterComponent{Count: 5}
+//
+//	    html, err := components.SimulateAndRender(ctx, counter, "increment")
+//	    require.NoError(t, err)
+//
+//	    assert.Contains(t, html, "6")
+//	}
+func SimulateAndRender(ctx context.Context, component templ.Component, eventName string) (string, error) {
+	result, err := simulateLifecycle(ctx, component, eventName, DefaultEventMethodPrefix)
+	if err != nil {
+		return "", err
+	}
+	// Like HandlerFor, render the view an event handler returned instead
+	view := templ.Component(component)
+	if result.replacement != nil {
+		view = result.replacement
+	}
+	html, err := RenderToString(ctx, view)
+	if err != nil {
+		return "", err
+	}
+
+	// Like HandlerFor, AfterRender runs once rendering succeeds
+	return html, simulateAfterRender(ctx, component)
+}
+
+// simulateAfterRender calls AfterRender if component implements
+// AfterRenderHandler, returning its error so tests can assert on it.
+func simulateAfterRender(ctx context.Context, component interface{}) error {
+	if afterRender, ok := component.(AfterRenderHandler); ok {
+		if err := afterRender.AfterRender(ctx); err != nil {
+			return fmt.Errorf("AfterRender failed: %w", err)
+		}
+	}
 	return nil
 }
+
+// SimulateValidation is a helper function for testing Validator components in isolation.
+//
+// The function executes the following lifecycle steps in order:
+//  1. Init - if component implements Initializer
+//  2. Validate - the component must implement Validator
+//
+// Returns the validation errors reported by Validate. An error is returned only
+// if Init fails or the component does not implement Validator.
+//
+// Example usage:
+//
+//	func TestLoginValidation(t *testing.T) {
+//	    form := &LoginForm{Username: ""}
+//
+//	    errs, err := components.SimulateValidation(ctx, form)
+//	    require.NoError(t, err)
+//
+//	    require.Len(t, errs, 1)
+//	    assert.Equal(t, "username", errs[0].Field)
+//	}
+func SimulateValidation(ctx context.Context, component interface{}) ([]ValidationError, error) {
+	if component == nil {
+		return nil, fmt.Errorf("component cannot be nil")
+	}
+
+	validator, ok := component.(Validator)
+	if !ok {
+		return nil, fmt.Errorf("component %T does not implement Validator", component)
+	}
+
+	// Step 1: Call Init if component implements Initializer
+	if initializer, ok := component.(Initializer); ok {
+		if err := initializer.Init(ctx); err != nil {
+			return nil, fmt.Errorf("Init failed: %w", err)
+		}
+	}
+
+	// Step 2: Call Validate
+	return validator.Validate(ctx), nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/ocomsoft/HxComponents/components"
@@ -238,6 +240,16 @@ func TestSimulateEventErrors(t *testing.T) {
 		assert.Contains(t, err.Error(), "Process failed")
 	})
 
+	t.Run("does not run the event when the context is cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		component := &TestLifecycleComponent{}
+
+		err := components.SimulateEvent(cancelled, component, "increment")
+		require.ErrorIs(t, err, context.Canceled)
+		assert.NotContains(t, component.Log, "OnIncrement")
+	})
+
 	t.Run("stops at event handler error", func(t *testing.T) {
 		component := &TestLifecycleComponent{}
 		err := components.SimulateEvent(ctx, component, "error")
@@ -325,3 +337,315 @@ func TestCounterComponent(t *testing.T) {
 		assert.Equal(t, 5, counter.Count)
 	})
 }
+
+// TestRequestComponent records decoded fields and request headers
+type TestRequestComponent struct {
+	Name      string   `form:"name"`
+	Count     int      `form:"count"`
+	IsBoosted bool     `json:"-"`
+	Method    string   `json:"-"`
+	Validated bool     `json:"-"`
+	Log       []string `json:"-"`
+}
+
+func (t *TestRequestComponent) Validate(ctx context.Context) []components.ValidationError {
+	t.Validated = true
+	return nil
+}
+
+func (t *TestRequestComponent) SetHxBoosted(v bool) {
+	t.IsBoosted = v
+}
+
+func (t *TestRequestComponent) SetHttpMethod(method string) {
+	t.Method = method
+}
+
+func (t *TestRequestComponent) OnIncrement(ctx context.Context) error {
+	t.Log = append(t.Log, "OnIncrement")
+	t.Count++
+	return nil
+}
+
+func (t *TestRequestComponent) Process(ctx context.Context) error {
+	t.Log = append(t.Log, "Process")
+	return nil
+}
+
+func (t *TestRequestComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>%s: %d</div>", t.Name, t.Count)
+	return nil
+}
+
+func TestSimulateRequest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("decodes form values into the component", func(t *testing.T) {
+		component := &TestRequestComponent{}
+		values := url.Values{"name": {"widgets"}, "count": {"3"}}
+
+		err := components.SimulateRequest(ctx, component, http.MethodPost, values, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "widgets", component.Name)
+		assert.Equal(t, 3, component.Count)
+		assert.Equal(t, http.MethodPost, component.Method)
+		assert.True(t, component.Validated)
+		assert.Equal(t, []string{"Process"}, component.Log)
+	})
+
+	t.Run("applies HX-Boosted header", func(t *testing.T) {
+		component := &TestRequestComponent{}
+		headers := http.Header{}
+		headers.Set("HX-Boosted", "true")
+
+		err := components.SimulateRequest(ctx, component, http.MethodGet, url.Values{}, headers)
+		require.NoError(t, err)
+
+		assert.True(t, component.IsBoosted)
+		assert.Equal(t, http.MethodGet, component.Method)
+	})
+
+	t.Run("dispatches event named by hxc-event", func(t *testing.T) {
+		component := &TestRequestComponent{}
+		values := url.Values{"count": {"5"}, "hxc-event": {"increment"}}
+
+		err := components.SimulateRequest(ctx, component, http.MethodPost, values, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, 6, component.Count)
+		assert.Equal(t, []string{"OnIncrement", "Process"}, component.Log)
+	})
+
+	t.Run("returns error when decoding fails", func(t *testing.T) {
+		component := &TestRequestComponent{}
+		values := url.Values{"count": {"not-a-number"}}
+
+		err := components.SimulateRequest(ctx, component, http.MethodPost, values, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "decode failed")
+	})
+
+	t.Run("returns error when component is nil", func(t *testing.T) {
+		err := components.SimulateRequest(ctx, nil, http.MethodGet, url.Values{}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "component cannot be nil")
+	})
+}
+
+func TestSimulateHandler(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestRequestComponent](registry, "request")
+
+	t.Run("GET sends values as the query string", func(t *testing.T) {
+		w := components.SimulateHandler(registry, "request", http.MethodGet, url.Values{"name": {"widgets"}, "count": {"3"}}, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>widgets: 3</div>", w.Body.String())
+	})
+
+	t.Run("POST sends values in the body and dispatches events", func(t *testing.T) {
+		values := url.Values{"name": {"widgets"}, "count": {"5"}, "hxc-event": {"increment"}}
+		w := components.SimulateHandler(registry, "request", http.MethodPost, values, http.Header{"Hx-Request": {"true"}})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>widgets: 6</div>", w.Body.String())
+	})
+
+	t.Run("decode failure", func(t *testing.T) {
+		w := components.SimulateHandler(registry, "request", http.MethodPost, url.Values{"count": {"not-a-number"}}, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unknown component", func(t *testing.T) {
+		w := components.SimulateHandler(registry, "missing", http.MethodGet, nil, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestRenderToString(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("renders component HTML", func(t *testing.T) {
+		html, err := components.RenderToString(ctx, &TestSimpleCounter{Count: 7})
+		require.NoError(t, err)
+		assert.Equal(t, "<div>7</div>", html)
+	})
+
+	t.Run("returns error when component is nil", func(t *testing.T) {
+		_, err := components.RenderToString(ctx, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "component cannot be nil")
+	})
+}
+
+func TestSimulateAndRender(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("runs event then renders", func(t *testing.T) {
+		component := &TestLifecycleComponent{Value: 5}
+
+		html, err := components.SimulateAndRender(ctx, component, "increment")
+		require.NoError(t, err)
+
+		assert.Equal(t, "<div>6</div>", html)
+		assert.Equal(t, "Render", component.Log[len(component.Log)-1])
+	})
+
+	t.Run("runs process lifecycle when event name is empty", func(t *testing.T) {
+		component := &TestLifecycleComponent{}
+
+		html, err := components.SimulateAndRender(ctx, component, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "<div>10</div>", html)
+		assert.Equal(t, []string{"Init", "Process", "Render"}, component.Log)
+	})
+
+	t.Run("does not render when event fails", func(t *testing.T) {
+		component := &TestLifecycleComponent{}
+
+		html, err := components.SimulateAndRender(ctx, component, "error")
+		require.Error(t, err)
+
+		assert.Empty(t, html)
+		assert.NotContains(t, component.Log, "Render")
+	})
+
+	t.Run("renders the view returned by the event handler", func(t *testing.T) {
+		component := &TestLoginSwapComponent{Username: "demo"}
+
+		html, err := components.SimulateAndRender(ctx, component, "login")
+		require.NoError(t, err)
+
+		assert.Equal(t, "<div>Dashboard for demo</div>", html)
+	})
+}
+
+// TestValidatingComponent validates required fields after Init applies defaults
+type TestValidatingComponent struct {
+	Email    string `form:"email"`
+	Country  string `form:"country"`
+	FailInit bool   `json:"-"`
+}
+
+func (t *TestValidatingComponent) Init(ctx context.Context) error {
+	if t.FailInit {
+		return fmt.Errorf("init error")
+	}
+	if t.Country == "" {
+		t.Country = "NZ"
+	}
+	return nil
+}
+
+func (t *TestValidatingComponent) Validate(ctx context.Context) []components.ValidationError {
+	var errs []components.ValidationError
+	if t.Email == "" {
+		errs = append(errs, components.ValidationError{Field: "email", Message: "Email is required"})
+	}
+	if t.Country == "" {
+		errs = append(errs, components.ValidationError{Field: "country", Message: "Country is required"})
+	}
+	return errs
+}
+
+func (t *TestValidatingComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprint(w, "<div>Validating</div>")
+	return nil
+}
+
+func TestSimulateValidation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns validation errors for missing fields", func(t *testing.T) {
+		component := &TestValidatingComponent{}
+
+		errs, err := components.SimulateValidation(ctx, component)
+		require.NoError(t, err)
+
+		// Country is defaulted by Init, so only email is missing
+		expected := []components.ValidationError{
+			{Field: "email", Message: "Email is required"},
+		}
+		assert.Equal(t, expected, errs)
+		assert.Equal(t, "NZ", component.Country)
+	})
+
+	t.Run("returns no errors for valid component", func(t *testing.T) {
+		component := &TestValidatingComponent{Email: "user@example.com"}
+
+		errs, err := components.SimulateValidation(ctx, component)
+		require.NoError(t, err)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("returns error when Init fails", func(t *testing.T) {
+		component := &TestValidatingComponent{FailInit: true}
+
+		errs, err := components.SimulateValidation(ctx, component)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Init failed")
+		assert.Nil(t, errs)
+	})
+
+	t.Run("returns error when component does not implement Validator", func(t *testing.T) {
+		_, err := components.SimulateValidation(ctx, &TestSimpleCounter{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not implement Validator")
+	})
+}
+
+type testContextKey string
+
+// TestAuditedCounter records the user found in the context when incremented
+type TestAuditedCounter struct {
+	Count     int    `form:"count"`
+	UpdatedBy string `json:"-"`
+}
+
+func (t *TestAuditedCounter) OnIncrement(ctx context.Context) error {
+	user, ok := ctx.Value(testContextKey("user")).(string)
+	if !ok {
+		return fmt.Errorf("no user in context")
+	}
+	t.Count++
+	t.UpdatedBy = user
+	return nil
+}
+
+func (t *TestAuditedCounter) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>%d</div>", t.Count)
+	return nil
+}
+
+func TestSimulateEventWithContext(t *testing.T) {
+	withValue := func(key, value string) func(context.Context) context.Context {
+		return func(ctx context.Context) context.Context {
+			return context.WithValue(ctx, testContextKey(key), value)
+		}
+	}
+
+	t.Run("decorator values are visible in event handlers", func(t *testing.T) {
+		counter := &TestAuditedCounter{}
+
+		err := components.SimulateEventWithContext(context.Background(), counter, "increment", withValue("user", "alice"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, counter.Count)
+		assert.Equal(t, "alice", counter.UpdatedBy)
+	})
+
+	t.Run("decorators are applied in order", func(t *testing.T) {
+		counter := &TestAuditedCounter{}
+
+		err := components.SimulateEventWithContext(context.Background(), counter, "increment",
+			withValue("user", "alice"), withValue("user", "bob"))
+		require.NoError(t, err)
+		assert.Equal(t, "bob", counter.UpdatedBy)
+	})
+
+	t.Run("without decorators behaves like SimulateEvent", func(t *testing.T) {
+		err := components.SimulateEventWithContext(context.Background(), &TestAuditedCounter{}, "increment")
+		assert.ErrorContains(t, err, "no user in context")
+	})
+}
@@ -0,0 +1,65 @@
+package components
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-playground/form/v4"
+)
+
+// DefaultTimeFormats are the layouts tried, in order, when decoding a form value
+// into a time.Time field: RFC 3339, the value of an <input type="datetime-local">
+// and the value of an <input type="date">.
+var DefaultTimeFormats = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"}
+
+var defaultDecoder = newFormDecoder(DefaultTimeFormats)
+
+// newFormDecoder returns a form decoder that decodes time.Time fields using the
+// given layouts and time.Duration fields from Go duration strings such as "1h30m".
+// Empty values decode to the zero value.
+func newFormDecoder(timeFormats []string) *form.Decoder {
+	decoder := form.NewDecoder()
+	decoder.RegisterCustomTypeFunc(func(vals []string) (interface{}, error) {
+		if len(vals) == 0 || vals[0] == "" {
+			return time.Time{}, nil
+		}
+		for _, layout := range timeFormats {
+			if t, err := time.Parse(layout, vals[0]); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot parse %q as a time", vals[0])
+	}, time.Time{})
+	decoder.RegisterCustomTypeFunc(func(vals []string) (interface{}, error) {
+		if len(vals) == 0 || vals[0] == "" {
+			return time.Duration(0), nil
+		}
+		return time.ParseDuration(vals[0])
+	}, time.Duration(0))
+	return decoder
+}
+
+// SetTimeFormats sets the layouts tried, in order, when decoding a form value into
+// a time.Time field (default DefaultTimeFormats), both by the registry's default
+// decoder and by decoders configured with WithDecoderTagName or WithDecoderMode,
+// whether registered before or after the call. Pass nil to restore the defaults.
+// Decoders supplied by a component's GetFormDecoder method are left alone.
+//
+//	registry.SetTimeFormats([]string{"02/01/2006", time.RFC3339})
+func (r *Registry) SetTimeFormats(formats []string) {
+	if len(formats) == 0 {
+		formats = DefaultTimeFormats
+	}
+	formats = append([]string(nil), formats...)
+	decoder := newFormDecoder(formats)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timeFormats = formats
+	r.decoder = decoder
+	for name, entry := range r.components {
+		if len(entry.decoderConfig) > 0 {
+			r.buildDecoderLocked(&entry)
+			r.components[name] = entry
+		}
+	}
+}
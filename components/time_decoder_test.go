@@ -0,0 +1,110 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestScheduleComponent struct {
+	Start    time.Time     `form:"start"`
+	Duration time.Duration `form:"duration"`
+}
+
+func (c *TestScheduleComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "%s for %s", c.Start.Format(time.RFC3339), c.Duration)
+	return nil
+}
+
+func TestTimeDecoding(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestScheduleComponent](registry, "schedule")
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/schedule", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("schedule")(w, req)
+		return w
+	}
+
+	t.Run("decodes a date and a duration", func(t *testing.T) {
+		w := post("start=2024-03-15&duration=1h30m")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2024-03-15T00:00:00Z for 1h30m0s", w.Body.String())
+	})
+
+	t.Run("decodes datetime-local and RFC 3339 values", func(t *testing.T) {
+		assert.Equal(t, "2024-03-15T09:30:00Z for 0s", post("start=2024-03-15T09:30").Body.String())
+		assert.Equal(t, "2024-03-15T09:30:00+02:00 for 0s", post("start=2024-03-15T09:30:00%2B02:00").Body.String())
+	})
+
+	t.Run("empty values decode to zero", func(t *testing.T) {
+		w := post("start=&duration=")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "0001-01-01T00:00:00Z for 0s", w.Body.String())
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, post("start=15/03/2024").Code)
+		assert.Equal(t, http.StatusBadRequest, post("duration=soon").Code)
+	})
+
+	t.Run("uses the configured time formats", func(t *testing.T) {
+		registry.SetTimeFormats([]string{"02/01/2006"})
+		defer registry.SetTimeFormats(nil)
+
+		w := post("start=15/03/2024")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2024-03-15T00:00:00Z for 0s", w.Body.String())
+		assert.Equal(t, http.StatusBadRequest, post("start=2024-03-15").Code)
+	})
+}
+
+// TestJSONScheduleComponent decodes its start time from a json-tagged field
+type TestJSONScheduleComponent struct {
+	Start time.Time `json:"start"`
+}
+
+func (c *TestJSONScheduleComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprint(w, c.Start.Format(time.RFC3339))
+	return nil
+}
+
+func TestTimeFormatsWithDecoderOptions(t *testing.T) {
+	get := func(registry *components.Registry, name, start string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		registry.HandlerFor(name)(w, httptest.NewRequest(http.MethodGet, "/component/"+name+"?start="+start, nil))
+		return w
+	}
+
+	t.Run("formats set before registering", func(t *testing.T) {
+		registry := components.NewRegistry()
+		registry.SetTimeFormats([]string{"02/01/2006"})
+		components.Register[*TestJSONScheduleComponent](registry, "schedule", components.WithDecoderTagName("json"))
+
+		w := get(registry, "schedule", "15/03/2024")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2024-03-15T00:00:00Z", w.Body.String())
+	})
+
+	t.Run("formats set after registering", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestJSONScheduleComponent](registry, "schedule", components.WithDecoderTagName("json"))
+		registry.SetTimeFormats([]string{"02/01/2006"})
+
+		assert.Equal(t, "2024-03-15T00:00:00Z", get(registry, "schedule", "15/03/2024").Body.String())
+		assert.Equal(t, http.StatusBadRequest, get(registry, "schedule", "2024-03-15").Code)
+
+		registry.SetTimeFormats(nil)
+		assert.Equal(t, "2024-03-15T00:00:00Z", get(registry, "schedule", "2024-03-15").Body.String())
+	})
+}
@@ -0,0 +1,55 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// StatusClientClosedRequest is the non-standard status (popularized by nginx) used
+// when the client cancelled the request before the component finished.
+const StatusClientClosedRequest = 499
+
+// SetDefaultTimeout bounds the lifecycle of every component that was not
+// registered with its own WithTimeout option. Pass d <= 0 to remove the default.
+func (r *Registry) SetDefaultTimeout(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultTimeout = d
+}
+
+// timeoutFor returns the timeout for a component, falling back to the registry default.
+// Callers must hold r.mu.
+func (r *Registry) timeoutFor(entry componentEntry) time.Duration {
+	if entry.timeout > 0 {
+		return entry.timeout
+	}
+	return r.defaultTimeout
+}
+
+// deadlineExceeded reports whether the request's component timeout has expired.
+func deadlineExceeded(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+// renderTimeout renders a 504 Gateway Timeout for a component whose lifecycle
+// ran past its timeout during the given operation.
+func (r *Registry) renderTimeout(w http.ResponseWriter, req *http.Request, componentName, operation string) {
+	r.logger().Error("component timed out",
+		"component", componentName,
+		"operation", operation)
+	r.reportError(req.Context(), componentName, operation, req.Context().Err(), http.StatusGatewayTimeout)
+	r.renderComponentError(w, req, componentName, req.Context().Err(), "Gateway Timeout", "Component took too long to respond", http.StatusGatewayTimeout)
+}
+
+// renderCanceled renders a 499 Client Closed Request for a component whose request
+// was cancelled, typically by the client disconnecting, during the given operation.
+// The client is usually gone, so the response is mostly for logs and middleware.
+func (r *Registry) renderCanceled(w http.ResponseWriter, req *http.Request, componentName, operation string, err error) {
+	r.logger().Warn("component request cancelled",
+		"component", componentName,
+		"operation", operation)
+	r.reportError(req.Context(), componentName, operation, err, StatusClientClosedRequest)
+	r.renderComponentError(w, req, componentName, err, "Client Closed Request", "The request was cancelled", StatusClientClosedRequest)
+}
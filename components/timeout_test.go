@@ -0,0 +1,145 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSlowComponent sleeps in Process for the requested number of milliseconds
+type TestSlowComponent struct {
+	DelayMS  int  `form:"delay"`
+	HonorCtx bool `form:"honor"`
+}
+
+func (c *TestSlowComponent) Process(ctx context.Context) error {
+	if !c.HonorCtx {
+		time.Sleep(time.Duration(c.DelayMS) * time.Millisecond)
+		return nil
+	}
+	select {
+	case <-time.After(time.Duration(c.DelayMS) * time.Millisecond):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *TestSlowComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>done after %dms</div>", c.DelayMS)
+	return nil
+}
+
+func TestWithTimeout(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*TestSlowComponent](registry, "slow", components.WithTimeout(20*time.Millisecond))
+	components.Register[*TestSlowComponent](registry, "unbounded")
+
+	get := func(name, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/component/"+name+"?"+query, nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor(name)(w, req)
+		return w
+	}
+
+	t.Run("fast handler succeeds", func(t *testing.T) {
+		w := get("slow", "delay=1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>done after 1ms</div>", w.Body.String())
+	})
+
+	t.Run("handler honoring ctx times out with 504", func(t *testing.T) {
+		w := get("slow", "delay=1000&honor=true")
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.Contains(t, w.Body.String(), "Gateway Timeout")
+	})
+
+	t.Run("handler ignoring ctx still gets 504", func(t *testing.T) {
+		w := get("slow", "delay=40")
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	})
+
+	t.Run("registry default applies to components without their own", func(t *testing.T) {
+		registry.SetDefaultTimeout(10 * time.Millisecond)
+		defer registry.SetDefaultTimeout(0)
+
+		w := get("unbounded", "delay=1000&honor=true")
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	})
+
+	t.Run("no timeout without an option or default", func(t *testing.T) {
+		w := get("unbounded", "delay=30")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+// TestCancelComponent records the event phases it runs
+type TestCancelComponent struct {
+	calls *[]string
+}
+
+func (c *TestCancelComponent) BeforeEvent(ctx context.Context, eventName string) error {
+	*c.calls = append(*c.calls, "BeforeEvent")
+	return nil
+}
+
+func (c *TestCancelComponent) OnSave(ctx context.Context) error {
+	*c.calls = append(*c.calls, "OnSave")
+	return nil
+}
+
+func (c *TestCancelComponent) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprint(w, "<div>saved</div>")
+	return nil
+}
+
+func TestEventRespectsContext(t *testing.T) {
+	var calls []string
+	registry := components.NewRegistry()
+	components.Register[*TestCancelComponent](registry, "save",
+		components.WithFactory(func() *TestCancelComponent {
+			return &TestCancelComponent{calls: &calls}
+		}),
+	)
+
+	serve := func(ctx context.Context) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/save?hxc-event=save", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("save")(w, req)
+		return w
+	}
+
+	t.Run("runs the event with a live context", func(t *testing.T) {
+		calls = nil
+		w := serve(context.Background())
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"BeforeEvent", "OnSave"}, calls)
+	})
+
+	t.Run("cancelled request skips the event", func(t *testing.T) {
+		calls = nil
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		w := serve(ctx)
+		assert.Equal(t, components.StatusClientClosedRequest, w.Code)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("expired deadline skips the event", func(t *testing.T) {
+		calls = nil
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		w := serve(ctx)
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.Empty(t, calls)
+	})
+}
@@ -0,0 +1,131 @@
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TraceParam is the query parameter that requests a lifecycle trace instead of the
+// rendered component when debug mode is enabled, e.g. /component/counter?__trace=1.
+const TraceParam = "__trace"
+
+// ComponentTrace is the JSON body returned for a traced request. It lists every
+// lifecycle phase in order, whether it ran, how long it took and, for phases that
+// were skipped, why.
+type ComponentTrace struct {
+	Component string       `json:"component"`
+	Event     string       `json:"event,omitempty"`
+	Phases    []TracePhase `json:"phases"`
+}
+
+// TracePhase describes a single lifecycle phase within a ComponentTrace.
+type TracePhase struct {
+	Phase      string  `json:"phase"`
+	Executed   bool    `json:"executed"`
+	DurationMs float64 `json:"durationMs"`
+	Error      string  `json:"error,omitempty"`
+	// Skipped explains why a phase did not run, e.g. "Processor not implemented".
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// traceRequested reports whether req asks for a lifecycle trace.
+func traceRequested(req *http.Request) bool {
+	return req.URL.Query().Get(TraceParam) == "1"
+}
+
+// traceObserver records phase timings for a single request and forwards every
+// notification to the registry's own observer (if any).
+type traceObserver struct {
+	next LifecycleObserver
+
+	mu     sync.Mutex
+	starts map[string]time.Time
+	phases map[string]TracePhase
+}
+
+func newTraceObserver(next LifecycleObserver) *traceObserver {
+	return &traceObserver{
+		next:   next,
+		starts: make(map[string]time.Time),
+		phases: make(map[string]TracePhase),
+	}
+}
+
+func (o *traceObserver) PhaseStart(ctx context.Context, component, phase string) {
+	o.mu.Lock()
+	o.starts[phase] = time.Now()
+	o.mu.Unlock()
+	if o.next != nil {
+		o.next.PhaseStart(ctx, component, phase)
+	}
+}
+
+func (o *traceObserver) PhaseEnd(ctx context.Context, component, phase string, err error) {
+	o.mu.Lock()
+	p := TracePhase{
+		Phase:      phase,
+		Executed:   true,
+		DurationMs: float64(time.Since(o.starts[phase])) / float64(time.Millisecond),
+	}
+	if err != nil {
+		p.Error = err.Error()
+	}
+	o.phases[phase] = p
+	o.mu.Unlock()
+	if o.next != nil {
+		o.next.PhaseEnd(ctx, component, phase, err)
+	}
+}
+
+// trace builds the ComponentTrace for instance, explaining any phase that did not run.
+func (o *traceObserver) trace(componentName, eventName string, instance any) ComponentTrace {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	t := ComponentTrace{Component: componentName, Event: eventName}
+	for _, phase := range []string{PhaseDecode, PhaseInit, PhaseValidate, PhaseEvent, PhaseProcess, PhaseRender} {
+		if p, ok := o.phases[phase]; ok {
+			t.Phases = append(t.Phases, p)
+			continue
+		}
+		t.Phases = append(t.Phases, TracePhase{Phase: phase, Skipped: skipReason(phase, eventName, instance)})
+	}
+	return t
+}
+
+// skipReason explains why phase was not executed for instance.
+func skipReason(phase, eventName string, instance any) string {
+	switch phase {
+	case PhaseInit:
+		if _, ok := instance.(Initializer); !ok {
+			return "Initializer not implemented"
+		}
+	case PhaseValidate:
+		if _, ok := instance.(Validator); !ok {
+			return "Validator not implemented"
+		}
+	case PhaseEvent:
+		if eventName == "" {
+			return "no event requested"
+		}
+	case PhaseProcess:
+		if _, ok := instance.(Processor); !ok {
+			return "Processor not implemented"
+		}
+	}
+	return "not reached"
+}
+
+// writeTrace responds with the JSON lifecycle trace in place of the rendered component.
+func (r *Registry) writeTrace(w http.ResponseWriter, trace ComponentTrace) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(trace); err != nil {
+		r.logger().Error("failed to write lifecycle trace",
+			"component", trace.Component,
+			"error", err)
+	}
+}
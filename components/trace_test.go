@@ -0,0 +1,71 @@
+package components_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycleTrace(t *testing.T) {
+	get := func(registry *components.Registry, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("counter")(w, req)
+		return w
+	}
+
+	t.Run("lists the phases of a counter increment", func(t *testing.T) {
+		registry := components.NewRegistry()
+		registry.EnableDebugMode()
+		components.Register[*TestLifecycleComponent](registry, "counter")
+
+		w := get(registry, "/component/counter?hxc-event=increment&__trace=1")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var trace components.ComponentTrace
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trace))
+		assert.Equal(t, "counter", trace.Component)
+		assert.Equal(t, "increment", trace.Event)
+
+		var executed []string
+		for _, phase := range trace.Phases {
+			if phase.Executed {
+				executed = append(executed, phase.Phase)
+			}
+		}
+		assert.Equal(t, []string{"decode", "init", "event", "process", "render"}, executed)
+		assert.Equal(t, "Validator not implemented", trace.Phases[2].Skipped)
+	})
+
+	t.Run("explains a skipped event", func(t *testing.T) {
+		registry := components.NewRegistry()
+		registry.EnableDebugMode()
+		components.Register[*TestSimpleCounter](registry, "counter")
+
+		w := get(registry, "/component/counter?__trace=1")
+
+		var trace components.ComponentTrace
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trace))
+		for _, phase := range trace.Phases {
+			if phase.Phase == components.PhaseEvent {
+				assert.False(t, phase.Executed)
+				assert.Equal(t, "no event requested", phase.Skipped)
+			}
+		}
+	})
+
+	t.Run("ignored outside debug mode", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestLifecycleComponent](registry, "counter")
+
+		w := get(registry, "/component/counter?hxc-event=increment&__trace=1")
+		assert.Equal(t, "text/html", w.Header().Get("Content-Type"))
+		assert.Equal(t, "<div>11</div>", w.Body.String())
+	})
+}
@@ -0,0 +1,72 @@
+package components
+
+import (
+	"context"
+	"fmt"
+)
+
+// Transactional is an optional interface for components whose event handling must
+// be atomic, such as handlers that perform several database writes across
+// BeforeEvent, On{Event} and AfterEvent.
+//
+// When a request dispatches an event, the registry calls Begin before BeforeEvent
+// and threads the context it returns through BeforeEvent, the event handler and
+// AfterEvent. If all three succeed, Commit is called; if any of them returns an
+// error (or panics), Rollback is called instead and the event error is reported.
+// Init, Validate and Process run outside the transaction.
+//
+// Example:
+//
+//	type txKey struct{}
+//
+//	func (c *TransferForm) Begin(ctx context.Context) (context.Context, error) {
+//	    tx, err := c.DB.BeginTx(ctx, nil)
+//	    if err != nil {
+//	        return ctx, err
+//	    }
+//	    return context.WithValue(ctx, txKey{}, tx), nil
+//	}
+//
+//	func (c *TransferForm) Commit(ctx context.Context) error {
+//	    return ctx.Value(txKey{}).(*sql.Tx).Commit()
+//	}
+//
+//	func (c *TransferForm) Rollback(ctx context.Context) {
+//	    ctx.Value(txKey{}).(*sql.Tx).Rollback()
+//	}
+type Transactional interface {
+	Begin(ctx context.Context) (context.Context, error)
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context)
+}
+
+// inTransaction runs fn inside instance's transaction if it implements
+// Transactional, and directly otherwise. The transaction is rolled back if fn
+// returns an error or panics.
+func inTransaction(ctx context.Context, instance any, fn func(ctx context.Context) error) error {
+	tx, ok := instance.(Transactional)
+	if !ok {
+		return fn(ctx)
+	}
+
+	txCtx, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin failed: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback(txCtx)
+		}
+	}()
+
+	if err := fn(txCtx); err != nil {
+		return err
+	}
+	committed = true
+	if err := tx.Commit(txCtx); err != nil {
+		return fmt.Errorf("Commit failed: %w", err)
+	}
+	return nil
+}
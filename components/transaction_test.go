@@ -0,0 +1,113 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTxKey struct{}
+
+// TestTransactionalComponent records its transaction calls alongside the event hooks
+type TestTransactionalComponent struct {
+	Fail bool     `form:"fail"`
+	Log  []string `json:"-"`
+}
+
+func (c *TestTransactionalComponent) Begin(ctx context.Context) (context.Context, error) {
+	c.Log = append(c.Log, "Begin")
+	return context.WithValue(ctx, testTxKey{}, "tx-1"), nil
+}
+
+func (c *TestTransactionalComponent) Commit(ctx context.Context) error {
+	c.Log = append(c.Log, "Commit:"+ctx.Value(testTxKey{}).(string))
+	return nil
+}
+
+func (c *TestTransactionalComponent) Rollback(ctx context.Context) {
+	c.Log = append(c.Log, "Rollback:"+ctx.Value(testTxKey{}).(string))
+}
+
+func (c *TestTransactionalComponent) BeforeEvent(ctx context.Context, eventName string) error {
+	c.Log = append(c.Log, "BeforeEvent")
+	return nil
+}
+
+func (c *TestTransactionalComponent) OnSave(ctx context.Context) error {
+	tx, _ := ctx.Value(testTxKey{}).(string)
+	c.Log = append(c.Log, "OnSave:"+tx)
+	if c.Fail {
+		return fmt.Errorf("constraint violation")
+	}
+	return nil
+}
+
+func (c *TestTransactionalComponent) AfterEvent(ctx context.Context, eventName string) error {
+	c.Log = append(c.Log, "AfterEvent")
+	return nil
+}
+
+func (c *TestTransactionalComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, "<div>"+strings.Join(c.Log, ",")+"</div>")
+	return err
+}
+
+func TestTransactional(t *testing.T) {
+	t.Run("commits after a successful event", func(t *testing.T) {
+		component := &TestTransactionalComponent{}
+		err := components.SimulateEvent(context.Background(), component, "save")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Begin", "BeforeEvent", "OnSave:tx-1", "AfterEvent", "Commit:tx-1"}, component.Log)
+	})
+
+	t.Run("rolls back when the event fails", func(t *testing.T) {
+		component := &TestTransactionalComponent{Fail: true}
+		err := components.SimulateEvent(context.Background(), component, "save")
+		assert.ErrorContains(t, err, "constraint violation")
+		assert.Equal(t, []string{"Begin", "BeforeEvent", "OnSave:tx-1", "Rollback:tx-1"}, component.Log)
+	})
+
+	t.Run("registry wraps the event phase in the transaction", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestTransactionalComponent](registry, "tx")
+
+		req := httptest.NewRequest(http.MethodPost, "/component/tx", strings.NewReader("hxc-event=save"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("tx")(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>Begin,BeforeEvent,OnSave:tx-1,AfterEvent,Commit:tx-1</div>", w.Body.String())
+	})
+
+	t.Run("registry reports the event error after rolling back", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestTransactionalComponent](registry, "tx")
+
+		req := httptest.NewRequest(http.MethodPost, "/component/tx", strings.NewReader("hxc-event=save&fail=true"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("tx")(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "constraint violation")
+	})
+
+	t.Run("no transaction without an event", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestTransactionalComponent](registry, "tx")
+
+		req := httptest.NewRequest(http.MethodGet, "/component/tx", nil)
+		w := httptest.NewRecorder()
+		registry.HandlerFor("tx")(w, req)
+
+		assert.Equal(t, "<div></div>", w.Body.String())
+	})
+}
@@ -0,0 +1,117 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Trigger describes a client-side event sent in an HX-Trigger response header.
+// Build one with NewTrigger and optionally attach a detail payload:
+//
+//	components.NewTrigger("itemAdded")                                // HX-Trigger: itemAdded
+//	components.NewTrigger("itemAdded").WithDetail(map[string]any{"id": 7}) // HX-Trigger: {"itemAdded":{"id":7}}
+type Trigger struct {
+	Name      string
+	Detail    any
+	hasDetail bool
+}
+
+// NewTrigger creates a trigger for the named client-side event.
+func NewTrigger(name string) *Trigger {
+	return &Trigger{Name: name}
+}
+
+// WithDetail attaches a payload that HTMX passes to listeners as event.detail.
+// The detail must be JSON-encodable.
+func (t *Trigger) WithDetail(detail any) *Trigger {
+	t.Detail = detail
+	t.hasDetail = true
+	return t
+}
+
+// HeaderValue returns the HX-Trigger header value: the plain event name, or a
+// JSON object mapping the name to its detail when one is set.
+func (t *Trigger) HeaderValue() (string, error) {
+	if !t.hasDetail {
+		return t.Name, nil
+	}
+	value, err := json.Marshal(map[string]any{t.Name: t.Detail})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode trigger detail for '%s': %w", t.Name, err)
+	}
+	return string(value), nil
+}
+
+// HxStructuredTriggerResponse is implemented by structs that want to set the HX-Trigger
+// response header from a *Trigger rather than a hand-built string. When both this and
+// HxTriggerResponse are implemented, a non-nil *Trigger takes precedence. If the
+// detail cannot be encoded as JSON, the header is not set.
+type HxStructuredTriggerResponse interface {
+	GetHxStructuredTrigger() *Trigger
+}
+
+// TriggerEvents is a list of client-side events sent together in one trigger
+// response header, for any of the three timings:
+//
+//	components.TriggerEvents{
+//	    components.NewTrigger("itemAdded").WithDetail(map[string]any{"id": 7}),
+//	    components.NewTrigger("refreshTotals"),
+//	}
+type TriggerEvents []*Trigger
+
+// HeaderValue returns the header value for the events: a comma-separated list of
+// names when none has a detail, otherwise a JSON object mapping each name to its
+// detail (null for events without one). Nil and unnamed triggers are skipped.
+func (e TriggerEvents) HeaderValue() (string, error) {
+	var names []string
+	details := make(map[string]any)
+	hasDetail := false
+	for _, trigger := range e {
+		if trigger == nil || trigger.Name == "" {
+			continue
+		}
+		names = append(names, trigger.Name)
+		details[trigger.Name] = trigger.Detail
+		hasDetail = hasDetail || trigger.hasDetail
+	}
+	if !hasDetail {
+		return strings.Join(names, ", "), nil
+	}
+	value, err := json.Marshal(details)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode trigger details for %s: %w", strings.Join(names, ", "), err)
+	}
+	return string(value), nil
+}
+
+// HxTriggerEventsResponse is implemented by structs that want to set the HX-Trigger
+// response header from several structured events. A non-empty TriggerEvents takes
+// precedence over HxTriggerResponse and HxStructuredTriggerResponse. If a detail
+// cannot be encoded as JSON, the header is not set.
+type HxTriggerEventsResponse interface {
+	GetHxTriggerEvents() TriggerEvents
+}
+
+// HxTriggerAfterSettleEventsResponse is HxTriggerEventsResponse for the
+// HX-Trigger-After-Settle header, taking precedence over
+// HxTriggerAfterSettleResponse.
+type HxTriggerAfterSettleEventsResponse interface {
+	GetHxTriggerAfterSettleEvents() TriggerEvents
+}
+
+// HxTriggerAfterSwapEventsResponse is HxTriggerEventsResponse for the
+// HX-Trigger-After-Swap header, taking precedence over HxTriggerAfterSwapResponse.
+type HxTriggerAfterSwapEventsResponse interface {
+	GetHxTriggerAfterSwapEvents() TriggerEvents
+}
+
+// setTriggerEvents sets header to the value of events, unless there are none or
+// their details cannot be encoded.
+func setTriggerEvents(h http.Header, header string, events TriggerEvents) {
+	value, err := events.HeaderValue()
+	if err == nil && value != "" {
+		h.Set(header, value)
+	}
+}
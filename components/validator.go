@@ -1,6 +1,14 @@
 package components
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/a-h/templ"
+)
 
 // Validator is an optional interface that components can implement to perform
 // validation after form decoding but before processing.
@@ -38,3 +46,51 @@ type ValidationError struct {
 func (v ValidationError) Error() string {
 	return v.Field + ": " + v.Message
 }
+
+// ValidationErrorRenderer is an optional interface that components can implement to
+// provide a dedicated view for failed validation. When the registry is in strict
+// validation mode (see Registry.SetStrictValidation), the returned component is
+// rendered with a 422 Unprocessable Entity status instead of running events and
+// Process.
+//
+// Example:
+//
+//	func (f *LoginForm) RenderValidationErrors(ctx context.Context, errs []ValidationError) templ.Component {
+//	    return LoginErrors(errs)
+//	}
+type ValidationErrorRenderer interface {
+	RenderValidationErrors(ctx context.Context, errs []ValidationError) templ.Component
+}
+
+// renderValidationErrors responds with a 422 for a component whose validation failed,
+// using its ValidationErrorRenderer view or, failing that, the error handler. The
+// swap is redirected if the component was registered with WithValidationTarget.
+func (r *Registry) renderValidationErrors(w http.ResponseWriter, req *http.Request, entry componentEntry, instance any, componentName string, errs []ValidationError) {
+	if entry.validationTarget != "" {
+		w.Header().Set("HX-Retarget", entry.validationTarget)
+	}
+	if entry.validationSwap != "" {
+		w.Header().Set("HX-Reswap", entry.validationSwap)
+	}
+
+	if renderer, ok := instance.(ValidationErrorRenderer); ok {
+		if view := renderer.RenderValidationErrors(req.Context(), errs); view != nil {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			if err := view.Render(req.Context(), w); err != nil {
+				r.logger().Error("failed to render validation errors",
+					"component", componentName,
+					"error", err)
+			}
+			return
+		}
+	}
+
+	messages := make([]string, len(errs))
+	causes := make([]error, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+		causes[i] = e
+	}
+	r.renderComponentError(w, req, componentName, errors.Join(causes...), "Validation Error", fmt.Sprintf("Validation failed: %s", strings.Join(messages, "; ")), http.StatusUnprocessableEntity)
+}
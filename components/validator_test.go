@@ -0,0 +1,136 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSignupForm renders a dedicated view for its validation errors
+type TestSignupForm struct {
+	Email     string `form:"email"`
+	Processed bool   `json:"-"`
+}
+
+func (f *TestSignupForm) Validate(ctx context.Context) []components.ValidationError {
+	if f.Email == "" {
+		return []components.ValidationError{{Field: "email", Message: "Email is required"}}
+	}
+	return nil
+}
+
+func (f *TestSignupForm) RenderValidationErrors(ctx context.Context, errs []components.ValidationError) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		for _, e := range errs {
+			fmt.Fprintf(w, "<p class=\"error\">%s: %s</p>", e.Field, e.Message)
+		}
+		return nil
+	})
+}
+
+func (f *TestSignupForm) Process(ctx context.Context) error {
+	f.Processed = true
+	return nil
+}
+
+func (f *TestSignupForm) Render(ctx context.Context, w io.Writer) error {
+	fmt.Fprintf(w, "<div>Signed up %s (processed: %t)</div>", f.Email, f.Processed)
+	return nil
+}
+
+func TestValidationErrorRenderer(t *testing.T) {
+	post := func(registry *components.Registry, name string, values url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/"+name, strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor(name)(w, req)
+		return w
+	}
+
+	t.Run("strict mode renders the validation view with 422", func(t *testing.T) {
+		registry := components.NewRegistry()
+		registry.SetStrictValidation(true)
+		components.Register[*TestSignupForm](registry, "signup")
+
+		w := post(registry, "signup", url.Values{})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, `<p class="error">email: Email is required</p>`, w.Body.String())
+	})
+
+	t.Run("strict mode renders the component when valid", func(t *testing.T) {
+		registry := components.NewRegistry()
+		registry.SetStrictValidation(true)
+		components.Register[*TestSignupForm](registry, "signup")
+
+		w := post(registry, "signup", url.Values{"email": {"user@example.com"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>Signed up user@example.com (processed: true)</div>", w.Body.String())
+	})
+
+	t.Run("strict mode without a renderer uses the error handler", func(t *testing.T) {
+		registry := components.NewRegistry()
+		registry.SetStrictValidation(true)
+		components.Register[*TestValidatingComponent](registry, "validating")
+
+		w := post(registry, "validating", url.Values{})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "Validation Error")
+		assert.Contains(t, w.Body.String(), "email: Email is required")
+	})
+
+	t.Run("default mode keeps processing", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSignupForm](registry, "signup")
+
+		w := post(registry, "signup", url.Values{})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<div>Signed up  (processed: true)</div>", w.Body.String())
+	})
+}
+
+func TestWithValidationTarget(t *testing.T) {
+	post := func(registry *components.Registry, name string, values url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/component/"+name, strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor(name)(w, req)
+		return w
+	}
+
+	registry := components.NewRegistry()
+	registry.SetStrictValidation(true)
+	components.Register[*TestSignupForm](registry, "signup",
+		components.WithValidationTarget("#signup-errors", "innerHTML"))
+	components.Register[*TestValidatingComponent](registry, "validating",
+		components.WithValidationTarget("#errors", ""))
+
+	t.Run("sets retarget and reswap on failure", func(t *testing.T) {
+		w := post(registry, "signup", url.Values{})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, "#signup-errors", w.Header().Get("HX-Retarget"))
+		assert.Equal(t, "innerHTML", w.Header().Get("HX-Reswap"))
+	})
+
+	t.Run("omitted on success", func(t *testing.T) {
+		w := post(registry, "signup", url.Values{"email": {"user@example.com"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("HX-Retarget"))
+		assert.Empty(t, w.Header().Get("HX-Reswap"))
+	})
+
+	t.Run("applies to the error handler fallback", func(t *testing.T) {
+		w := post(registry, "validating", url.Values{})
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, "#errors", w.Header().Get("HX-Retarget"))
+		assert.Empty(t, w.Header().Get("HX-Reswap"))
+	})
+}
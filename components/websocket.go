@@ -0,0 +1,312 @@
+package components
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"runtime/debug"
+	"strconv"
+
+	"github.com/a-h/templ"
+)
+
+// WebSocketConn is a WebSocket connection carrying text messages. Implement it with
+// the WebSocket library of your choice; see WebSocketUpgrader.
+//
+// ReadMessage blocks until a message arrives, the connection closes or ctx is
+// cancelled, and returns an error in the latter two cases.
+type WebSocketConn interface {
+	ReadMessage(ctx context.Context) ([]byte, error)
+	WriteMessage(ctx context.Context, data []byte) error
+	Close() error
+}
+
+// WebSocketUpgrader upgrades an HTTP request to a WebSocket connection. It keeps
+// the registry free of a WebSocket dependency: adapt the library you already use,
+// e.g. with github.com/coder/websocket:
+//
+//	type wsUpgrader struct{}
+//
+//	func (wsUpgrader) Upgrade(w http.ResponseWriter, req *http.Request) (components.WebSocketConn, error) {
+//	    conn, err := websocket.Accept(w, req, nil)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return wsConn{conn}, nil
+//	}
+//
+//	type wsConn struct{ *websocket.Conn }
+//
+//	func (c wsConn) ReadMessage(ctx context.Context) ([]byte, error) {
+//	    _, data, err := c.Read(ctx)
+//	    return data, err
+//	}
+//
+//	func (c wsConn) WriteMessage(ctx context.Context, data []byte) error {
+//	    return c.Write(ctx, websocket.MessageText, data)
+//	}
+//
+//	func (c wsConn) Close() error {
+//	    return c.Conn.Close(websocket.StatusNormalClosure, "")
+//	}
+//
+// If the upgrade fails, Upgrade is responsible for writing the HTTP response.
+type WebSocketUpgrader interface {
+	Upgrade(w http.ResponseWriter, req *http.Request) (WebSocketConn, error)
+}
+
+// SetWebSocketUpgrader sets the upgrader used by WebSocketHandler.
+func (r *Registry) SetWebSocketUpgrader(upgrader WebSocketUpgrader) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.wsUpgrader = upgrader
+}
+
+// WebSocketHandler returns a handler that serves a component over a WebSocket,
+// for use with the HTMX ws extension:
+//
+//	<div hx-ext="ws" ws-connect="/ws/counter">
+//	    <div id="counter"></div>
+//	    <form ws-send><button name="hxc-event" value="increment">+1</button></form>
+//	</div>
+//
+// The component instance lives as long as the connection. Before upgrading, the
+// query string is decoded into it and Authorize and Init run; failures are
+// returned as ordinary HTTP error responses. Each JSON message the client sends
+// (the ws extension sends the form values, plus a HEADERS object that is ignored)
+// is then decoded into the instance, Validate runs, the event named by the event
+// parameter is dispatched, Process runs and the rendered HTML is written back.
+// Decoding goes through the same checks and hooks as HandlerFor (form key limits,
+// WithStrictFields, WithSanitize, `default` tags, SetPostDecode and AfterDecode),
+// and each message is bounded by the component's timeout.
+// A failing or panicking message is answered with the error component and the
+// connection stays open. The handler returns when the client disconnects or the
+// request context is cancelled.
+//
+// SetWebSocketUpgrader must be called first; otherwise the handler responds with
+// 501 Not Implemented.
+func (r *Registry) WebSocketHandler(componentName string) http.HandlerFunc {
+	return r.groupMiddleware(componentName, func(w http.ResponseWriter, req *http.Request) {
+		logger := r.logger()
+
+		r.mu.RLock()
+		entry, exists := r.components[componentName]
+		upgrader := r.wsUpgrader
+		decoding := r.decodeSettingsLocked()
+		r.mu.RUnlock()
+
+		if !exists {
+			r.renderComponentError(w, req, componentName, &ErrComponentNotFound{ComponentName: componentName}, "Component Not Found", fmt.Sprintf("Component '%s' not found", componentName), http.StatusNotFound)
+			return
+		}
+		if entry.disabled {
+			err := &ErrComponentDisabled{ComponentName: componentName}
+			r.renderComponentError(w, req, componentName, err, "Service Unavailable", capitalize(err.Error()), http.StatusServiceUnavailable)
+			return
+		}
+		if upgrader == nil {
+			r.renderComponentError(w, req, componentName, nil, "Not Implemented", "WebSocket support requires SetWebSocketUpgrader", http.StatusNotImplemented)
+			return
+		}
+
+		instance := entry.newInstance()
+		req = req.WithContext(withRenderer(withRequestInfo(req.Context(), RequestInfo{
+			ComponentName: componentName,
+			Method:        req.Method,
+		}), r))
+
+		if err := r.decodeComponent(req.Context(), entry, instance.Interface(), req.URL.Query(), decoding, true); err != nil {
+			r.reportError(req.Context(), componentName, "decode", err, http.StatusBadRequest)
+			r.renderComponentError(w, req, componentName, err, "Decode Error", capitalize(err.Error()), http.StatusBadRequest)
+			return
+		}
+		applyHxHeaders(instance.Interface(), req)
+
+		if authorizer, ok := instance.Interface().(Authorizer); ok {
+			if err := authorizer.Authorize(req.Context()); err != nil {
+				title, message, code := authorizeFailure(err)
+				r.reportError(req.Context(), componentName, "authorize", err, code)
+				r.renderComponentError(w, req, componentName, err, title, message, code)
+				return
+			}
+		}
+
+		if err := r.initComponent(req.Context(), instance.Interface(), lifecycleOptions{componentName: componentName}); err != nil {
+			cause := errors.Unwrap(err)
+			title, message := lifecycleFailure("init", "", cause)
+			r.reportError(req.Context(), componentName, "init", cause, http.StatusInternalServerError)
+			r.renderComponentError(w, req, componentName, cause, title, message, http.StatusInternalServerError)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, req)
+		if err != nil {
+			logger.Warn("websocket upgrade failed",
+				"component", componentName,
+				"remote_addr", req.RemoteAddr,
+				"error", err)
+			return
+		}
+		defer conn.Close()
+
+		for {
+			message, err := conn.ReadMessage(req.Context())
+			if err != nil {
+				logger.Debug("websocket closed",
+					"component", componentName,
+					"error", err)
+				return
+			}
+
+			response := r.handleWebSocketMessage(req, entry, instance, componentName, message)
+			if err := conn.WriteMessage(req.Context(), response); err != nil {
+				logger.Debug("websocket write failed",
+					"component", componentName,
+					"error", err)
+				return
+			}
+		}
+	})
+}
+
+// handleWebSocketMessage runs one message through the component and returns the
+// HTML to send back: the rendered component, or the error component on failure.
+// Like a request to HandlerFor, the message is bounded by the component's timeout
+// and a panic is recovered (unless the component was registered WithRecover(false)),
+// so a failing message never takes down the connection.
+func (r *Registry) handleWebSocketMessage(req *http.Request, entry componentEntry, instance reflect.Value, componentName string, message []byte) (response []byte) {
+	logger := r.logger()
+	ctx := req.Context()
+
+	r.mu.RLock()
+	decoding := r.decodeSettingsLocked()
+	strictValidation := r.strictValid
+	timeout := r.timeoutFor(entry)
+	r.mu.RUnlock()
+
+	var buf bytes.Buffer
+	errorResponse := func(title, message string, code int) []byte {
+		buf.Reset()
+		// Rendered with the connection's context, which outlives a timed-out message
+		if renderErr := ErrorComponent(title, message, code).Render(req.Context(), &buf); renderErr != nil {
+			logger.Error("failed to render error component",
+				"component", componentName,
+				"error", renderErr)
+		}
+		return buf.Bytes()
+	}
+	fail := func(operation, title, message string, code int, err error) []byte {
+		logger.Error("websocket message error",
+			"component", componentName,
+			"operation", operation,
+			"error", err)
+		r.reportError(ctx, componentName, operation, err, code)
+		return errorResponse(title, message, code)
+	}
+
+	if r.recoversPanics(componentName) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				logger.Error("panic in websocket message handler",
+					"component", componentName,
+					"error", recovered,
+					"stack", string(debug.Stack()))
+				title, message, code := r.panicResponse(recovered, req)
+				r.reportError(ctx, componentName, "panic", fmt.Errorf("panic: %v", recovered), code)
+				response = errorResponse(title, message, code)
+			}
+		}()
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	values, err := webSocketMessageValues(message)
+	if err != nil {
+		return fail("parse", "Bad Request", fmt.Sprintf("Failed to parse message: %v", err), http.StatusBadRequest, err)
+	}
+	eventName := values.Get(decoding.eventParam)
+	ctx = withRequestInfo(ctx, RequestInfo{
+		ComponentName: componentName,
+		EventName:     eventName,
+		Method:        http.MethodGet,
+	})
+
+	if err := r.decodeComponent(ctx, entry, instance.Interface(), values, decoding, true); err != nil {
+		return fail("decode", "Decode Error", capitalize(err.Error()), http.StatusBadRequest, err)
+	}
+
+	lifecycle, err := r.runLifecycle(ctx, instance.Interface(), lifecycleOptions{
+		componentName:    componentName,
+		eventName:        eventName,
+		strictValidation: strictValidation,
+		initialized:      true,
+	})
+	var lifecycleErr *ComponentError
+	if errors.As(err, &lifecycleErr) {
+		operation, cause := lifecycleErr.Operation, lifecycleErr.Err
+		if deadlineExceeded(ctx) {
+			return fail(operation, "Gateway Timeout", "Component took too long to respond", http.StatusGatewayTimeout, cause)
+		}
+		title, message := lifecycleFailure(operation, eventName, cause)
+		code := http.StatusInternalServerError
+		if operation == "validate" {
+			code = http.StatusUnprocessableEntity
+		}
+		return fail(operation, title, message, code, cause)
+	}
+
+	component, _ := instance.Interface().(templ.Component)
+	if lifecycle.replacement != nil {
+		component = lifecycle.replacement
+	}
+	if component == nil {
+		err := &ErrNotRenderable{ComponentName: componentName, Type: instance.Type().String()}
+		return fail("render", "Configuration Error", err.Error(), http.StatusInternalServerError, err)
+	}
+	if err := renderWithOutOfBand(ctx, &buf, component, instance.Interface()); err != nil {
+		return fail("render", "Render Error", fmt.Sprintf("Component rendering failed: %v", err), http.StatusInternalServerError, err)
+	}
+	return buf.Bytes()
+}
+
+// webSocketMessageValues converts a JSON message from the HTMX ws extension into
+// form values. Arrays become repeated values and the HEADERS object is dropped.
+func webSocketMessageValues(message []byte) (url.Values, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(message, &fields); err != nil {
+		return nil, err
+	}
+	values := make(url.Values, len(fields))
+	for key, value := range fields {
+		if key == "HEADERS" {
+			continue
+		}
+		items, ok := value.([]any)
+		if !ok {
+			items = []any{value}
+		}
+		for _, item := range items {
+			switch v := item.(type) {
+			case nil:
+			case string:
+				values.Add(key, v)
+			case float64:
+				values.Add(key, strconv.FormatFloat(v, 'f', -1, 64))
+			case bool:
+				values.Add(key, strconv.FormatBool(v))
+			default:
+				return nil, fmt.Errorf("field %q has unsupported value %v", key, v)
+			}
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,221 @@
+package components_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWSConn is an in-memory WebSocket connection: the test client sends on in
+// and receives on out.
+type testWSConn struct {
+	in     chan []byte
+	out    chan []byte
+	closed chan struct{}
+}
+
+func (c *testWSConn) ReadMessage(ctx context.Context) ([]byte, error) {
+	select {
+	case msg, ok := <-c.in:
+		if !ok {
+			return nil, io.EOF
+		}
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *testWSConn) WriteMessage(ctx context.Context, data []byte) error {
+	c.out <- append([]byte(nil), data...)
+	return nil
+}
+
+func (c *testWSConn) Close() error {
+	close(c.closed)
+	return nil
+}
+
+// testWSUpgrader hands out a single testWSConn
+type testWSUpgrader struct {
+	conn *testWSConn
+}
+
+func (u *testWSUpgrader) Upgrade(w http.ResponseWriter, req *http.Request) (components.WebSocketConn, error) {
+	return u.conn, nil
+}
+
+func newTestWSConn() *testWSConn {
+	return &testWSConn{
+		in:     make(chan []byte),
+		out:    make(chan []byte, 1),
+		closed: make(chan struct{}),
+	}
+}
+
+func (c *testWSConn) roundTrip(t *testing.T, message string) string {
+	t.Helper()
+	c.in <- []byte(message)
+	select {
+	case reply := <-c.out:
+		return string(reply)
+	case <-time.After(time.Second):
+		t.Fatal("no reply from websocket handler")
+		return ""
+	}
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	t.Run("dispatches events and writes rendered HTML", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSimpleCounter](registry, "counter")
+		conn := newTestWSConn()
+		registry.SetWebSocketUpgrader(&testWSUpgrader{conn: conn})
+
+		req := httptest.NewRequest(http.MethodGet, "/ws/counter?count=5", nil)
+		done := make(chan struct{})
+		go func() {
+			registry.WebSocketHandler("counter")(httptest.NewRecorder(), req)
+			close(done)
+		}()
+
+		assert.Equal(t, "<div>6</div>", conn.roundTrip(t, `{"hxc-event":"increment","HEADERS":{"HX-Request":"true"}}`))
+		assert.Equal(t, "<div>7</div>", conn.roundTrip(t, `{"hxc-event":"increment"}`))
+		assert.Equal(t, "<div>10</div>", conn.roundTrip(t, `{"count":10}`))
+		assert.Contains(t, conn.roundTrip(t, `{"hxc-event":"missing"}`), "Event Error")
+
+		close(conn.in)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("handler did not return after disconnect")
+		}
+		_, open := <-conn.closed
+		assert.False(t, open)
+	})
+
+	t.Run("returns when the request context is cancelled", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSimpleCounter](registry, "counter")
+		conn := newTestWSConn()
+		registry.SetWebSocketUpgrader(&testWSUpgrader{conn: conn})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequest(http.MethodGet, "/ws/counter", nil).WithContext(ctx)
+		done := make(chan struct{})
+		go func() {
+			registry.WebSocketHandler("counter")(httptest.NewRecorder(), req)
+			close(done)
+		}()
+
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("handler did not return after cancellation")
+		}
+	})
+
+	t.Run("requires an upgrader", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSimpleCounter](registry, "counter")
+
+		w := httptest.NewRecorder()
+		registry.WebSocketHandler("counter")(w, httptest.NewRequest(http.MethodGet, "/ws/counter", nil))
+		require.Equal(t, http.StatusNotImplemented, w.Code)
+	})
+
+	t.Run("rejects before upgrading when Init fails", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestErrorComponent](registry, "failing", components.WithInitialState(func() *TestErrorComponent {
+			return &TestErrorComponent{FailPhase: "init"}
+		}))
+		conn := newTestWSConn()
+		registry.SetWebSocketUpgrader(&testWSUpgrader{conn: conn})
+
+		w := httptest.NewRecorder()
+		registry.WebSocketHandler("failing")(w, httptest.NewRequest(http.MethodGet, "/ws/failing", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("runs the post-decode hook for each message", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSimpleCounter](registry, "counter")
+		registry.SetPostDecode(func(ctx context.Context, component any) error {
+			if c, ok := component.(*TestSimpleCounter); ok {
+				c.Count = min(c.Count, 100)
+			}
+			return nil
+		})
+		conn, stop := serveWebSocket(t, registry, "counter", "/ws/counter?count=500")
+		defer stop()
+
+		assert.Equal(t, "<div>101</div>", conn.roundTrip(t, `{"hxc-event":"increment"}`))
+		assert.Equal(t, "<div>100</div>", conn.roundTrip(t, `{"count":900}`))
+	})
+
+	t.Run("applies strict fields before upgrading", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSimpleCounter](registry, "counter", components.WithStrictFields())
+		registry.SetWebSocketUpgrader(&testWSUpgrader{conn: newTestWSConn()})
+
+		w := httptest.NewRecorder()
+		registry.WebSocketHandler("counter")(w, httptest.NewRequest(http.MethodGet, "/ws/counter?count=1&bogus=1", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "bogus")
+	})
+
+	t.Run("recovers a panicking message and keeps the connection", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestPlainPanickingComponent](registry, "plain")
+		var operations []string
+		registry.SetOnError(func(ctx context.Context, err *components.ComponentError) {
+			operations = append(operations, err.Operation)
+		})
+		conn, stop := serveWebSocket(t, registry, "plain", "/ws/plain")
+		defer stop()
+
+		assert.Contains(t, conn.roundTrip(t, `{}`), "Internal Server Error")
+		assert.Contains(t, conn.roundTrip(t, `{}`), "Internal Server Error")
+		assert.Equal(t, []string{"panic", "panic"}, operations)
+	})
+
+	t.Run("bounds each message with the component timeout", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*TestSlowComponent](registry, "slow", components.WithTimeout(20*time.Millisecond))
+		conn, stop := serveWebSocket(t, registry, "slow", "/ws/slow")
+		defer stop()
+
+		assert.Contains(t, conn.roundTrip(t, `{"delay":1000,"honor":true}`), "Gateway Timeout")
+		assert.Equal(t, "<div>done after 1ms</div>", conn.roundTrip(t, `{"delay":1,"honor":true}`))
+	})
+}
+
+// serveWebSocket runs the component's WebSocketHandler for target in the
+// background; stop disconnects the client and waits for the handler to return.
+func serveWebSocket(t *testing.T, registry *components.Registry, name, target string) (*testWSConn, func()) {
+	t.Helper()
+	conn := newTestWSConn()
+	registry.SetWebSocketUpgrader(&testWSUpgrader{conn: conn})
+
+	done := make(chan struct{})
+	go func() {
+		registry.WebSocketHandler(name)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+		close(done)
+	}()
+	return conn, func() {
+		close(conn.in)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("handler did not return after disconnect")
+		}
+	}
+}
@@ -26,6 +26,16 @@ func (c *CounterComponent) OnDecrement(ctx context.Context) error {
 	return nil
 }
 
+// HxTarget makes the counter's buttons swap the whole counter.
+func (c *CounterComponent) HxTarget() string {
+	return "closest .counter-component"
+}
+
+// HxSwap replaces the counter element itself rather than its contents.
+func (c *CounterComponent) HxSwap() string {
+	return "outerHTML"
+}
+
 // Render implements templ.Component interface.
 // This allows the component to be used both as an HTMX component
 // and as a regular templ component in templates.
@@ -1,8 +1,11 @@
 package counter_test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/ocomsoft/HxComponents/examples/counter"
 	"github.com/ocomsoft/HxComponents/examples/testutil"
 	"github.com/playwright-community/playwright-go"
 	"github.com/stretchr/testify/assert"
@@ -224,3 +227,34 @@ func TestCounterComponent(t *testing.T) {
 		assert.Equal(t, "outerHTML", hxSwap)
 	})
 }
+
+func TestCounterRender(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("renders current count", func(t *testing.T) {
+		html, err := components.RenderToString(ctx, &counter.CounterComponent{Count: 3})
+		require.NoError(t, err)
+
+		assert.Contains(t, html, "counter-component")
+		assert.Contains(t, html, `&#34;count&#34;: 3`)
+	})
+
+	t.Run("increment renders new count", func(t *testing.T) {
+		c := &counter.CounterComponent{Count: 3}
+
+		html, err := components.SimulateAndRender(ctx, c, "increment")
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, c.Count)
+		assert.Contains(t, html, `&#34;count&#34;: 4`)
+	})
+
+	t.Run("decrement renders new count", func(t *testing.T) {
+		c := &counter.CounterComponent{Count: 0}
+
+		html, err := components.SimulateAndRender(ctx, c, "decrement")
+		require.NoError(t, err)
+
+		assert.Contains(t, html, "-1")
+	})
+}
@@ -1,8 +1,12 @@
 package login_test
 
 import (
+	"net/http"
+	"net/url"
 	"testing"
 
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/ocomsoft/HxComponents/examples/login"
 	"github.com/ocomsoft/HxComponents/examples/testutil"
 	"github.com/playwright-community/playwright-go"
 	"github.com/stretchr/testify/assert"
@@ -228,3 +232,34 @@ func TestLoginComponent(t *testing.T) {
 		assert.Equal(t, 1, count)
 	})
 }
+
+func TestLoginHandler(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*login.LoginComponent](registry, "login")
+
+	t.Run("valid credentials redirect to the dashboard", func(t *testing.T) {
+		values := url.Values{"username": {"demo"}, "password": {"password"}}
+		w := components.SimulateHandler(registry, "login", http.MethodPost, values, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "/dashboard", w.Header().Get("HX-Redirect"))
+		assert.Contains(t, w.Body.String(), "Login successful!")
+	})
+
+	t.Run("invalid credentials show an error", func(t *testing.T) {
+		values := url.Values{"username": {"demo"}, "password": {"wrong"}}
+		w := components.SimulateHandler(registry, "login", http.MethodPost, values, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("HX-Redirect"))
+		assert.Contains(t, w.Body.String(), "Invalid credentials")
+	})
+
+	t.Run("missing fields show an error", func(t *testing.T) {
+		w := components.SimulateHandler(registry, "login", http.MethodPost, url.Values{"username": {"demo"}}, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("HX-Redirect"))
+		assert.Contains(t, w.Body.String(), "Username and password are required")
+	})
+}
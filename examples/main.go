@@ -9,6 +9,7 @@ import (
 	"github.com/ocomsoft/HxComponents/components"
 	"github.com/ocomsoft/HxComponents/examples/counter"
 	"github.com/ocomsoft/HxComponents/examples/login"
+	"github.com/ocomsoft/HxComponents/examples/order"
 	"github.com/ocomsoft/HxComponents/examples/pages"
 	"github.com/ocomsoft/HxComponents/examples/profile"
 	"github.com/ocomsoft/HxComponents/examples/search"
@@ -27,6 +28,7 @@ func main() {
 	components.Register[*profile.ProfileComponent](registry, "profile")
 	components.Register[*counter.CounterComponent](registry, "counter")
 	components.Register[*todolist.TodoListComponent](registry, "todolist")
+	components.Register[*order.OrderComponent](registry, "order")
 
 	// Setup router
 	router := chi.NewRouter()
@@ -0,0 +1,55 @@
+package order
+
+import (
+	"context"
+	"io"
+)
+
+// LineItem is a single product line in an order.
+type LineItem struct {
+	SKU      string  `form:"sku"`
+	Quantity int     `form:"qty"`
+	Price    float64 `form:"price"`
+}
+
+// Subtotal returns the line's quantity multiplied by its unit price.
+func (l LineItem) Subtotal() float64 {
+	return float64(l.Quantity) * l.Price
+}
+
+// OrderComponent represents an order form with a variable number of line items.
+// It demonstrates decoding indexed form fields into a slice of structs and a map:
+//
+//	customer=Ada
+//	lineItems[0].sku=WIDGET&lineItems[0].qty=2&lineItems[0].price=3.50
+//	lineItems[1].sku=GADGET&lineItems[1].qty=1&lineItems[1].price=10
+//	notes[gift]=yes
+type OrderComponent struct {
+	Customer  string            `form:"customer"`
+	LineItems []LineItem        `form:"lineItems"`
+	Notes     map[string]string `form:"notes"`
+	Total     float64           `json:"-"`
+}
+
+// Process implements the Processor interface to total the order.
+// Lines without a SKU or with a non-positive quantity are dropped.
+func (c *OrderComponent) Process(ctx context.Context) error {
+	items := c.LineItems[:0]
+	c.Total = 0
+	for _, item := range c.LineItems {
+		if item.SKU == "" || item.Quantity <= 0 {
+			continue
+		}
+		items = append(items, item)
+		c.Total += item.Subtotal()
+	}
+	c.LineItems = items
+	return nil
+}
+
+// Render implements templ.Component interface.
+// This allows the component to be used both as an HTMX component
+// and as a regular templ component in templates.
+func (c *OrderComponent) Render(ctx context.Context, w io.Writer) error {
+	return Order(*c).Render(ctx, w)
+}
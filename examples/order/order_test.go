@@ -0,0 +1,73 @@
+package order_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/ocomsoft/HxComponents/examples/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func orderForm() url.Values {
+	return url.Values{
+		"customer":           {"Ada"},
+		"lineItems[0].sku":   {"WIDGET"},
+		"lineItems[0].qty":   {"2"},
+		"lineItems[0].price": {"3.50"},
+		"lineItems[1].sku":   {"GADGET"},
+		"lineItems[1].qty":   {"1"},
+		"lineItems[1].price": {"10"},
+		"notes[gift]":        {"yes"},
+	}
+}
+
+func TestOrderIndexedFields(t *testing.T) {
+	t.Run("decodes indexed fields into a slice of structs", func(t *testing.T) {
+		component := &order.OrderComponent{}
+		err := components.SimulateRequest(context.Background(), component, http.MethodPost, orderForm(), nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Ada", component.Customer)
+		assert.Equal(t, []order.LineItem{
+			{SKU: "WIDGET", Quantity: 2, Price: 3.5},
+			{SKU: "GADGET", Quantity: 1, Price: 10},
+		}, component.LineItems)
+		assert.Equal(t, map[string]string{"gift": "yes"}, component.Notes)
+		assert.Equal(t, 17.0, component.Total)
+	})
+
+	t.Run("renders the order over HTTP", func(t *testing.T) {
+		registry := components.NewRegistry()
+		components.Register[*order.OrderComponent](registry, "order")
+
+		req := httptest.NewRequest(http.MethodPost, "/component/order", strings.NewReader(orderForm().Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		registry.HandlerFor("order")(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, "Order for Ada")
+		assert.Contains(t, body, "<td>WIDGET</td>")
+		assert.Contains(t, body, "<td>7.00</td>")
+		assert.Contains(t, body, "17.00")
+		assert.Contains(t, body, "<strong>Gift:</strong> yes")
+	})
+
+	t.Run("drops empty lines", func(t *testing.T) {
+		values := orderForm()
+		values.Set("lineItems[2].sku", "")
+		values.Set("lineItems[2].qty", "0")
+
+		component := &order.OrderComponent{}
+		err := components.SimulateRequest(context.Background(), component, http.MethodPost, values, nil)
+		require.NoError(t, err)
+		assert.Len(t, component.LineItems, 2)
+	})
+}
@@ -1,8 +1,15 @@
 package search_test
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
 
+	"github.com/ocomsoft/HxComponents/components"
+	"github.com/ocomsoft/HxComponents/examples/search"
 	"github.com/ocomsoft/HxComponents/examples/testutil"
 	"github.com/playwright-community/playwright-go"
 	"github.com/stretchr/testify/assert"
@@ -178,3 +185,43 @@ func TestSearchComponent(t *testing.T) {
 		assert.GreaterOrEqual(t, count, 1, "Limit should be in get-demo div")
 	})
 }
+
+func TestRenderComponentMatchesHTTP(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*search.SearchComponent](registry, "search")
+
+	values := url.Values{"q": {"golang"}, "limit": {"5"}}
+
+	component, err := registry.RenderComponent(context.Background(), "search", values)
+	require.NoError(t, err)
+	embedded, err := components.RenderToString(context.Background(), component)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/component/search?"+values.Encode(), nil)
+	w := httptest.NewRecorder()
+	registry.HandlerFor("search")(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, w.Body.String(), embedded)
+	assert.Contains(t, embedded, "golang")
+
+	_, err = registry.RenderComponent(context.Background(), "missing", nil)
+	var notFound *components.ErrComponentNotFound
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestSearchHeadRequest(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*search.SearchComponent](registry, "search")
+
+	get := httptest.NewRecorder()
+	registry.HandlerFor("search")(get, httptest.NewRequest(http.MethodGet, "/component/search?q=golang", nil))
+
+	w := httptest.NewRecorder()
+	registry.HandlerFor("search")(w, httptest.NewRequest(http.MethodHead, "/component/search?q=golang", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html", w.Header().Get("Content-Type"))
+	assert.Equal(t, strconv.Itoa(get.Body.Len()), w.Header().Get("Content-Length"))
+	assert.Empty(t, w.Body.String())
+}
@@ -20,8 +20,7 @@ type TodoItem struct {
 // It shows BeforeEvent, multiple event handlers, and AfterEvent.
 // This is a stateless component - all state is passed via form fields.
 type TodoListComponent struct {
-	Items       []TodoItem `json:"-"`
-	ItemsJSON   string     `form:"items"` // Hidden field containing JSON-encoded items
+	Items       []TodoItem `form:"items,json"` // Hidden field containing JSON-encoded items
 	NewItemText string     `form:"newItemText"`
 	ItemID      int        `form:"itemId"`
 	LastEvent   string     `json:"-"`
@@ -32,14 +31,6 @@ type TodoListComponent struct {
 // This demonstrates validation and setup logic that runs for all events.
 func (t *TodoListComponent) BeforeEvent(ctx context.Context, eventName string) error {
 	slog.Info("TodoList BeforeEvent", "event", eventName)
-
-	// Deserialize items from JSON (stateless approach)
-	if t.ItemsJSON != "" {
-		if err := json.Unmarshal([]byte(t.ItemsJSON), &t.Items); err != nil {
-			return fmt.Errorf("failed to unmarshal items: %w", err)
-		}
-	}
-
 	return nil
 }
 
@@ -131,13 +122,6 @@ func (t *TodoListComponent) OnClearCompleted(ctx context.Context) error {
 // Process is still called after events complete.
 // This demonstrates that you can still use Process() for final logic.
 func (t *TodoListComponent) Process(ctx context.Context) error {
-	// Deserialize items from JSON if not already done (for non-event requests)
-	if len(t.Items) == 0 && t.ItemsJSON != "" {
-		if err := json.Unmarshal([]byte(t.ItemsJSON), &t.Items); err != nil {
-			return fmt.Errorf("failed to unmarshal items: %w", err)
-		}
-	}
-
 	slog.Debug("TodoList Process called", "itemCount", len(t.Items), "lastEvent", t.LastEvent)
 	return nil
 }
@@ -1,9 +1,12 @@
 package todolist_test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/ocomsoft/HxComponents/components"
 	"github.com/ocomsoft/HxComponents/examples/testutil"
+	"github.com/ocomsoft/HxComponents/examples/todolist"
 	"github.com/playwright-community/playwright-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -291,3 +294,54 @@ func TestTodoListComponent(t *testing.T) {
 		assert.Equal(t, "text", inputType)
 	})
 }
+
+func TestTodoListRender(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("add item renders the new item", func(t *testing.T) {
+		list := &todolist.TodoListComponent{NewItemText: "Write tests"}
+
+		html, err := components.SimulateAndRender(ctx, list, "addItem")
+		require.NoError(t, err)
+
+		assert.Contains(t, html, "Write tests")
+		assert.Contains(t, html, "Last event: <strong>addItem</strong>")
+	})
+
+	t.Run("toggle item renders completed count", func(t *testing.T) {
+		list := &todolist.TodoListComponent{
+			Items:  []todolist.TodoItem{{ID: 1, Text: "Buy milk"}},
+			ItemID: 1,
+		}
+
+		html, err := components.SimulateAndRender(ctx, list, "toggleItem")
+		require.NoError(t, err)
+
+		assert.True(t, list.Items[0].Completed)
+		assert.Contains(t, html, "<strong>Completed:</strong> 1")
+	})
+
+	t.Run("empty item text returns error without rendering", func(t *testing.T) {
+		list := &todolist.TodoListComponent{}
+
+		html, err := components.SimulateAndRender(ctx, list, "addItem")
+		require.Error(t, err)
+		assert.Empty(t, html)
+	})
+}
+
+func TestTodoListComponentInfo(t *testing.T) {
+	registry := components.NewRegistry()
+	components.Register[*todolist.TodoListComponent](registry, "todolist")
+
+	info, err := registry.GetComponentInfo("todolist")
+	require.NoError(t, err)
+
+	assert.Equal(t, "todolist", info.Name)
+	assert.Equal(t, "todolist.TodoListComponent", info.StructType)
+	assert.ElementsMatch(t, []string{"addItem", "toggleItem", "deleteItem", "clearCompleted"}, info.Events)
+	assert.Contains(t, info.Interfaces, "BeforeEventHandler")
+	assert.Contains(t, info.Interfaces, "AfterEventHandler")
+	assert.Contains(t, info.Interfaces, "Processor")
+	assert.Equal(t, []string{"items", "newItemText", "itemId"}, info.FormFields)
+}